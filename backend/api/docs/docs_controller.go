@@ -0,0 +1,103 @@
+package docs
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"io/fs"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	swaggerFiles "github.com/swaggo/files/v2"
+
+	"github.com/plinphon/StatsBanger/backend/openapi"
+)
+
+// Assets are the swagger-ui files the docs page loads. They are served
+// from the binary, which embeds the swagger-ui release that
+// github.com/swaggo/files/v2 ships, rather than from a CDN.
+var Assets = []string{"swagger-ui.css", "swagger-ui-bundle.js"}
+
+var page = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>StatsBanger API</title>
+<link rel="stylesheet" href="{{.AssetURL}}/swagger-ui.css" integrity="{{index .Integrity "swagger-ui.css"}}" crossorigin="anonymous">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="{{.AssetURL}}/swagger-ui-bundle.js" integrity="{{index .Integrity "swagger-ui-bundle.js"}}" crossorigin="anonymous"></script>
+<script>
+window.ui = SwaggerUIBundle({url: {{.SpecURL}}, dom_id: "#swagger-ui"});
+</script>
+</body>
+</html>
+`))
+
+type DocsController struct {
+	spec   []byte
+	page   []byte
+	assets map[string][]byte
+}
+
+// NewDocsController renders the OpenAPI document and the docs page, which
+// loads the document from specURL and the Assets from assetURL. The page
+// pins each asset with a subresource integrity hash. All of it is fixed
+// for the life of the process. It panics if any of it fails to render,
+// since none of it depends on anything but the code.
+func NewDocsController(specURL, assetURL string) *DocsController {
+	spec, err := json.Marshal(openapi.Spec())
+	if err != nil {
+		panic("docs: " + err.Error())
+	}
+
+	assets := make(map[string][]byte, len(Assets))
+	integrity := make(map[string]string, len(Assets))
+	for _, name := range Assets {
+		data, err := fs.ReadFile(swaggerFiles.FS, name)
+		if err != nil {
+			panic("docs: " + err.Error())
+		}
+		sum := sha512.Sum384(data)
+		assets[name] = data
+		integrity[name] = "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+	}
+
+	var html strings.Builder
+	err = page.Execute(&html, struct {
+		SpecURL, AssetURL string
+		Integrity         map[string]string
+	}{specURL, assetURL, integrity})
+	if err != nil {
+		panic("docs: " + err.Error())
+	}
+	return &DocsController{spec: spec, page: []byte(html.String()), assets: assets}
+}
+
+// GetSpec serves the OpenAPI document.
+func (dc *DocsController) GetSpec(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(dc.spec)
+}
+
+// GetUI serves a Swagger UI page for the OpenAPI document.
+func (dc *DocsController) GetUI(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Send(dc.page)
+}
+
+// GetAsset serves one of the Assets.
+func (dc *DocsController) GetAsset(c *fiber.Ctx) error {
+	name := c.Params("asset")
+	data, ok := dc.assets[name]
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, "Unknown asset")
+	}
+	c.Set(fiber.HeaderContentType, mime.TypeByExtension(path.Ext(name)))
+	c.Set(fiber.HeaderCacheControl, "public, max-age=86400")
+	return c.Send(data)
+}
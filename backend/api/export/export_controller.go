@@ -0,0 +1,46 @@
+package export
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+)
+
+type ExportController struct {
+	service *ExportService
+}
+
+func NewExportController(service *ExportService) *ExportController {
+	return &ExportController{service: service}
+}
+
+// GetTable sends a season of a stat table as a file download, in the
+// format named by the route's extension.
+func (ec *ExportController) GetTable(c *fiber.Ctx) error {
+	table := c.Params("table")
+	format, err := LookupFormat(c.Params("format"))
+	if err != nil {
+		return err
+	}
+
+	uniqueTournamentID, err := strconv.Atoi(c.Query("uniqueTournamentID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid uniqueTournamentID")
+	}
+	seasonID, err := strconv.Atoi(c.Query("seasonID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid seasonID")
+	}
+
+	t, err := ec.service.GetTable(c.UserContext(), table, uniqueTournamentID, seasonID)
+	if err != nil {
+		return apperr.Wrap(err, "Failed to export "+table)
+	}
+
+	// Attachment guesses a content type from the extension; set ours after.
+	c.Attachment(table + "." + format.Name)
+	c.Set(fiber.HeaderContentType, format.MIME)
+	return format.Write(c, t)
+}
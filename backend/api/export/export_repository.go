@@ -0,0 +1,73 @@
+package export
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/models"
+	"github.com/plinphon/StatsBanger/backend/statquery"
+)
+
+// ExportRepository reads whole seasons of the stat tables, each row with
+// its player, team and match.
+type ExportRepository struct {
+	db *gorm.DB
+}
+
+func NewExportRepository(db *gorm.DB) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+func seasonFilters(uniqueTournamentId int, seasonId int) []statquery.Filter {
+	return []statquery.Filter{
+		statquery.Eq("unique_tournament_id", uniqueTournamentId),
+		statquery.Eq("season_id", seasonId),
+	}
+}
+
+// seasonMatchIds returns the IDs of the matches of a season.
+func (r *ExportRepository) seasonMatchIds(ctx context.Context, uniqueTournamentId int, seasonId int) ([]int, error) {
+	var ids []int
+	err := r.db.WithContext(ctx).
+		Model(&models.Match{}).
+		Where("unique_tournament_id = ? AND season_id = ?", uniqueTournamentId, seasonId).
+		Pluck("match_id", &ids).Error
+	return ids, err
+}
+
+func (r *ExportRepository) GetPlayerSeasonStats(ctx context.Context, uniqueTournamentId int, seasonId int) ([]*models.PlayerSeasonStat, error) {
+	return statquery.Find(r.db.WithContext(ctx), statquery.PlayerSeasonStats, statquery.Query{
+		Filters: seasonFilters(uniqueTournamentId, seasonId),
+	})
+}
+
+func (r *ExportRepository) GetTeamSeasonStats(ctx context.Context, uniqueTournamentId int, seasonId int) ([]*models.TeamSeasonStat, error) {
+	return statquery.Find(r.db.WithContext(ctx), statquery.TeamSeasonStats, statquery.Query{
+		Filters: seasonFilters(uniqueTournamentId, seasonId),
+	})
+}
+
+// GetPlayerMatchStats returns the stats of every player in every match of
+// a season, ordered by match.
+func (r *ExportRepository) GetPlayerMatchStats(ctx context.Context, uniqueTournamentId int, seasonId int) ([]*models.PlayerMatchStat, error) {
+	matchIds, err := r.seasonMatchIds(ctx, uniqueTournamentId, seasonId)
+	if err != nil || len(matchIds) == 0 {
+		return nil, err
+	}
+	return statquery.Find(r.db.WithContext(ctx), statquery.PlayerMatchStats, statquery.Query{
+		Filters: []statquery.Filter{statquery.In("match_id", matchIds)},
+	})
+}
+
+// GetTeamMatchStats returns the stats of both teams in every match of a
+// season, ordered by match.
+func (r *ExportRepository) GetTeamMatchStats(ctx context.Context, uniqueTournamentId int, seasonId int) ([]*models.TeamMatchStat, error) {
+	matchIds, err := r.seasonMatchIds(ctx, uniqueTournamentId, seasonId)
+	if err != nil || len(matchIds) == 0 {
+		return nil, err
+	}
+	return statquery.Find(r.db.WithContext(ctx), statquery.TeamMatchStats, statquery.Query{
+		Filters: []statquery.Filter{statquery.In("match_id", matchIds)},
+	})
+}
@@ -0,0 +1,106 @@
+package export
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+	"github.com/plinphon/StatsBanger/backend/apperr"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// Tables lists the stat tables that can be exported.
+var Tables = []string{
+	models.PlayerSeasonStats.Table,
+	models.TeamSeasonStats.Table,
+	models.PlayerMatchStats.Table,
+	models.TeamMatchStats.Table,
+}
+
+// Format is a file format tables are exported in.
+type Format struct {
+	// Name is the extension of the format's files.
+	Name  string
+	MIME  string
+	Write func(w io.Writer, t *tabular.Table) error
+}
+
+// Formats lists every export format.
+var Formats = []Format{
+	{"parquet", tabular.MIMEParquet, tabular.WriteParquet},
+	{"arrow", tabular.MIMEArrow, tabular.WriteArrow},
+}
+
+// LookupFormat returns the format named name.
+func LookupFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(name, f.Name) {
+			return f, nil
+		}
+	}
+	return Format{}, apperr.InvalidArgument("unknown export format %q (want parquet or arrow)", name)
+}
+
+var ErrInvalidSeason = apperr.New(apperr.ErrInvalidArgument, "tournament and season Ids must be positive")
+
+// Repository is the storage ExportService reads seasons through.
+type Repository interface {
+	GetPlayerSeasonStats(ctx context.Context, uniqueTournamentId int, seasonId int) ([]*models.PlayerSeasonStat, error)
+	GetTeamSeasonStats(ctx context.Context, uniqueTournamentId int, seasonId int) ([]*models.TeamSeasonStat, error)
+	GetPlayerMatchStats(ctx context.Context, uniqueTournamentId int, seasonId int) ([]*models.PlayerMatchStat, error)
+	GetTeamMatchStats(ctx context.Context, uniqueTournamentId int, seasonId int) ([]*models.TeamMatchStat, error)
+}
+
+type ExportService struct {
+	repo Repository
+}
+
+func NewExportService(repo Repository) *ExportService {
+	return &ExportService{repo: repo}
+}
+
+// CheckTable returns an error unless table is one of Tables.
+func CheckTable(table string) error {
+	for _, t := range Tables {
+		if table == t {
+			return nil
+		}
+	}
+	return apperr.InvalidArgument("unknown table %q (want %s)", table, strings.Join(Tables, ", "))
+}
+
+// GetTable returns every row of a stat table in a season, flattened with
+// the names of its players and teams. A season without rows is not found.
+func (s *ExportService) GetTable(ctx context.Context, table string, uniqueTournamentId int, seasonId int) (*tabular.Table, error) {
+	if err := CheckTable(table); err != nil {
+		return nil, err
+	}
+	if uniqueTournamentId <= 0 || seasonId <= 0 {
+		return nil, ErrInvalidSeason
+	}
+
+	var (
+		rows any
+		err  error
+	)
+	switch table {
+	case models.PlayerSeasonStats.Table:
+		rows, err = s.repo.GetPlayerSeasonStats(ctx, uniqueTournamentId, seasonId)
+	case models.TeamSeasonStats.Table:
+		rows, err = s.repo.GetTeamSeasonStats(ctx, uniqueTournamentId, seasonId)
+	case models.PlayerMatchStats.Table:
+		rows, err = s.repo.GetPlayerMatchStats(ctx, uniqueTournamentId, seasonId)
+	case models.TeamMatchStats.Table:
+		rows, err = s.repo.GetTeamMatchStats(ctx, uniqueTournamentId, seasonId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t := tabular.New(rows)
+	if t.Len() == 0 {
+		return nil, apperr.NotFound("no %s rows for tournament %d, season %d", table, uniqueTournamentId, seasonId)
+	}
+	return t, nil
+}
@@ -0,0 +1,117 @@
+// Package graph serves a GraphQL schema over the same services as the
+// REST API. Nested fields are loaded through per-request dataloaders, so a
+// query costs one database round trip per level of nesting rather than one
+// per row.
+package graph
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+)
+
+//go:embed schema.graphql
+var schema string
+
+const (
+	// maxDepth bounds how deeply a query may nest selections.
+	maxDepth = 8
+	// maxParallelism bounds the resolvers a query runs at once, and with
+	// it how many IDs one dataloader batch can collect.
+	maxParallelism = 256
+)
+
+// Request is the body of a GraphQL request.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+type GraphController struct {
+	schema   *graphql.Schema
+	services *Services
+	maxRows  int
+}
+
+// NewGraphController returns a controller whose requests may resolve at
+// most maxRows stat rows into nested lists, or any number if maxRows is 0.
+func NewGraphController(services *Services, maxRows int) *GraphController {
+	return &GraphController{
+		schema: graphql.MustParseSchema(schema, &Resolver{services},
+			graphql.UseStringDescriptions(),
+			graphql.MaxDepth(maxDepth),
+			graphql.MaxParallelism(maxParallelism),
+		),
+		services: services,
+		maxRows:  maxRows,
+	}
+}
+
+// Execute runs a query given as a JSON body, or for GET requests in the
+// query, operationName and variables parameters. Like any GraphQL server
+// it answers 200 with the errors in the body once the query is parsed.
+func (gc *GraphController) Execute(c *fiber.Ctx) error {
+	var req Request
+	if c.Method() == fiber.MethodGet {
+		req.Query, req.OperationName = c.Query("query"), c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid variables: "+err.Error())
+			}
+		}
+	} else if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+	if req.Query == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "Missing query")
+	}
+
+	loaders := newLoaders(gc.services, gc.maxRows)
+	resp := gc.schema.Exec(withLoaders(c.UserContext(), loaders), req.Query, req.OperationName, req.Variables)
+	if err := loaders.rows.err(); err != nil {
+		// Report the query as too large once, rather than the partial
+		// data and an error from every list that was cut off.
+		resp = &graphql.Response{Errors: []*gqlerrors.QueryError{{Message: err.Error(), ResolverError: err}}}
+	}
+	for _, err := range resp.Errors {
+		if err.ResolverError != nil {
+			classify(c, err)
+		}
+	}
+	return c.JSON(resp)
+}
+
+// classify gives a resolver error a code after its apperr kind and, as
+// middleware.ErrorHandler does for REST, hides the text of internal
+// errors.
+func classify(c *fiber.Ctx, err *gqlerrors.QueryError) {
+	code := "INTERNAL"
+	switch apperr.Kind(err.ResolverError) {
+	case apperr.ErrNotFound:
+		code = "NOT_FOUND"
+	case apperr.ErrInvalidArgument:
+		code = "BAD_USER_INPUT"
+	case apperr.ErrConflict:
+		code = "CONFLICT"
+	default:
+		slog.ErrorContext(c.UserContext(), "graphql resolver failed",
+			"path", err.Path, "error", err.ResolverError)
+		err.Message = "Internal server error"
+		var appErr *apperr.Error
+		if errors.As(err.ResolverError, &appErr) {
+			err.Message = appErr.Message
+		}
+	}
+	if err.Extensions == nil {
+		err.Extensions = make(map[string]interface{})
+	}
+	err.Extensions["code"] = code
+}
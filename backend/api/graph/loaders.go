@@ -0,0 +1,219 @@
+package graph
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/graph-gophers/dataloader"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// id is a dataloader key.
+type id int
+
+func (k id) String() string   { return strconv.Itoa(int(k)) }
+func (k id) Raw() interface{} { return int(k) }
+
+// loader batches the lookups of one resolver field. The IDs requested while
+// a query resolves one level of its selection are fetched together; each
+// ID is fetched at most once per request.
+type loader[V any] struct {
+	l *dataloader.Loader
+}
+
+// newLoader returns a loader whose batches are fetched by fetch, which
+// maps each ID to its value. IDs missing from the map load as the zero V.
+func newLoader[V any](fetch func(ctx context.Context, ids []int) (map[int]V, error)) loader[V] {
+	batch := func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		ids := make([]int, len(keys))
+		for i, key := range keys {
+			ids[i] = key.Raw().(int)
+		}
+		values, err := fetch(ctx, ids)
+
+		results := make([]*dataloader.Result, len(keys))
+		for i, id := range ids {
+			if err != nil {
+				results[i] = &dataloader.Result{Error: err}
+			} else {
+				results[i] = &dataloader.Result{Data: values[id]}
+			}
+		}
+		return results
+	}
+	return loader[V]{dataloader.NewBatchedLoader(batch)}
+}
+
+func (l loader[V]) load(ctx context.Context, key int) (V, error) {
+	v, err := l.l.Load(ctx, id(key))()
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return v.(V), nil
+}
+
+// statKey is a key of a statLoader: the ID whose rows to load and the
+// stats to load into them, comma separated.
+type statKey struct {
+	id     int
+	fields string
+}
+
+func (k statKey) String() string   { return strconv.Itoa(k.id) + ":" + k.fields }
+func (k statKey) Raw() interface{} { return k }
+
+// statLoader is a loader of stat rows that only selects the stats asked
+// for. Each batch fetches the IDs wanting the same stats together.
+type statLoader[V any] struct {
+	l *dataloader.Loader
+}
+
+// newStatLoader returns a statLoader whose batches are fetched by fetch,
+// which maps each ID to its rows with the given stats.
+func newStatLoader[V any](fetch func(ctx context.Context, ids []int, fields []string) (map[int][]V, error)) statLoader[V] {
+	batch := func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		byFields := make(map[string][]int)
+		var order []string
+		for _, key := range keys {
+			k := key.Raw().(statKey)
+			if _, ok := byFields[k.fields]; !ok {
+				order = append(order, k.fields)
+			}
+			byFields[k.fields] = append(byFields[k.fields], k.id)
+		}
+
+		type fetched struct {
+			rows map[int][]V
+			err  error
+		}
+		results := make(map[string]fetched, len(order))
+		for _, fields := range order {
+			rows, err := fetch(ctx, byFields[fields], strings.Split(fields, ","))
+			results[fields] = fetched{rows, err}
+		}
+
+		out := make([]*dataloader.Result, len(keys))
+		for i, key := range keys {
+			k := key.Raw().(statKey)
+			if r := results[k.fields]; r.err != nil {
+				out[i] = &dataloader.Result{Error: r.err}
+			} else {
+				out[i] = &dataloader.Result{Data: r.rows[k.id]}
+			}
+		}
+		return out
+	}
+	return statLoader[V]{dataloader.NewBatchedLoader(batch)}
+}
+
+// load returns the rows of id with the given stats, which must be valid.
+func (l statLoader[V]) load(ctx context.Context, id int, fields []string) ([]V, error) {
+	fields = slices.Clone(fields)
+	slices.Sort(fields)
+	v, err := l.l.Load(ctx, statKey{id, strings.Join(slices.Compact(fields), ",")})()
+	if err != nil {
+		return nil, err
+	}
+	rows, _ := v.([]V)
+	return rows, nil
+}
+
+// budget counts the rows a request resolves into nested lists.
+type budget struct {
+	max  int64
+	used atomic.Int64
+}
+
+// spend counts n rows and fails once the request has used more than its
+// budget; a max of 0 allows any number.
+func (b *budget) spend(n int) error {
+	if b.max > 0 && b.used.Add(int64(n)) > b.max {
+		return b.err()
+	}
+	return nil
+}
+
+// err returns the error of a request over its budget, or nil.
+func (b *budget) err() error {
+	if b.max > 0 && b.used.Load() > b.max {
+		return apperr.InvalidArgument("query resolves more than %d stat rows; select fewer nested lists", b.max)
+	}
+	return nil
+}
+
+// loaders are the loaders of one request, so that nothing is cached
+// across requests.
+type loaders struct {
+	rows *budget
+
+	teams   loader[*models.Team]
+	players loader[*models.Player]
+
+	playerStatsByMatch  statLoader[*models.PlayerMatchStat]
+	playerStatsByPlayer statLoader[*models.PlayerMatchStat]
+	teamStatsByMatch    statLoader[*models.TeamMatchStat]
+	teamStatsByTeam     statLoader[*models.TeamMatchStat]
+}
+
+func newLoaders(s *Services, maxRows int) *loaders {
+	return &loaders{
+		rows: &budget{max: int64(maxRows)},
+
+		teams: newLoader(func(ctx context.Context, ids []int) (map[int]*models.Team, error) {
+			teams, err := s.Teams.GetTeamsByIDs(ctx, ids)
+			return index(teams, func(t *models.Team) int { return t.TeamId }), err
+		}),
+		players: newLoader(func(ctx context.Context, ids []int) (map[int]*models.Player, error) {
+			players, err := s.Players.GetPlayersByIDs(ctx, ids)
+			return index(players, func(p *models.Player) int { return p.PlayerId }), err
+		}),
+		playerStatsByMatch: newStatLoader(func(ctx context.Context, ids []int, fields []string) (map[int][]*models.PlayerMatchStat, error) {
+			stats, err := s.PlayerMatchStats.GetStatsByMatchIDs(ctx, ids, fields)
+			return group(stats, func(s *models.PlayerMatchStat) int { return s.MatchId }), err
+		}),
+		playerStatsByPlayer: newStatLoader(func(ctx context.Context, ids []int, fields []string) (map[int][]*models.PlayerMatchStat, error) {
+			stats, err := s.PlayerMatchStats.GetStatsByPlayerIDs(ctx, ids, fields)
+			return group(stats, func(s *models.PlayerMatchStat) int { return s.PlayerId }), err
+		}),
+		teamStatsByMatch: newStatLoader(func(ctx context.Context, ids []int, fields []string) (map[int][]*models.TeamMatchStat, error) {
+			stats, err := s.TeamMatchStats.GetStatsByMatchIDs(ctx, ids, fields)
+			return group(stats, func(s *models.TeamMatchStat) int { return s.MatchId }), err
+		}),
+		teamStatsByTeam: newStatLoader(func(ctx context.Context, ids []int, fields []string) (map[int][]*models.TeamMatchStat, error) {
+			stats, err := s.TeamMatchStats.GetStatsByTeamIDs(ctx, ids, fields)
+			return group(stats, func(s *models.TeamMatchStat) int { return s.TeamId }), err
+		}),
+	}
+}
+
+func index[V any](values []V, key func(V) int) map[int]V {
+	m := make(map[int]V, len(values))
+	for _, v := range values {
+		m[key(v)] = v
+	}
+	return m
+}
+
+func group[V any](values []V, key func(V) int) map[int][]V {
+	m := make(map[int][]V)
+	for _, v := range values {
+		m[key(v)] = append(m[key(v)], v)
+	}
+	return m
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
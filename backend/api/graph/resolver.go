@@ -0,0 +1,190 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+	"github.com/plinphon/StatsBanger/backend/models"
+
+	matches "github.com/plinphon/StatsBanger/backend/api/matches"
+
+	team "github.com/plinphon/StatsBanger/backend/api/team/info"
+	teamMatchStat "github.com/plinphon/StatsBanger/backend/api/team/match"
+	teamSeasonStat "github.com/plinphon/StatsBanger/backend/api/team/season"
+
+	player "github.com/plinphon/StatsBanger/backend/api/player/info"
+	playerMatchStat "github.com/plinphon/StatsBanger/backend/api/player/match"
+	playerSeasonStat "github.com/plinphon/StatsBanger/backend/api/player/season"
+)
+
+// Services are what the resolvers read through.
+type Services struct {
+	Matches *matches.MatchService
+
+	Teams           *team.TeamService
+	TeamMatchStats  *teamMatchStat.TeamMatchStatService
+	TeamSeasonStats *teamSeasonStat.TeamSeasonStatService
+
+	Players           *player.PlayerService
+	PlayerMatchStats  *playerMatchStat.PlayerMatchStatService
+	PlayerSeasonStats *playerSeasonStat.PlayerSeasonStatService
+}
+
+// Resolver resolves the Query type.
+type Resolver struct {
+	s *Services
+}
+
+func (r *Resolver) Match(ctx context.Context, args struct{ ID int32 }) (*matchResolver, error) {
+	match, err := r.s.Matches.GetMatchById(ctx, int(args.ID))
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to get match")
+	}
+	return &matchResolver{match}, nil
+}
+
+func (r *Resolver) Matches(ctx context.Context, args struct{ TeamID int32 }) ([]*matchResolver, error) {
+	matches, err := r.s.Matches.GetMatchesByTeamId(ctx, int(args.TeamID))
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to get matches")
+	}
+	resolvers := make([]*matchResolver, len(matches))
+	for i := range matches {
+		resolvers[i] = &matchResolver{&matches[i]}
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) Team(ctx context.Context, args struct{ ID int32 }) (*teamResolver, error) {
+	team, err := r.s.Teams.GetTeamByID(ctx, int(args.ID))
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to get team")
+	}
+	return &teamResolver{team}, nil
+}
+
+func (r *Resolver) Teams(ctx context.Context, args struct{ Name string }) ([]*teamResolver, error) {
+	teams, err := r.s.Teams.SearchTeamsByName(ctx, args.Name)
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to search teams")
+	}
+	return resolve(teams, func(t *models.Team) *teamResolver { return &teamResolver{t} }), nil
+}
+
+func (r *Resolver) Player(ctx context.Context, args struct{ ID int32 }) (*playerResolver, error) {
+	player, err := r.s.Players.GetPlayerByID(ctx, int(args.ID))
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to get player")
+	}
+	return &playerResolver{player}, nil
+}
+
+func (r *Resolver) Players(ctx context.Context, args struct{ Name string }) ([]*playerResolver, error) {
+	players, err := r.s.Players.SearchPlayersByName(ctx, args.Name)
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to search players")
+	}
+	return resolve(players, func(p *models.Player) *playerResolver { return &playerResolver{p} }), nil
+}
+
+func (r *Resolver) PlayerSeasonStats(ctx context.Context, args struct {
+	UniqueTournamentID int32
+	SeasonID           int32
+	PlayerIDs          *[]int32
+}) ([]*playerSeasonStatResolver, error) {
+	stats, err := r.s.PlayerSeasonStats.GetPlayerStatsWithMeta(ctx, nil,
+		int(args.UniqueTournamentID), int(args.SeasonID), ints(args.PlayerIDs))
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to get player stats")
+	}
+	return resolve(stats, func(s *models.PlayerSeasonStat) *playerSeasonStatResolver {
+		return &playerSeasonStatResolver{s, statValues{models.PlayerSeasonStats, s.Stats, nil, nil}}
+	}), nil
+}
+
+func (r *Resolver) TeamSeasonStats(ctx context.Context, args struct {
+	UniqueTournamentID int32
+	SeasonID           int32
+	TeamIDs            *[]int32
+}) ([]*teamSeasonStatResolver, error) {
+	stats, err := r.s.TeamSeasonStats.GetTeamStatsWithMeta(ctx, nil,
+		int(args.UniqueTournamentID), int(args.SeasonID), ints(args.TeamIDs))
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to get team stats")
+	}
+	return resolve(stats, func(s *models.TeamSeasonStat) *teamSeasonStatResolver {
+		return &teamSeasonStatResolver{s, statValues{models.TeamSeasonStats, s.Stats, nil, nil}}
+	}), nil
+}
+
+func (r *Resolver) TopPlayers(ctx context.Context, args struct {
+	Stat               string
+	UniqueTournamentID int32
+	SeasonID           int32
+	Limit              int32
+	Position           string
+}) ([]*topPlayerResolver, error) {
+	if args.Limit < 0 {
+		return nil, apperr.InvalidArgument("invalid limit: %d", args.Limit)
+	}
+	top, err := r.s.PlayerSeasonStats.GetTopPlayersByStat(ctx, args.Stat,
+		int(args.UniqueTournamentID), int(args.SeasonID), int(args.Limit), args.Position)
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to get top players")
+	}
+	resolvers := make([]*topPlayerResolver, len(top))
+	for i := range top {
+		resolvers[i] = &topPlayerResolver{&top[i]}
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) TopTeams(ctx context.Context, args struct {
+	Stat               string
+	UniqueTournamentID int32
+	SeasonID           int32
+	Limit              int32
+}) ([]*topTeamResolver, error) {
+	if args.Limit < 0 {
+		return nil, apperr.InvalidArgument("invalid limit: %d", args.Limit)
+	}
+	top, err := r.s.TeamSeasonStats.GetTopTeamsByStat(ctx, args.Stat,
+		int(args.UniqueTournamentID), int(args.SeasonID), int(args.Limit))
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to get top teams")
+	}
+	resolvers := make([]*topTeamResolver, len(top))
+	for i := range top {
+		resolvers[i] = &topTeamResolver{&top[i]}
+	}
+	return resolvers, nil
+}
+
+func resolve[M, R any](rows []M, resolver func(M) R) []R {
+	resolvers := make([]R, len(rows))
+	for i, row := range rows {
+		resolvers[i] = resolver(row)
+	}
+	return resolvers
+}
+
+func ints(ids *[]int32) []int {
+	if ids == nil {
+		return nil
+	}
+	out := make([]int, len(*ids))
+	for i, id := range *ids {
+		out[i] = int(id)
+	}
+	return out
+}
@@ -0,0 +1,361 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+type matchResolver struct {
+	m *models.Match
+}
+
+func (r *matchResolver) ID() int32                 { return int32(r.m.Id) }
+func (r *matchResolver) UniqueTournamentID() int32 { return int32(r.m.UniqueTournamentId) }
+func (r *matchResolver) SeasonID() int32           { return int32(r.m.SeasonId) }
+func (r *matchResolver) Matchday() int32           { return int32(r.m.Matchday) }
+func (r *matchResolver) HomeWin() *int32           { return int32Ptr(r.m.HomeWin) }
+func (r *matchResolver) HomeScore() *int32         { return int32Ptr(r.m.HomeScore) }
+func (r *matchResolver) AwayScore() *int32         { return int32Ptr(r.m.AwayScore) }
+func (r *matchResolver) InjuryTime1() *int32       { return int32Ptr(r.m.InjuryTime1) }
+func (r *matchResolver) InjuryTime2() *int32       { return int32Ptr(r.m.InjuryTime2) }
+
+func (r *matchResolver) CurrentPeriodStartTimestamp() string {
+	return r.m.CurrentPeriodStartTimestamp.Format(time.RFC3339)
+}
+
+func (r *matchResolver) HomeTeam(ctx context.Context) (*teamResolver, error) {
+	return teamOf(ctx, &r.m.HomeTeam, r.m.HomeTeamId)
+}
+
+func (r *matchResolver) AwayTeam(ctx context.Context) (*teamResolver, error) {
+	return teamOf(ctx, &r.m.AwayTeam, r.m.AwayTeamId)
+}
+
+func (r *matchResolver) TeamStats(ctx context.Context) ([]*teamMatchStatResolver, error) {
+	return teamMatchStats(ctx, loadersFrom(ctx).teamStatsByMatch, r.m.Id)
+}
+
+func (r *matchResolver) PlayerStats(ctx context.Context) ([]*playerMatchStatResolver, error) {
+	return playerMatchStats(ctx, loadersFrom(ctx).playerStatsByMatch, r.m.Id)
+}
+
+// matchOf resolves a joined match, which is empty if the match is missing.
+func matchOf(m *models.Match) *matchResolver {
+	if m == nil || m.Id == 0 {
+		return nil
+	}
+	return &matchResolver{m}
+}
+
+type teamResolver struct {
+	t *models.Team
+}
+
+func (r *teamResolver) ID() int32           { return int32(r.t.TeamId) }
+func (r *teamResolver) Name() string        { return r.t.TeamName }
+func (r *teamResolver) HomeStadium() string { return r.t.HomeStadium }
+
+func (r *teamResolver) MatchStats(ctx context.Context) ([]*teamMatchStatResolver, error) {
+	return teamMatchStats(ctx, loadersFrom(ctx).teamStatsByTeam, r.t.TeamId)
+}
+
+// teamOf resolves the team with the given ID, using loaded if the query
+// that produced it already joined the team. It resolves to null if there
+// is no such team.
+func teamOf(ctx context.Context, loaded *models.Team, teamID int) (*teamResolver, error) {
+	if loaded != nil && loaded.TeamId == teamID && teamID != 0 {
+		return &teamResolver{loaded}, nil
+	}
+	team, err := loadersFrom(ctx).teams.load(ctx, teamID)
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to get team")
+	}
+	if team == nil {
+		return nil, nil
+	}
+	return &teamResolver{team}, nil
+}
+
+type playerResolver struct {
+	p *models.Player
+}
+
+func (r *playerResolver) ID() int32             { return int32(r.p.PlayerId) }
+func (r *playerResolver) Name() string          { return r.p.PlayerName }
+func (r *playerResolver) Age() int32            { return int32(r.p.Age) }
+func (r *playerResolver) Position() string      { return r.p.Position }
+func (r *playerResolver) Height() float64       { return r.p.Height }
+func (r *playerResolver) PreferredFoot() string { return r.p.PreferredFoot }
+func (r *playerResolver) Nationality() string   { return r.p.Nationality }
+
+func (r *playerResolver) BirthdayTimestamp() string {
+	return r.p.Birthday.Format(time.RFC3339)
+}
+
+func (r *playerResolver) MatchStats(ctx context.Context) ([]*playerMatchStatResolver, error) {
+	return playerMatchStats(ctx, loadersFrom(ctx).playerStatsByPlayer, r.p.PlayerId)
+}
+
+// playerOf resolves the player with the given ID, using loaded if the
+// query that produced it already joined the player. It resolves to null if
+// there is no such player.
+func playerOf(ctx context.Context, loaded *models.Player, playerID int) (*playerResolver, error) {
+	if loaded != nil && loaded.PlayerId == playerID && playerID != 0 {
+		return &playerResolver{loaded}, nil
+	}
+	player, err := loadersFrom(ctx).players.load(ctx, playerID)
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to get player")
+	}
+	if player == nil {
+		return nil, nil
+	}
+	return &playerResolver{player}, nil
+}
+
+// statValues resolves the stats and stat fields shared by every stat type.
+type statValues struct {
+	registry *models.StatRegistry
+	values   map[string]*float64
+	// loaded lists the stats that values was loaded with, and reload
+	// fetches the row again for others. Both are nil if values holds every
+	// stat.
+	loaded []string
+	reload func(ctx context.Context, fields []string) (map[string]*float64, error)
+}
+
+func (v statValues) Stats(ctx context.Context, args struct{ Fields *[]string }) ([]*statResolver, error) {
+	fields := v.registry.Fields()
+	if args.Fields != nil {
+		fields = *args.Fields
+	}
+	values, err := v.lookup(ctx, fields)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]*statResolver, len(fields))
+	for i, field := range fields {
+		stats[i] = &statResolver{field, values[field]}
+	}
+	return stats, nil
+}
+
+func (v statValues) Stat(ctx context.Context, args struct{ Field string }) (*float64, error) {
+	values, err := v.lookup(ctx, []string{args.Field})
+	if err != nil {
+		return nil, err
+	}
+	return values[args.Field], nil
+}
+
+// lookup returns the values of fields, reloading the row if some of them
+// were not loaded with it.
+func (v statValues) lookup(ctx context.Context, fields []string) (map[string]*float64, error) {
+	if err := checkStats(v.registry, fields); err != nil {
+		return nil, err
+	}
+	if v.reload == nil {
+		return v.values, nil
+	}
+	for _, field := range fields {
+		if !slices.Contains(v.loaded, field) {
+			return v.reload(ctx, fields)
+		}
+	}
+	return v.values, nil
+}
+
+func checkStats(registry *models.StatRegistry, fields []string) error {
+	for _, field := range fields {
+		if !registry.Has(field) {
+			return fmt.Errorf("%w: %s", models.ErrInvalidStatField, field)
+		}
+	}
+	return nil
+}
+
+// selectedStats returns the stats that the stats and stat fields selected
+// below the current field ask for, to load only those. The selection
+// only has the arguments of the first of several aliased fields; statValues
+// reloads the rows for the stats the others ask for.
+func selectedStats(ctx context.Context, registry *models.StatRegistry) ([]string, error) {
+	var fields []string
+	if graphql.HasSelectedField(ctx, "stats") {
+		var args struct{ Fields *[]string }
+		if _, err := graphql.DecodeSelectedFieldArgs(ctx, "stats", &args); err != nil {
+			return nil, err
+		}
+		if args.Fields == nil {
+			return registry.Fields(), nil
+		}
+		fields = append(fields, *args.Fields...)
+	}
+	if graphql.HasSelectedField(ctx, "stat") {
+		var args struct{ Field string }
+		if _, err := graphql.DecodeSelectedFieldArgs(ctx, "stat", &args); err != nil {
+			return nil, err
+		}
+		fields = append(fields, args.Field)
+	}
+	return fields, checkStats(registry, fields)
+}
+
+type statResolver struct {
+	field string
+	value *float64
+}
+
+func (r *statResolver) Field() string   { return r.field }
+func (r *statResolver) Value() *float64 { return r.value }
+
+type playerMatchStatResolver struct {
+	s *models.PlayerMatchStat
+	statValues
+}
+
+// playerMatchStats resolves the player match stats of id, loading the
+// stats selected below the current field.
+func playerMatchStats(ctx context.Context, l statLoader[*models.PlayerMatchStat], id int) ([]*playerMatchStatResolver, error) {
+	fields, err := selectedStats(ctx, models.PlayerMatchStats)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := l.load(ctx, id, fields)
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to get player stats")
+	}
+	if err := loadersFrom(ctx).rows.spend(len(stats)); err != nil {
+		return nil, err
+	}
+	return resolve(stats, func(s *models.PlayerMatchStat) *playerMatchStatResolver {
+		reload := func(ctx context.Context, fields []string) (map[string]*float64, error) {
+			rows, err := l.load(ctx, id, fields)
+			if err != nil {
+				return nil, apperr.Wrap(err, "Failed to get player stats")
+			}
+			for _, row := range rows {
+				if row.MatchId == s.MatchId && row.PlayerId == s.PlayerId {
+					return row.Stats, nil
+				}
+			}
+			return nil, nil
+		}
+		return &playerMatchStatResolver{s, statValues{models.PlayerMatchStats, s.Stats, fields, reload}}
+	}), nil
+}
+
+func (r *playerMatchStatResolver) Match() *matchResolver { return matchOf(&r.s.Match) }
+
+func (r *playerMatchStatResolver) Player(ctx context.Context) (*playerResolver, error) {
+	return playerOf(ctx, &r.s.Player, r.s.PlayerId)
+}
+
+func (r *playerMatchStatResolver) Team(ctx context.Context) (*teamResolver, error) {
+	return teamOf(ctx, &r.s.Team, r.s.TeamId)
+}
+
+type teamMatchStatResolver struct {
+	s *models.TeamMatchStat
+	statValues
+}
+
+// teamMatchStats resolves the team match stats of id, loading the stats
+// selected below the current field.
+func teamMatchStats(ctx context.Context, l statLoader[*models.TeamMatchStat], id int) ([]*teamMatchStatResolver, error) {
+	fields, err := selectedStats(ctx, models.TeamMatchStats)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := l.load(ctx, id, fields)
+	if err != nil {
+		return nil, apperr.Wrap(err, "Failed to get team stats")
+	}
+	if err := loadersFrom(ctx).rows.spend(len(stats)); err != nil {
+		return nil, err
+	}
+	return resolve(stats, func(s *models.TeamMatchStat) *teamMatchStatResolver {
+		reload := func(ctx context.Context, fields []string) (map[string]*float64, error) {
+			rows, err := l.load(ctx, id, fields)
+			if err != nil {
+				return nil, apperr.Wrap(err, "Failed to get team stats")
+			}
+			for _, row := range rows {
+				if row.MatchId == s.MatchId && row.TeamId == s.TeamId {
+					return row.Stats, nil
+				}
+			}
+			return nil, nil
+		}
+		return &teamMatchStatResolver{s, statValues{models.TeamMatchStats, s.Stats, fields, reload}}
+	}), nil
+}
+
+func (r *teamMatchStatResolver) Match() *matchResolver { return matchOf(r.s.Match) }
+
+func (r *teamMatchStatResolver) Team(ctx context.Context) (*teamResolver, error) {
+	return teamOf(ctx, &r.s.Team, r.s.TeamId)
+}
+
+type playerSeasonStatResolver struct {
+	s *models.PlayerSeasonStat
+	statValues
+}
+
+func (r *playerSeasonStatResolver) UniqueTournamentID() int32 { return int32(r.s.UniqueTournamentId) }
+func (r *playerSeasonStatResolver) SeasonID() int32           { return int32(r.s.SeasonId) }
+
+func (r *playerSeasonStatResolver) Player(ctx context.Context) (*playerResolver, error) {
+	return playerOf(ctx, &r.s.Player, r.s.PlayerId)
+}
+
+func (r *playerSeasonStatResolver) Team(ctx context.Context) (*teamResolver, error) {
+	return teamOf(ctx, &r.s.Team, r.s.TeamId)
+}
+
+type teamSeasonStatResolver struct {
+	s *models.TeamSeasonStat
+	statValues
+}
+
+func (r *teamSeasonStatResolver) UniqueTournamentID() int32 { return int32(r.s.UniqueTournamentID) }
+func (r *teamSeasonStatResolver) SeasonID() int32           { return int32(r.s.SeasonID) }
+
+func (r *teamSeasonStatResolver) Team(ctx context.Context) (*teamResolver, error) {
+	return teamOf(ctx, &r.s.Team, r.s.TeamID)
+}
+
+type topPlayerResolver struct {
+	t *models.TopPlayerStatResult
+}
+
+func (r *topPlayerResolver) Position() string   { return r.t.Position }
+func (r *topPlayerResolver) StatValue() float64 { return r.t.StatValue }
+
+func (r *topPlayerResolver) Player(ctx context.Context) (*playerResolver, error) {
+	return playerOf(ctx, nil, r.t.PlayerID)
+}
+
+type topTeamResolver struct {
+	t *models.TopTeamStatResult
+}
+
+func (r *topTeamResolver) StatValue() float64 { return r.t.StatValue }
+
+func (r *topTeamResolver) Team(ctx context.Context) (*teamResolver, error) {
+	return teamOf(ctx, nil, r.t.TeamID)
+}
+
+func int32Ptr(p *int) *int32 {
+	if p == nil {
+		return nil
+	}
+	v := int32(*p)
+	return &v
+}
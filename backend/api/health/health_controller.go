@@ -0,0 +1,45 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/database"
+)
+
+// readyTimeout bounds the database checks of one readiness probe.
+const readyTimeout = 2 * time.Second
+
+// Status is the body of the health endpoints.
+type Status struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type HealthController struct {
+	db *gorm.DB
+}
+
+func NewHealthController(db *gorm.DB) *HealthController {
+	return &HealthController{db: db}
+}
+
+// GetLiveness answers as long as the process serves HTTP at all.
+func (hc *HealthController) GetLiveness(c *fiber.Ctx) error {
+	return c.JSON(Status{Status: "ok"})
+}
+
+// GetReadiness answers 200 when the database is reachable and has every
+// core table, and 503 with the reason otherwise.
+func (hc *HealthController) GetReadiness(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), readyTimeout)
+	defer cancel()
+
+	if err := database.Ready(ctx, hc.db); err != nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(Status{Status: "unavailable", Error: err.Error()})
+	}
+	return c.JSON(Status{Status: "ready"})
+}
@@ -2,8 +2,12 @@ package matches
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+	"github.com/plinphon/StatsBanger/backend/apperr"
+	"github.com/plinphon/StatsBanger/backend/models"
 )
 
 type MatchController struct {
@@ -22,26 +26,125 @@ func (mc *MatchController) GetMatchByID(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid match ID")
 	}
 
-	matches, err := mc.service.GetMatchById(matchID)
+	matches, err := mc.service.GetMatchById(c.UserContext(), matchID)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get a match")
+		return apperr.Wrap(err, "Failed to get a match")
 	}
 
 	return c.JSON(matches)
 }
 
-func (mc *MatchController) GetMatchByTeamID(c *fiber.Ctx) error {
-	teamIDStr := c.Query("teamID")
-	teamID, err := strconv.Atoi(teamIDStr)
+// ListMatches serves a page of matches, filtered by the query parameters
+// and ordered by kick-off. How many matches pass the filters, across every
+// page, is in the X-Total-Count header.
+func (mc *MatchController) ListMatches(c *fiber.Ctx) error {
+	var f MatchFilter
+	for name, dst := range map[string]*int{
+		"uniqueTournamentID": &f.UniqueTournamentId,
+		"seasonID":           &f.SeasonId,
+		"matchday":           &f.Matchday,
+		"teamID":             &f.TeamId,
+		"limit":              &f.Limit,
+		"offset":             &f.Offset,
+	} {
+		if s := c.Query(name); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid "+name)
+			}
+			*dst = n
+		}
+	}
+	f.Side = c.Query("side")
+
+	var err error
+	if f.From, err = parseDate(c.Query("from"), false); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid from")
+	}
+	if f.Before, err = parseDate(c.Query("to"), true); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid to")
+	}
+	if s := c.Query("played"); s != "" {
+		played, err := strconv.ParseBool(s)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid played")
+		}
+		f.Played = &played
+	}
+	switch c.Query("order", "asc") {
+	case "asc":
+	case "desc":
+		f.Descending = true
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid order")
+	}
 
+	matches, total, err := mc.service.ListMatches(c.UserContext(), f)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid team ID")
+		return apperr.Wrap(err, "Failed to get matches")
 	}
 
-	matches, err := mc.service.GetMatchesByTeamId(teamID)
+	c.Set(tabular.HeaderTotalCount, strconv.FormatInt(total, 10))
+	return tabular.Send(c, "matches", matches)
+}
+
+// parseDate parses a query parameter holding a date, 2024-05-26, or an
+// RFC 3339 time. A date ends a range at the end of its day when end is
+// set; a time is inclusive either way. Empty is the zero time.
+func parseDate(s string, end bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		if end {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get matches")
+		return time.Time{}, err
+	}
+	if end {
+		// Kick-offs are whole seconds.
+		t = t.Truncate(time.Second).Add(time.Second)
+	}
+	return t, nil
+}
+
+func (mc *MatchController) CreateMatch(c *fiber.Ctx) error {
+	var match models.Match
+	if err := c.BodyParser(&match); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid match body")
 	}
 
-	return c.JSON(matches)
-}
\ No newline at end of file
+	saved, err := mc.service.CreateMatch(c.UserContext(), match)
+	if err != nil {
+		return apperr.Wrap(err, "Failed to save match")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(saved)
+}
+
+func (mc *MatchController) UpsertMatch(c *fiber.Ctx) error {
+	matchID, err := strconv.Atoi(c.Params("matchID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid match ID")
+	}
+
+	var match models.Match
+	if err := c.BodyParser(&match); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid match body")
+	}
+	if match.Id != 0 && match.Id != matchID {
+		return fiber.NewError(fiber.StatusBadRequest, "Match ID in body does not match URL")
+	}
+	match.Id = matchID
+
+	saved, err := mc.service.UpsertMatch(c.UserContext(), match)
+	if err != nil {
+		return apperr.Wrap(err, "Failed to save match")
+	}
+
+	return c.JSON(saved)
+}
@@ -1,66 +1,52 @@
 package matches
 
 import (
+	"context"
 	"errors"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"github.com/plinphon/StatsBanger/backend/apperr"
 	"github.com/plinphon/StatsBanger/backend/models"
 )
 
-var ErrMatchNotFound = errors.New("match not found")
+var ErrMatchNotFound = apperr.New(apperr.ErrNotFound, "match not found")
 
 type MatchRepository struct {
 	db *gorm.DB
 }
 
-func NewMatchRepository(dbPath string) (*MatchRepository, error) {
-	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
-	if err != nil {
-		return nil, err
-	}
+func NewMatchRepository(db *gorm.DB) *MatchRepository {
+	return &MatchRepository{db: db}
+}
 
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, err
+func (r *MatchRepository) Create(ctx context.Context, match models.Match) error {
+	err := r.db.WithContext(ctx).Omit("HomeTeam", "AwayTeam").Create(&match).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrDuplicateMatch
 	}
-	if err = sqlDB.Ping(); err != nil {
-		return nil, err
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return ErrUnknownReference
 	}
-
-	return &MatchRepository{db: db}, nil
+	return err
 }
-/*
-
-func (r *MatchRepository) Create(match models.Match) error {
-	query := `
-	INSERT INTO match_info (
-		match_id, unique_tournament_id, season_id, matchday, 
-		home_team_id, away_team_id, home_win, home_score, 
-		away_score, injury_time1, injury_time2, current_period_start_timestamp
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-
-	_,err := r.db.Exec(query,
-		match.Id,
-		match.UniqueTournamentId,
-		match.SeasonId,
-		match.Matchday,
-		match.HomeTeamId,
-		match.AwayTeamId,
-		match.HomeWin,
-		match.HomeScore,
-		match.AwayScore,
-		match.InjuryTime1,
-		match.InjuryTime2,
-		match.CurrentPeriodStartTimestamp,
-	)
 
+// Upsert inserts match, or overwrites every column of the existing match
+// with the same ID.
+func (r *MatchRepository) Upsert(ctx context.Context, match models.Match) error {
+	err := r.db.WithContext(ctx).
+		Omit("HomeTeam", "AwayTeam").
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&match).Error
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return ErrUnknownReference
+	}
 	return err
-}*/
+}
 
-func (r *MatchRepository) GetById(matchId int) (*models.Match, error) {
+func (r *MatchRepository) GetById(ctx context.Context, matchId int) (*models.Match, error) {
 	var match models.Match
-	err := r.db.
+	err := r.db.WithContext(ctx).
 		Preload("HomeTeam").
 		Preload("AwayTeam").
 		First(&match, "match_id = ?", matchId).Error
@@ -72,10 +58,10 @@ func (r *MatchRepository) GetById(matchId int) (*models.Match, error) {
 	return &match, err
 }
 
-func (r *MatchRepository) GetByTeamId(teamId int) ([]models.Match, error) {
+func (r *MatchRepository) GetByTeamId(ctx context.Context, teamId int) ([]models.Match, error) {
 	var matches []models.Match
 
-	err := r.db.
+	err := r.db.WithContext(ctx).
 		Preload("HomeTeam").
 		Preload("AwayTeam").
 		Where("home_team_id = ? OR away_team_id = ?", teamId, teamId).
@@ -88,3 +74,65 @@ func (r *MatchRepository) GetByTeamId(teamId int) ([]models.Match, error) {
 	return matches, nil
 }
 
+
+// List returns the page of matches that pass f, ordered by kick-off, and
+// how many pass it in all.
+func (r *MatchRepository) List(ctx context.Context, f MatchFilter) ([]models.Match, int64, error) {
+	tx := r.db.WithContext(ctx).Model(&models.Match{})
+	if f.UniqueTournamentId > 0 {
+		tx = tx.Where("unique_tournament_id = ?", f.UniqueTournamentId)
+	}
+	if f.SeasonId > 0 {
+		tx = tx.Where("season_id = ?", f.SeasonId)
+	}
+	if f.Matchday > 0 {
+		tx = tx.Where("matchday = ?", f.Matchday)
+	}
+	if f.TeamId > 0 {
+		switch f.Side {
+		case SideHome:
+			tx = tx.Where("home_team_id = ?", f.TeamId)
+		case SideAway:
+			tx = tx.Where("away_team_id = ?", f.TeamId)
+		default:
+			tx = tx.Where("home_team_id = ? OR away_team_id = ?", f.TeamId, f.TeamId)
+		}
+	}
+	// The timestamps are stored as Unix seconds.
+	if !f.From.IsZero() {
+		tx = tx.Where("current_period_start_timestamp >= ?", f.From.Unix())
+	}
+	if !f.Before.IsZero() {
+		tx = tx.Where("current_period_start_timestamp < ?", f.Before.Unix())
+	}
+	if f.Played != nil {
+		if *f.Played {
+			tx = tx.Where("home_score IS NOT NULL")
+		} else {
+			tx = tx.Where("home_score IS NULL")
+		}
+	}
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	direction := "ASC"
+	if f.Descending {
+		direction = "DESC"
+	}
+	var matches []models.Match
+	err := tx.
+		Preload("HomeTeam").
+		Preload("AwayTeam").
+		Order("current_period_start_timestamp " + direction).
+		Order("match_id " + direction).
+		Limit(f.Limit).
+		Offset(f.Offset).
+		Find(&matches).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return matches, total, nil
+}
@@ -1,43 +1,148 @@
 package matches
 
 import (
+	"context"
+	"github.com/plinphon/StatsBanger/backend/apperr"
 	"github.com/plinphon/StatsBanger/backend/models"
-	"errors"
+	"time"
 )
 
 var (
-	ErrDuplicateMatch     = errors.New("match Id already exists")
-	ErrInvalidTeamIds     = errors.New("home and away teams cannot be the same")
+	ErrDuplicateMatch     = apperr.New(apperr.ErrConflict, "match Id already exists")
+	ErrInvalidTeamIds     = apperr.New(apperr.ErrInvalidArgument, "home and away teams cannot be the same")
+	ErrInvalidMatch       = apperr.New(apperr.ErrInvalidArgument, "match, tournament, season and team Ids must be positive")
+	ErrUnknownReference   = apperr.New(apperr.ErrInvalidArgument, "the tournament, season and teams of a match must exist")
 )
 
+// Repository is the storage MatchService reads and writes matches through.
+type Repository interface {
+	GetById(ctx context.Context, matchId int) (*models.Match, error)
+	GetByTeamId(ctx context.Context, teamId int) ([]models.Match, error)
+	List(ctx context.Context, f MatchFilter) ([]models.Match, int64, error)
+	Create(ctx context.Context, match models.Match) error
+	Upsert(ctx context.Context, match models.Match) error
+}
+
 type MatchService struct {
-	repo *MatchRepository
+	repo Repository
 }
 
-func NewMatchService(repo *MatchRepository) *MatchService {
+func NewMatchService(repo Repository) *MatchService {
     return &MatchService{repo: repo}
 }
-/*
-func (s *MatchService) CreateMatch(match models.Match) error {
-	if match.HomeTeamId == match.AwayTeamId {
-		return ErrInvalidTeamIds
+
+// CreateMatch saves a new match and returns it as saved, with the kick-off
+// defaulting to now.
+func (s *MatchService) CreateMatch(ctx context.Context, match models.Match) (*models.Match, error) {
+	if err := validateMatch(match); err != nil {
+		return nil, err
+	}
+
+	if match.CurrentPeriodStartTimestamp.IsZero() {
+		match.CurrentPeriodStartTimestamp = time.Now()
 	}
+	// The column holds Unix seconds.
+	match.CurrentPeriodStartTimestamp = match.CurrentPeriodStartTimestamp.Truncate(time.Second)
 
-	if existing, _ := s.repo.GetById(match.Id); existing != nil {
-		return ErrDuplicateMatch
+	if err := s.repo.Create(ctx, match); err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// UpsertMatch saves match over any with its ID and returns it as saved,
+// with the kick-off defaulting to now.
+func (s *MatchService) UpsertMatch(ctx context.Context, match models.Match) (*models.Match, error) {
+	if err := validateMatch(match); err != nil {
+		return nil, err
 	}
 
 	if match.CurrentPeriodStartTimestamp.IsZero() {
 		match.CurrentPeriodStartTimestamp = time.Now()
 	}
+	// The column holds Unix seconds.
+	match.CurrentPeriodStartTimestamp = match.CurrentPeriodStartTimestamp.Truncate(time.Second)
 
-	return s.repo.Create(match)
+	if err := s.repo.Upsert(ctx, match); err != nil {
+		return nil, err
+	}
+	return &match, nil
 }
-*/
-func (s *MatchService) GetMatchById(matchId int) (*models.Match, error) {
-	return s.repo.GetById(matchId)
+
+func validateMatch(match models.Match) error {
+	if match.Id <= 0 || match.UniqueTournamentId <= 0 || match.SeasonId <= 0 ||
+		match.HomeTeamId <= 0 || match.AwayTeamId <= 0 {
+		return ErrInvalidMatch
+	}
+	if match.HomeTeamId == match.AwayTeamId {
+		return ErrInvalidTeamIds
+	}
+	return nil
 }
 
-func (s *MatchService) GetMatchesByTeamId(teamId int) ([]models.Match, error) {
-	return s.repo.GetByTeamId(teamId)
-}
\ No newline at end of file
+func (s *MatchService) GetMatchById(ctx context.Context, matchId int) (*models.Match, error) {
+	return s.repo.GetById(ctx, matchId)
+}
+
+func (s *MatchService) GetMatchesByTeamId(ctx context.Context, teamId int) ([]models.Match, error) {
+	return s.repo.GetByTeamId(ctx, teamId)
+}
+
+// Page sizes of ListMatches.
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// Sides a team can play on in a MatchFilter; SideEither matches both.
+const (
+	SideEither = ""
+	SideHome   = "home"
+	SideAway   = "away"
+)
+
+// MatchFilter selects a page of matches. Zero IDs and times match every
+// match; From is inclusive and Before exclusive.
+type MatchFilter struct {
+	UniqueTournamentId int
+	SeasonId           int
+	Matchday           int
+	TeamId             int
+	Side               string
+	From, Before       time.Time
+	// Played selects matches with or without a score, if set.
+	Played     *bool
+	Descending bool
+	Limit      int
+	Offset     int
+}
+
+// ListMatches returns the page of matches that pass f, ordered by kick-off,
+// and how many pass it in all. A zero limit means DefaultLimit.
+func (s *MatchService) ListMatches(ctx context.Context, f MatchFilter) ([]models.Match, int64, error) {
+	if f.UniqueTournamentId < 0 || f.SeasonId < 0 || f.Matchday < 0 || f.TeamId < 0 {
+		return nil, 0, apperr.InvalidArgument("tournament, season, matchday and team must be positive")
+	}
+	switch f.Side {
+	case SideEither:
+	case SideHome, SideAway:
+		if f.TeamId == 0 {
+			return nil, 0, apperr.InvalidArgument("side %q needs a team", f.Side)
+		}
+	default:
+		return nil, 0, apperr.InvalidArgument("unknown side %q (want home or away)", f.Side)
+	}
+	if !f.From.IsZero() && !f.Before.IsZero() && !f.From.Before(f.Before) {
+		return nil, 0, apperr.InvalidArgument("the date range is empty")
+	}
+	if f.Limit == 0 {
+		f.Limit = DefaultLimit
+	}
+	if f.Limit < 0 || f.Limit > MaxLimit {
+		return nil, 0, apperr.InvalidArgument("invalid limit: %d (want 1 to %d)", f.Limit, MaxLimit)
+	}
+	if f.Offset < 0 {
+		return nil, 0, apperr.InvalidArgument("invalid offset: %d", f.Offset)
+	}
+	return s.repo.List(ctx, f)
+}
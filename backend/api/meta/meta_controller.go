@@ -0,0 +1,71 @@
+package meta
+
+import (
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+	"github.com/plinphon/StatsBanger/backend/database"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// StatMeta is a stat's metadata as served to clients. Min and Max are only
+// set for units with a fixed range, such as percentages.
+type StatMeta struct {
+	models.Stat
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// EntityMeta lists the stats of one entity in display order.
+type EntityMeta struct {
+	Entity string     `json:"entity"`
+	Stats  []StatMeta `json:"stats"`
+}
+
+type MetaController struct {
+	db *gorm.DB
+}
+
+func NewMetaController(db *gorm.DB) *MetaController {
+	return &MetaController{db: db}
+}
+
+// GetStatEntities lists the entities that have stat metadata.
+func (mc *MetaController) GetStatEntities(c *fiber.Ctx) error {
+	return c.JSON(models.StatEntities())
+}
+
+// GetStatsByEntity serves the stat registry of one entity, such as
+// player-season.
+func (mc *MetaController) GetStatsByEntity(c *fiber.Ctx) error {
+	registry, ok := models.StatRegistries[c.Params("entity")]
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, "Unknown stat entity")
+	}
+	return c.JSON(NewEntityMeta(registry))
+}
+
+// NewEntityMeta converts a registry into its served form.
+func NewEntityMeta(registry *models.StatRegistry) EntityMeta {
+	stats := registry.Stats()
+	meta := EntityMeta{Entity: registry.Entity, Stats: make([]StatMeta, len(stats))}
+	for i, s := range stats {
+		meta.Stats[i] = StatMeta{Stat: s}
+		if min, max, ok := s.Bounds(); ok {
+			meta.Stats[i].Min, meta.Stats[i].Max = &min, &max
+		}
+	}
+	return meta
+}
+
+// GetSchemaCheck compares the stat registries with the live database
+// columns, as the server does at startup.
+func (mc *MetaController) GetSchemaCheck(c *fiber.Ctx) error {
+	report, err := database.CheckSchema(mc.db)
+	if err != nil {
+		return apperr.Wrap(err, "Failed to check the schema")
+	}
+	return c.JSON(report)
+}
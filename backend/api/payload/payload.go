@@ -0,0 +1,33 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// DecodeRows decodes a request body holding either a single JSON object or
+// an array of them, so write endpoints accept one row or a batch.
+func DecodeRows[T any](body []byte) ([]T, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, errors.New("empty request body")
+	}
+
+	if body[0] == '[' {
+		var rows []T
+		if err := json.Unmarshal(body, &rows); err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			return nil, errors.New("empty batch")
+		}
+		return rows, nil
+	}
+
+	var row T
+	if err := json.Unmarshal(body, &row); err != nil {
+		return nil, err
+	}
+	return []T{row}, nil
+}
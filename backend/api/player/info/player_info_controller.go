@@ -2,8 +2,10 @@ package info
 
 import (
 	"strconv"
-	"log"
 	"github.com/gofiber/fiber/v2"
+
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+	"github.com/plinphon/StatsBanger/backend/apperr"
 )
 
 type PlayerController struct {
@@ -22,10 +24,9 @@ func (mc *PlayerController) GetPlayerByID(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid player ID")
 	}
 
-	player, err := mc.service.GetPlayerByID(playerID)
+	player, err := mc.service.GetPlayerByID(c.UserContext(), playerID)
 	if err != nil {
-		log.Printf("❌ Error getting player: %v", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get a player")
+		return apperr.Wrap(err, "Failed to get a player")
 	}
 
 	return c.JSON(player)
@@ -34,11 +35,10 @@ func (mc *PlayerController) GetPlayerByID(c *fiber.Ctx) error {
 func (mc *PlayerController) SearchPlayersByName(c *fiber.Ctx) error {
 	playerName := c.Query("name")
 
-	player, err := mc.service.SearchPlayersByName(playerName)
+	player, err := mc.service.SearchPlayersByName(c.UserContext(), playerName)
 	if err != nil {
-		log.Printf("❌ Error getting player: %v", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get a player")
+		return apperr.Wrap(err, "Failed to get a player")
 	}
 
-	return c.JSON(player)
+	return tabular.Send(c, "players", player)
 }
@@ -1,43 +1,48 @@
 package info
 
 import (
+	"context"
 	"errors"
+	"github.com/plinphon/StatsBanger/backend/apperr"
 	"github.com/plinphon/StatsBanger/backend/models"
-	
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
+var ErrPlayerNotFound = apperr.New(apperr.ErrNotFound, "player not found")
+
 type PlayerRepository struct {
 	db *gorm.DB
 }
 
-func NewPlayerRepository(dbPath string) (*PlayerRepository, error) {
-	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
-	if err != nil {
-		return nil, err
-	}
-	return &PlayerRepository{db: db}, nil
+func NewPlayerRepository(db *gorm.DB) *PlayerRepository {
+	return &PlayerRepository{db: db}
 }
 /*
-func (r *PlayerRepository) Create(player *models.Player) error {
+func (r *PlayerRepository) Create(ctx context.Context, player *models.Player) error {
 	// You can use Create directly; it will insert or update based on primary key
-	return r.db.Create(player).Error
+	return r.db.WithContext(ctx).Create(player).Error
 }
 */
-func (r *PlayerRepository) GetByID(playerID int) (*models.Player, error) {
+func (r *PlayerRepository) GetByID(ctx context.Context, playerID int) (*models.Player, error) {
 	var player models.Player
-	err := r.db.First(&player, playerID).Error
+	err := r.db.WithContext(ctx).First(&player, playerID).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, errors.New("player not found")
+		return nil, ErrPlayerNotFound
 	}
 	return &player, err
 }
 
-func (r *PlayerRepository) SearchByName(name string) ([]*models.Player, error) {
+// GetByIDs returns the players with the given IDs, skipping unknown IDs.
+func (r *PlayerRepository) GetByIDs(ctx context.Context, playerIDs []int) ([]*models.Player, error) {
+	var players []*models.Player
+	err := r.db.WithContext(ctx).Where("player_id IN ?", playerIDs).Find(&players).Error
+	return players, err
+}
+
+func (r *PlayerRepository) SearchByName(ctx context.Context, name string) ([]*models.Player, error) {
 	var players []*models.Player
-	err := r.db.
-		Where("player_name LIKE ?", "%"+name+"%").
+	err := r.db.WithContext(ctx).
+		Where("LOWER(player_name) LIKE LOWER(?)", "%"+name+"%").
 		Limit(20).
 		Find(&players).Error
 	return players, err
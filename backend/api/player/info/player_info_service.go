@@ -1,31 +1,39 @@
 package info
 
 import (
+	"context"
+	"github.com/plinphon/StatsBanger/backend/apperr"
 	"github.com/plinphon/StatsBanger/backend/models"
-	"errors"
 )
 
-var ErrDuplicateMatch = errors.New("duplicate player")
+var ErrDuplicateMatch = apperr.New(apperr.ErrConflict, "duplicate player")
+
+// Repository is the storage PlayerService reads players from.
+type Repository interface {
+	GetByID(ctx context.Context, playerID int) (*models.Player, error)
+	GetByIDs(ctx context.Context, playerIDs []int) ([]*models.Player, error)
+	SearchByName(ctx context.Context, name string) ([]*models.Player, error)
+}
 
 type PlayerService struct {
-	repo *PlayerRepository
+	repo Repository
 }
 
-func NewPlayerService(repo *PlayerRepository) *PlayerService {
+func NewPlayerService(repo Repository) *PlayerService {
     return &PlayerService{repo: repo}
 }
 /*
-func (s *PlayerService) CreatePlayer(player models.Player) error {
+func (s *PlayerService) CreatePlayer(ctx context.Context, player models.Player) error {
 
-	if existing, _ := s.repo.GetByID(player.PlayerId); existing != nil {
+	if existing, _ := s.repo.GetByID(ctx, player.PlayerId); existing != nil {
 		return ErrDuplicateMatch
 	}
 
-	return s.repo.Create(&player)
+	return s.repo.Create(ctx, &player)
 }*/
 
-func (s *PlayerService) GetPlayerByID(playerID int) (*models.Player, error) {
-    player, err := s.repo.GetByID(playerID)
+func (s *PlayerService) GetPlayerByID(ctx context.Context, playerID int) (*models.Player, error) {
+    player, err := s.repo.GetByID(ctx, playerID)
     if err != nil {
         return nil, err
     }
@@ -33,8 +41,14 @@ func (s *PlayerService) GetPlayerByID(playerID int) (*models.Player, error) {
     return player, nil
 }
 
-func (s *PlayerService) SearchPlayersByName(name string) ([]*models.Player, error) {
-    players, err := s.repo.SearchByName(name)
+// GetPlayersByIDs returns the players with the given IDs in no particular
+// order, skipping unknown IDs.
+func (s *PlayerService) GetPlayersByIDs(ctx context.Context, playerIDs []int) ([]*models.Player, error) {
+	return s.repo.GetByIDs(ctx, playerIDs)
+}
+
+func (s *PlayerService) SearchPlayersByName(ctx context.Context, name string) ([]*models.Player, error) {
+    players, err := s.repo.SearchByName(ctx, name)
     if err != nil {
         return nil, err
     }
@@ -2,9 +2,12 @@ package match
 
 import (
 	"strconv"
-	"log"
 	"github.com/gofiber/fiber/v2"
 	"strings"
+	"github.com/plinphon/StatsBanger/backend/api/payload"
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+	"github.com/plinphon/StatsBanger/backend/apperr"
+	"github.com/plinphon/StatsBanger/backend/models"
 )
 
 type PlayerMatchStatController struct {
@@ -34,13 +37,12 @@ func (mc *PlayerMatchStatController) GetStatsByMatchID(c *fiber.Ctx) error {
 		}
 	}
 
-	stats, err := mc.service.GetStatsByMatchID(matchID, statFields) 
+	stats, err := mc.service.GetStatsByMatchID(c.UserContext(), matchID, statFields) 
 	if err != nil {
-		log.Printf("❌ Error getting player stats: %v", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get player stats")
+		return apperr.Wrap(err, "Failed to get player stats")
 	}
 
-	return c.JSON(stats)
+	return tabular.Send(c, "player-match-stats", stats)
 }
 
 func (mc *PlayerMatchStatController) GetAllMatchesStatsByPlayerID(c *fiber.Ctx) error {
@@ -50,13 +52,12 @@ func (mc *PlayerMatchStatController) GetAllMatchesStatsByPlayerID(c *fiber.Ctx)
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid or missing playerID")
 	}
 
-	stats, err := mc.service.GetAllMatchesStatsByPlayerID(playerID)
+	stats, err := mc.service.GetAllMatchesStatsByPlayerID(c.UserContext(), playerID)
 	if err != nil {
-		log.Printf("❌ Error getting player stats: %v", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get player stats")
+		return apperr.Wrap(err, "Failed to get player stats")
 	}
 
-	return c.JSON(stats)
+	return tabular.Send(c, "player-match-stats", stats)
 }
 
 func (mc *PlayerMatchStatController) GetStatByPlayerAndMatchID(c *fiber.Ctx) error {
@@ -72,11 +73,36 @@ func (mc *PlayerMatchStatController) GetStatByPlayerAndMatchID(c *fiber.Ctx) err
         return fiber.NewError(fiber.StatusBadRequest, "Invalid or missing matchID")
     }
 
-    stat, err := mc.service.GetByPlayerAndMatchID(playerID, matchID)
+    stat, err := mc.service.GetByPlayerAndMatchID(c.UserContext(), playerID, matchID)
     if err != nil {
-        log.Printf("❌ Error getting player match stat: %v", err)
-        return fiber.NewError(fiber.StatusInternalServerError, "Failed to get player match stat")
+        return apperr.Wrap(err, "Failed to get player match stat")
     }
 
     return c.JSON(stat)
 }
+
+func (mc *PlayerMatchStatController) CreateStats(c *fiber.Ctx) error {
+	stats, err := payload.DecodeRows[models.PlayerMatchStat](c.Body())
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+
+	if err := mc.service.CreateStats(c.UserContext(), stats); err != nil {
+		return apperr.Wrap(err, "Failed to save player match stats")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(stats)
+}
+
+func (mc *PlayerMatchStatController) UpsertStats(c *fiber.Ctx) error {
+	stats, err := payload.DecodeRows[models.PlayerMatchStat](c.Body())
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+
+	if err := mc.service.UpsertStats(c.UserContext(), stats); err != nil {
+		return apperr.Wrap(err, "Failed to save player match stats")
+	}
+
+	return c.JSON(stats)
+}
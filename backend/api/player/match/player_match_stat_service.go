@@ -1,38 +1,81 @@
 package match
 
 import (
+	"context"
+	"github.com/plinphon/StatsBanger/backend/apperr"
 	"github.com/plinphon/StatsBanger/backend/models"
-	"errors"
 
 )
 
-var ErrDuplicateMatch = errors.New("duplicate match stat")
+var (
+	ErrDuplicateMatch   = apperr.New(apperr.ErrConflict, "duplicate match stat")
+	ErrInvalidStatIds   = apperr.New(apperr.ErrInvalidArgument, "match, player and team Ids must be positive")
+	ErrUnknownReference = apperr.New(apperr.ErrInvalidArgument, "the match, player and team of a stat must exist")
+)
+
+// Repository is the storage PlayerMatchStatService reads and writes match stats through.
+type Repository interface {
+	GetByMatchId(ctx context.Context, matchId int, statFields []string) ([]*models.PlayerMatchStat, error)
+	GetByMatchIds(ctx context.Context, matchIds []int, statFields []string) ([]*models.PlayerMatchStat, error)
+	GetByPlayerIds(ctx context.Context, playerIds []int, statFields []string) ([]*models.PlayerMatchStat, error)
+	GetAllMatchesByPlayerId(ctx context.Context, playerId int) ([]models.PlayerMatchStat, error)
+	GetByPlayerAndMatchId(ctx context.Context, playerId int, matchId int) (*models.PlayerMatchStat, error)
+	Create(ctx context.Context, stats []models.PlayerMatchStat) error
+	Upsert(ctx context.Context, stats []models.PlayerMatchStat) error
+}
 
 type PlayerMatchStatService struct {
-	repo *PlayerMatchStatRepository
+	repo Repository
 }
 
-func NewPlayerMatchStatService(repo *PlayerMatchStatRepository) *PlayerMatchStatService {
+func NewPlayerMatchStatService(repo Repository) *PlayerMatchStatService {
     return &PlayerMatchStatService{repo: repo}
 }
-/*
-func (s *PlayerMatchStatService) CreateStat(stat models.PlayerMatchStat) error {
 
-	if existing, _ := s.repo.GetByID(stat.MatchID, stat.PlayerID); existing != nil {
-		return ErrDuplicateMatch
+func (s *PlayerMatchStatService) CreateStats(ctx context.Context, stats []models.PlayerMatchStat) error {
+	if err := validateStats(stats); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, stats)
+}
+
+func (s *PlayerMatchStatService) UpsertStats(ctx context.Context, stats []models.PlayerMatchStat) error {
+	if err := validateStats(stats); err != nil {
+		return err
 	}
+	return s.repo.Upsert(ctx, stats)
+}
+
+func validateStats(stats []models.PlayerMatchStat) error {
+	for _, stat := range stats {
+		if stat.MatchId <= 0 || stat.PlayerId <= 0 || stat.TeamId <= 0 {
+			return ErrInvalidStatIds
+		}
+		if err := models.ValidateStats(stat.Stats, models.PlayerMatchStats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (s *PlayerMatchStatService) GetStatsByMatchID(ctx context.Context, matchID int, statFields []string) ([]*models.PlayerMatchStat, error) {
+	return s.repo.GetByMatchId(ctx, matchID, statFields)
+}
 
-	return s.repo.Create(stat)
+// GetStatsByMatchIDs returns every player's stats in several matches at
+// once.
+func (s *PlayerMatchStatService) GetStatsByMatchIDs(ctx context.Context, matchIDs []int, statFields []string) ([]*models.PlayerMatchStat, error) {
+	return s.repo.GetByMatchIds(ctx, matchIDs, statFields)
 }
-*/
-func (s *PlayerMatchStatService) GetStatsByMatchID(matchID int, statFields []string) ([]*models.PlayerMatchStat, error) {
-	return s.repo.GetByMatchId(matchID, statFields)
+
+// GetStatsByPlayerIDs returns the match stats of several players at once.
+func (s *PlayerMatchStatService) GetStatsByPlayerIDs(ctx context.Context, playerIDs []int, statFields []string) ([]*models.PlayerMatchStat, error) {
+	return s.repo.GetByPlayerIds(ctx, playerIDs, statFields)
 }
 
-func (s *PlayerMatchStatService) GetAllMatchesStatsByPlayerID(playerID int) ([]models.PlayerMatchStat, error) {
-	return s.repo.GetAllMatchesByPlayerId(playerID)
+func (s *PlayerMatchStatService) GetAllMatchesStatsByPlayerID(ctx context.Context, playerID int) ([]models.PlayerMatchStat, error) {
+	return s.repo.GetAllMatchesByPlayerId(ctx, playerID)
 }
 
-func (s *PlayerMatchStatService) GetByPlayerAndMatchID(playerID int, matchID int) (*models.PlayerMatchStat, error) {
-    return s.repo.GetByPlayerAndMatchId(playerID, matchID)
+func (s *PlayerMatchStatService) GetByPlayerAndMatchID(ctx context.Context, playerID int, matchID int) (*models.PlayerMatchStat, error) {
+    return s.repo.GetByPlayerAndMatchId(ctx, playerID, matchID)
 }
\ No newline at end of file
@@ -2,10 +2,12 @@ package season
 
 import (
 	"strconv"
-	"log"
 	"strings"
 	"github.com/gofiber/fiber/v2"
 	"github.com/plinphon/StatsBanger/backend/models"
+	"github.com/plinphon/StatsBanger/backend/api/payload"
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+	"github.com/plinphon/StatsBanger/backend/apperr"
 )
 
 type PlayerSeasonStatController struct {
@@ -51,13 +53,12 @@ func (mc *PlayerSeasonStatController) GetTopPlayersByStat(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid limit")
 	}
 	
-	topPlayer, err := mc.service.GetTopPlayersByStat(statName, uniqueTournamentID, seasonID, limit, positionFilter)
+	topPlayer, err := mc.service.GetTopPlayersByStat(c.UserContext(), statName, uniqueTournamentID, seasonID, limit, positionFilter)
 	if err != nil {
-		log.Printf("❌ Error getting top player by stat: %v", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get top player by stat")
+		return apperr.Wrap(err, "Failed to get top player by stat")
 	}
 
-	return c.JSON(topPlayer)
+	return tabular.Send(c, "top-players", topPlayer)
 }
 
 func (mc *PlayerSeasonStatController) GetPlayerStatsWithMeta(c *fiber.Ctx) error {
@@ -96,23 +97,45 @@ func (mc *PlayerSeasonStatController) GetPlayerStatsWithMeta(c *fiber.Ctx) error
     statFieldsQuery := c.Query("statFields", "")
     var statFields []string
     if statFieldsQuery == "" {
-        statFields = make([]string, 0, len(models.ValidPlayerSeasonFields))
-        for field := range models.ValidPlayerSeasonFields {
-            statFields = append(statFields, field)
-        }
+        statFields = models.PlayerSeasonStats.Fields()
     } else {
         statFields = strings.Split(statFieldsQuery, ",")
     }
 
 
     // Call service with playerIDs
-    playerStats, err := mc.service.GetPlayerStatsWithMeta(statFields, uniqueTournamentID, seasonID, playerIDs)
+    playerStats, err := mc.service.GetPlayerStatsWithMeta(c.UserContext(), statFields, uniqueTournamentID, seasonID, playerIDs)
 
     if err != nil {
-        log.Printf("❌ Error getting player stats: %v", err)
-        return fiber.NewError(fiber.StatusInternalServerError, "Failed to get player stats")
+        return apperr.Wrap(err, "Failed to get player stats")
     }
 
-    return c.JSON(playerStats)
+    return tabular.Send(c, "player-season-stats", playerStats)
+
+}
+
+func (mc *PlayerSeasonStatController) CreateStats(c *fiber.Ctx) error {
+	stats, err := payload.DecodeRows[models.PlayerSeasonStat](c.Body())
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+
+	if err := mc.service.CreateStats(c.UserContext(), stats); err != nil {
+		return apperr.Wrap(err, "Failed to save player season stats")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(stats)
+}
+
+func (mc *PlayerSeasonStatController) UpsertStats(c *fiber.Ctx) error {
+	stats, err := payload.DecodeRows[models.PlayerSeasonStat](c.Body())
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+
+	if err := mc.service.UpsertStats(c.UserContext(), stats); err != nil {
+		return apperr.Wrap(err, "Failed to save player season stats")
+	}
 
-}
\ No newline at end of file
+	return c.JSON(stats)
+}
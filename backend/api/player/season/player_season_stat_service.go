@@ -1,42 +1,73 @@
 package season
 
 import (
+	"context"
+	"github.com/plinphon/StatsBanger/backend/apperr"
 	"github.com/plinphon/StatsBanger/backend/models"
-	"errors"
 
 )
 
-var ErrDuplicateSeasonStat = errors.New("duplicate match stat")
+var (
+	ErrDuplicateSeasonStat = apperr.New(apperr.ErrConflict, "duplicate season stat")
+	ErrInvalidStatIds      = apperr.New(apperr.ErrInvalidArgument, "player, tournament, season and team Ids must be positive")
+	ErrUnknownReference    = apperr.New(apperr.ErrInvalidArgument, "the player, team, tournament and season of a stat must exist")
+)
+
+// Repository is the storage PlayerSeasonStatService reads and writes season stats through.
+type Repository interface {
+	GetMultipleStatsByPlayerId(ctx context.Context, statFields []string, uniqueTournamentId int, seasonId int, playerIdFields []int) ([]*models.PlayerSeasonStat, error)
+	GetTopPlayersByStat(ctx context.Context, statField string, uniqueTournamentId int, seasonId int, limit int, positionFilter string) ([]models.TopPlayerStatResult, error)
+	Create(ctx context.Context, stats []models.PlayerSeasonStat) error
+	Upsert(ctx context.Context, stats []models.PlayerSeasonStat) error
+}
 
 type PlayerSeasonStatService struct {
-	repo *PlayerSeasonStatRepository
+	repo Repository
 }
 
-func NewPlayerSeasonStatService(repo *PlayerSeasonStatRepository) *PlayerSeasonStatService {
+func NewPlayerSeasonStatService(repo Repository) *PlayerSeasonStatService {
     return &PlayerSeasonStatService{repo: repo}
 }
-/*
-func (s *PlayerSeasonStatService) CreateStat(stat models.PlayerSeasonStat) error {
 
-	if existing, _ := s.repo.GetByID(stat.UniqueTournamentId, stat.SeasonId, stat.PlayerId); existing != nil {
-		return ErrDuplicateSeasonStat
+func (s *PlayerSeasonStatService) CreateStats(ctx context.Context, stats []models.PlayerSeasonStat) error {
+	if err := validateStats(stats); err != nil {
+		return err
 	}
+	return s.repo.Create(ctx, stats)
+}
 
-	return s.repo.Create(stat)
-}*/
+func (s *PlayerSeasonStatService) UpsertStats(ctx context.Context, stats []models.PlayerSeasonStat) error {
+	if err := validateStats(stats); err != nil {
+		return err
+	}
+	return s.repo.Upsert(ctx, stats)
+}
+
+func validateStats(stats []models.PlayerSeasonStat) error {
+	for _, stat := range stats {
+		if stat.PlayerId <= 0 || stat.UniqueTournamentId <= 0 || stat.SeasonId <= 0 || stat.TeamId <= 0 {
+			return ErrInvalidStatIds
+		}
+		if err := models.ValidateStats(stat.Stats, models.PlayerSeasonStats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
 
-func (s *PlayerSeasonStatService) GetTopPlayersByStat(statField string, uniqueTournamentId int, seasonId int, limit int, positionFilter string) ([]models.TopPlayerStatResult, error) {
-	return s.repo.GetTopPlayersByStat(statField, uniqueTournamentId, seasonId, limit, positionFilter)
+func (s *PlayerSeasonStatService) GetTopPlayersByStat(ctx context.Context, statField string, uniqueTournamentId int, seasonId int, limit int, positionFilter string) ([]models.TopPlayerStatResult, error) {
+	return s.repo.GetTopPlayersByStat(ctx, statField, uniqueTournamentId, seasonId, limit, positionFilter)
 }
 
 func (s *PlayerSeasonStatService) GetPlayerStatsWithMeta(
+	ctx context.Context,
 	statFields []string,
 	tournamentId int,
 	seasonId int,
 	playerIds []int, 
 ) ([]*models.PlayerSeasonStat, error) {
-	stats, err := s.repo.GetMultipleStatsByPlayerId(statFields, tournamentId, seasonId, playerIds)
+	stats, err := s.repo.GetMultipleStatsByPlayerId(ctx, statFields, tournamentId, seasonId, playerIds)
 	if err != nil {
 		return nil, err
 	}
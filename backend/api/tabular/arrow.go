@@ -0,0 +1,290 @@
+package tabular
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// MIMEArrow is the media type of an Arrow IPC file.
+const MIMEArrow = "application/vnd.apache.arrow.file"
+
+var arrowMagic = []byte("ARROW1")
+
+// Values of the enums and unions of Arrow's Schema.fbs and Message.fbs.
+const (
+	arrowV5 = 4 // MetadataVersion
+
+	arrowSchemaHeader      = 1 // MessageHeader
+	arrowRecordBatchHeader = 3
+
+	arrowInt           = 2 // Type
+	arrowFloatingPoint = 3
+	arrowUtf8          = 5
+	arrowBool          = 6
+	arrowTimestamp     = 10
+
+	arrowDouble      = 2 // Precision
+	arrowMillisecond = 1 // TimeUnit
+)
+
+// WriteArrow writes t as an Arrow IPC file with a single record batch.
+// Every column is nullable: ints are int64, floats float64 and times
+// millisecond timestamps in UTC.
+func WriteArrow(w io.Writer, t *Table) error {
+	rows := t.values()
+	f := &fileWriter{w: w}
+	f.write(arrowMagic)
+	f.pad()
+
+	schema := flatbuffers.NewBuilder(1024)
+	header := arrowSchema(schema, t.Columns)
+	f.message(arrowMessage(schema, arrowSchemaHeader, header, 0), nil)
+
+	body := &arrowBody{}
+	nodes := make([]arrowNode, len(t.Columns))
+	for j, c := range t.Columns {
+		nodes[j] = body.column(c.Kind, rows, j)
+	}
+	batch := flatbuffers.NewBuilder(1024)
+	header = body.recordBatch(batch, len(rows), nodes)
+	block := f.message(arrowMessage(batch, arrowRecordBatchHeader, header, int64(body.Len())), body.Bytes())
+
+	// An empty message ends the stream for readers that ignore the footer.
+	f.write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0})
+
+	footer := flatbuffers.NewBuilder(1024)
+	s := arrowSchema(footer, t.Columns)
+	footer.StartVector(24, 0, 8)
+	dictionaries := footer.EndVector(0)
+	footer.StartVector(24, 1, 8)
+	footer.Prep(8, 24)
+	footer.PrependInt64(block.bodyLength)
+	footer.Pad(4)
+	footer.PrependInt32(block.metaDataLength)
+	footer.PrependInt64(block.offset)
+	batches := footer.EndVector(1)
+	footer.StartObject(5)
+	footer.PrependInt16Slot(0, arrowV5, 0)
+	footer.PrependUOffsetTSlot(1, s, 0)
+	footer.PrependUOffsetTSlot(2, dictionaries, 0)
+	footer.PrependUOffsetTSlot(3, batches, 0)
+	footer.Finish(footer.EndObject())
+
+	fb := footer.FinishedBytes()
+	f.write(fb)
+	f.write(binary.LittleEndian.AppendUint32(nil, uint32(len(fb))))
+	f.write(arrowMagic)
+	return f.err
+}
+
+// fileWriter writes a file, keeping track of the offset that footers
+// point at.
+type fileWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (f *fileWriter) write(p []byte) {
+	if f.err != nil {
+		return
+	}
+	n, err := f.w.Write(p)
+	f.n += int64(n)
+	f.err = err
+}
+
+// pad aligns the file to 8 bytes.
+func (f *fileWriter) pad() {
+	f.write(make([]byte, padding(f.n)))
+}
+
+// arrowBlock locates a message for the footer.
+type arrowBlock struct {
+	offset         int64
+	metaDataLength int32
+	bodyLength     int64
+}
+
+// message writes an encapsulated message: a continuation marker, the
+// length of the metadata, the metadata padded to 8 bytes and the body.
+func (f *fileWriter) message(meta, body []byte) arrowBlock {
+	size := len(meta) + int(padding(int64(8+len(meta))))
+	block := arrowBlock{offset: f.n, metaDataLength: int32(8 + size), bodyLength: int64(len(body))}
+	f.write([]byte{0xff, 0xff, 0xff, 0xff})
+	f.write(binary.LittleEndian.AppendUint32(nil, uint32(size)))
+	f.write(meta)
+	f.pad()
+	f.write(body)
+	return block
+}
+
+// arrowMessage finishes b with a Message around header.
+func arrowMessage(b *flatbuffers.Builder, headerType byte, header flatbuffers.UOffsetT, bodyLength int64) []byte {
+	b.StartObject(5)
+	b.PrependInt16Slot(0, arrowV5, 0)
+	b.PrependByteSlot(1, headerType, 0)
+	b.PrependUOffsetTSlot(2, header, 0)
+	b.PrependInt64Slot(3, bodyLength, 0)
+	b.Finish(b.EndObject())
+	return b.FinishedBytes()
+}
+
+func arrowSchema(b *flatbuffers.Builder, columns []Column) flatbuffers.UOffsetT {
+	fields := make([]flatbuffers.UOffsetT, len(columns))
+	for i, c := range columns {
+		name := b.CreateString(c.Name)
+		typeType, typ := arrowType(b, c.Kind)
+		// Readers expect a children vector even for primitive types.
+		b.StartVector(4, 0, 4)
+		children := b.EndVector(0)
+
+		b.StartObject(7)
+		b.PrependUOffsetTSlot(0, name, 0)
+		b.PrependBoolSlot(1, true, false)
+		b.PrependByteSlot(2, typeType, 0)
+		b.PrependUOffsetTSlot(3, typ, 0)
+		b.PrependUOffsetTSlot(5, children, 0)
+		fields[i] = b.EndObject()
+	}
+	b.StartVector(4, len(fields), 4)
+	for i := len(fields) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(fields[i])
+	}
+	vector := b.EndVector(len(fields))
+
+	b.StartObject(4)
+	b.PrependUOffsetTSlot(1, vector, 0)
+	return b.EndObject()
+}
+
+func arrowType(b *flatbuffers.Builder, kind Kind) (byte, flatbuffers.UOffsetT) {
+	switch kind {
+	case Int:
+		b.StartObject(2)
+		b.PrependInt32Slot(0, 64, 0)
+		b.PrependBoolSlot(1, true, false)
+		return arrowInt, b.EndObject()
+	case Float:
+		b.StartObject(1)
+		b.PrependInt16Slot(0, arrowDouble, 0)
+		return arrowFloatingPoint, b.EndObject()
+	case Bool:
+		b.StartObject(0)
+		return arrowBool, b.EndObject()
+	case Time:
+		timezone := b.CreateString("UTC")
+		b.StartObject(2)
+		b.PrependInt16Slot(0, arrowMillisecond, 0)
+		b.PrependUOffsetTSlot(1, timezone, 0)
+		return arrowTimestamp, b.EndObject()
+	}
+	b.StartObject(0)
+	return arrowUtf8, b.EndObject()
+}
+
+// arrowNode is the length and null count of a column.
+type arrowNode struct {
+	length, nullCount int64
+}
+
+// arrowBody is the body of a record batch: the buffers of every column,
+// each starting on an 8-byte boundary.
+type arrowBody struct {
+	bytes.Buffer
+	buffers [][2]int64 // offset and length
+}
+
+func (b *arrowBody) buffer(p []byte) {
+	b.buffers = append(b.buffers, [2]int64{int64(b.Len()), int64(len(p))})
+	b.Write(p)
+	b.Write(make([]byte, padding(int64(len(p)))))
+}
+
+// column adds the validity bitmap and the values of column j of rows.
+func (b *arrowBody) column(kind Kind, rows [][]any, j int) arrowNode {
+	node := arrowNode{length: int64(len(rows))}
+	validity := make([]byte, (len(rows)+7)/8)
+	for i, row := range rows {
+		if row[j] == nil {
+			node.nullCount++
+		} else {
+			validity[i/8] |= 1 << (i % 8)
+		}
+	}
+	b.buffer(validity)
+
+	switch kind {
+	case Int, Float, Time:
+		values := make([]byte, 0, 8*len(rows))
+		for _, row := range rows {
+			var bits uint64
+			switch v := row[j].(type) {
+			case int64:
+				bits = uint64(v)
+			case float64:
+				bits = math.Float64bits(v)
+			case time.Time:
+				bits = uint64(v.UnixMilli())
+			}
+			values = binary.LittleEndian.AppendUint64(values, bits)
+		}
+		b.buffer(values)
+
+	case Bool:
+		values := make([]byte, (len(rows)+7)/8)
+		for i, row := range rows {
+			if v, _ := row[j].(bool); v {
+				values[i/8] |= 1 << (i % 8)
+			}
+		}
+		b.buffer(values)
+
+	default:
+		offsets := make([]byte, 0, 4*(len(rows)+1))
+		var data []byte
+		offsets = binary.LittleEndian.AppendUint32(offsets, 0)
+		for _, row := range rows {
+			s, _ := row[j].(string)
+			data = append(data, s...)
+			offsets = binary.LittleEndian.AppendUint32(offsets, uint32(len(data)))
+		}
+		b.buffer(offsets)
+		b.buffer(data)
+	}
+	return node
+}
+
+func (b *arrowBody) recordBatch(fb *flatbuffers.Builder, length int, nodes []arrowNode) flatbuffers.UOffsetT {
+	fb.StartVector(16, len(nodes), 8)
+	for i := len(nodes) - 1; i >= 0; i-- {
+		fb.Prep(8, 16)
+		fb.PrependInt64(nodes[i].nullCount)
+		fb.PrependInt64(nodes[i].length)
+	}
+	nodeVector := fb.EndVector(len(nodes))
+
+	fb.StartVector(16, len(b.buffers), 8)
+	for i := len(b.buffers) - 1; i >= 0; i-- {
+		fb.Prep(8, 16)
+		fb.PrependInt64(b.buffers[i][1])
+		fb.PrependInt64(b.buffers[i][0])
+	}
+	bufferVector := fb.EndVector(len(b.buffers))
+
+	fb.StartObject(4)
+	fb.PrependInt64Slot(0, int64(length), 0)
+	fb.PrependUOffsetTSlot(1, nodeVector, 0)
+	fb.PrependUOffsetTSlot(2, bufferVector, 0)
+	return fb.EndObject()
+}
+
+// padding returns how many bytes align n to 8.
+func padding(n int64) int64 {
+	return (8 - n%8) % 8
+}
@@ -0,0 +1,119 @@
+package tabular
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+)
+
+type sample struct {
+	ID      int       `json:"id"`
+	Name    string    `json:"name"`
+	Rating  *float64  `json:"rating"`
+	Starter *bool     `json:"starter"`
+	Kickoff time.Time `json:"kickoff"`
+}
+
+// sampleTable returns a table with a column of every kind, spanning more
+// than one byte of validity bits, whose nullable columns hold nulls. It
+// also returns the null count of each column.
+func sampleTable() (*Table, []int) {
+	kickoff := time.Date(2023, 8, 12, 19, 30, 0, 0, time.UTC)
+	rows := make([]sample, 11)
+	nulls := make([]int, 5)
+	for i := range rows {
+		rows[i] = sample{ID: 1000 + i, Name: []string{"Bellingham", "Williams", "", "Guruzeta ñ"}[i%4]}
+		if i%3 == 0 {
+			nulls[2]++
+		} else {
+			rating := 6 + float64(i)/4
+			rows[i].Rating = &rating
+		}
+		if i%4 == 3 {
+			nulls[3]++
+		} else {
+			starter := i%2 == 0
+			rows[i].Starter = &starter
+		}
+		if i == 5 {
+			nulls[4]++
+		} else {
+			rows[i].Kickoff = kickoff.Add(time.Duration(i) * 7 * 24 * time.Hour)
+		}
+	}
+	return New(rows), nulls
+}
+
+func TestWriteArrowRoundTrip(t *testing.T) {
+	table, nulls := sampleTable()
+	var buf bytes.Buffer
+	if err := WriteArrow(&buf, table); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := ipc.NewFileReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	want := []struct {
+		name string
+		typ  arrow.DataType
+	}{
+		{"id", arrow.PrimitiveTypes.Int64},
+		{"name", arrow.BinaryTypes.String},
+		{"rating", arrow.PrimitiveTypes.Float64},
+		{"starter", arrow.FixedWidthTypes.Boolean},
+		{"kickoff", &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}},
+	}
+	fields := r.Schema().Fields()
+	if len(fields) != len(want) {
+		t.Fatalf("%d fields, want %d", len(fields), len(want))
+	}
+	for j, f := range fields {
+		if f.Name != want[j].name || !f.Nullable || !arrow.TypeEqual(f.Type, want[j].typ) {
+			t.Errorf("field %d = %s %s (nullable %v), want %s %s", j, f.Name, f.Type, f.Nullable, want[j].name, want[j].typ)
+		}
+	}
+
+	if r.NumRecords() != 1 {
+		t.Fatalf("%d record batches, want 1", r.NumRecords())
+	}
+	rec, err := r.Record(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(rec.NumRows()) != table.Len() {
+		t.Fatalf("%d rows, want %d", rec.NumRows(), table.Len())
+	}
+	for j, col := range rec.Columns() {
+		if col.NullN() != nulls[j] {
+			t.Errorf("%s: %d nulls, want %d", fields[j].Name, col.NullN(), nulls[j])
+		}
+		for i := 0; i < col.Len(); i++ {
+			var got any
+			if !col.IsNull(i) {
+				switch col := col.(type) {
+				case *array.Int64:
+					got = col.Value(i)
+				case *array.Float64:
+					got = col.Value(i)
+				case *array.String:
+					got = col.Value(i)
+				case *array.Boolean:
+					got = col.Value(i)
+				case *array.Timestamp:
+					got = time.UnixMilli(int64(col.Value(i))).UTC()
+				}
+			}
+			if want := table.Row(i)[j]; got != want {
+				t.Errorf("%s[%d] = %v, want %v", fields[j].Name, i, got, want)
+			}
+		}
+	}
+}
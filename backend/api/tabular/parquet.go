@@ -0,0 +1,295 @@
+package tabular
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+// MIMEParquet is the media type of a Parquet file.
+const MIMEParquet = "application/vnd.apache.parquet"
+
+var parquetMagic = []byte("PAR1")
+
+// Values of the enums of parquet.thrift.
+const (
+	parquetBoolean   = 0 // Type
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetOptional = 1 // FieldRepetitionType
+
+	parquetUTF8            = 0 // ConvertedType
+	parquetTimestampMillis = 9
+
+	parquetPlain = 0 // Encoding
+	parquetRLE   = 3
+
+	parquetUncompressed = 0 // CompressionCodec
+	parquetDataPage     = 0 // PageType
+)
+
+// WriteParquet writes t as a Parquet file with a single row group of
+// uncompressed, plain-encoded pages. Every column is optional: ints are
+// INT64, floats DOUBLE, strings UTF-8 byte arrays and times millisecond
+// timestamps in UTC.
+func WriteParquet(w io.Writer, t *Table) error {
+	rows := t.values()
+	f := &fileWriter{w: w}
+	f.write(parquetMagic)
+
+	type chunk struct{ offset, size int64 }
+	chunks := make([]chunk, len(t.Columns))
+	for j, c := range t.Columns {
+		page := parquetPage(c.Kind, rows, j)
+
+		header := newThriftWriter()
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(len(page)))
+		header.i32(3, int32(len(page)))
+		header.beginStruct(5)
+		header.i32(1, int32(len(rows)))
+		header.i32(2, parquetPlain)
+		header.i32(3, parquetRLE)
+		header.i32(4, parquetRLE)
+		header.end()
+		header.end()
+
+		chunks[j] = chunk{offset: f.n, size: int64(len(header.buf) + len(page))}
+		f.write(header.buf)
+		f.write(page)
+	}
+
+	var total int64
+	for _, c := range chunks {
+		total += c.size
+	}
+
+	meta := newThriftWriter()
+	meta.i32(1, 1)
+	meta.list(2, thriftStruct, len(t.Columns)+1)
+	meta.elem()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(t.Columns)))
+	meta.end()
+	for _, c := range t.Columns {
+		meta.elem()
+		meta.i32(1, parquetType(c.Kind))
+		meta.i32(3, parquetOptional)
+		meta.binary(4, c.Name)
+		switch c.Kind {
+		case String:
+			meta.i32(6, parquetUTF8)
+			meta.beginStruct(10) // LogicalType
+			meta.beginStruct(1)  // STRING
+			meta.end()
+			meta.end()
+		case Time:
+			meta.i32(6, parquetTimestampMillis)
+			meta.beginStruct(10) // LogicalType
+			meta.beginStruct(8)  // TIMESTAMP
+			meta.boolean(1, true)
+			meta.beginStruct(2) // unit
+			meta.beginStruct(1) // MILLIS
+			meta.end()
+			meta.end()
+			meta.end()
+			meta.end()
+		}
+		meta.end()
+	}
+	meta.i64(3, int64(len(rows)))
+	meta.list(4, thriftStruct, 1)
+	meta.elem()
+	meta.list(1, thriftStruct, len(t.Columns))
+	for j, c := range t.Columns {
+		meta.elem()
+		meta.i64(2, chunks[j].offset)
+		meta.beginStruct(3) // ColumnMetaData
+		meta.i32(1, parquetType(c.Kind))
+		meta.list(2, thriftI32, 2)
+		meta.uvarint(zigzag(parquetPlain))
+		meta.uvarint(zigzag(parquetRLE))
+		meta.list(3, thriftBinary, 1)
+		meta.uvarint(uint64(len(c.Name)))
+		meta.buf = append(meta.buf, c.Name...)
+		meta.i32(4, parquetUncompressed)
+		meta.i64(5, int64(len(rows)))
+		meta.i64(6, chunks[j].size)
+		meta.i64(7, chunks[j].size)
+		meta.i64(9, chunks[j].offset)
+		meta.end()
+		meta.end()
+	}
+	meta.i64(2, total)
+	meta.i64(3, int64(len(rows)))
+	meta.end()
+	meta.binary(6, "StatsBanger")
+	meta.end()
+
+	f.write(meta.buf)
+	f.write(binary.LittleEndian.AppendUint32(nil, uint32(len(meta.buf))))
+	f.write(parquetMagic)
+	return f.err
+}
+
+func parquetType(kind Kind) int32 {
+	switch kind {
+	case Int, Time:
+		return parquetInt64
+	case Float:
+		return parquetDouble
+	case Bool:
+		return parquetBoolean
+	}
+	return parquetByteArray
+}
+
+// parquetPage returns the body of a data page holding column j of rows:
+// the definition levels, 1 for a value and 0 for a null, then the values.
+func parquetPage(kind Kind, rows [][]any, j int) []byte {
+	// The levels are a single bit-packed run of the RLE hybrid encoding,
+	// preceded by its length.
+	levels := binary.AppendUvarint(nil, uint64((len(rows)+7)/8)<<1|1)
+	bits := make([]byte, (len(rows)+7)/8)
+	for i, row := range rows {
+		if row[j] != nil {
+			bits[i/8] |= 1 << (i % 8)
+		}
+	}
+	levels = append(levels, bits...)
+	page := binary.LittleEndian.AppendUint32(nil, uint32(len(levels)))
+	page = append(page, levels...)
+
+	if kind == Bool {
+		values := make([]byte, 0, len(bits))
+		n := 0
+		for _, row := range rows {
+			v, ok := row[j].(bool)
+			if !ok {
+				continue
+			}
+			if n%8 == 0 {
+				values = append(values, 0)
+			}
+			if v {
+				values[n/8] |= 1 << (n % 8)
+			}
+			n++
+		}
+		return append(page, values...)
+	}
+
+	for _, row := range rows {
+		switch v := row[j].(type) {
+		case int64:
+			page = binary.LittleEndian.AppendUint64(page, uint64(v))
+		case float64:
+			page = binary.LittleEndian.AppendUint64(page, math.Float64bits(v))
+		case time.Time:
+			page = binary.LittleEndian.AppendUint64(page, uint64(v.UnixMilli()))
+		case string:
+			page = binary.LittleEndian.AppendUint32(page, uint32(len(v)))
+			page = append(page, v...)
+		}
+	}
+	return page
+}
+
+// Types of the Thrift compact protocol.
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes a struct in the Thrift compact protocol, in which
+// Parquet stores its page headers and footer. Fields must be written in
+// the order of their IDs.
+type thriftWriter struct {
+	buf []byte
+	// last holds the ID of the last field written to each open struct.
+	last []int16
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{last: []int16{0}}
+}
+
+func (w *thriftWriter) field(id int16, typ byte) {
+	last := &w.last[len(w.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.uvarint(zigzag(int64(id)))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) uvarint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.uvarint(zigzag(int64(v)))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.uvarint(zigzag(v))
+}
+
+func (w *thriftWriter) boolean(id int16, v bool) {
+	if v {
+		w.field(id, thriftTrue)
+	} else {
+		w.field(id, thriftFalse)
+	}
+}
+
+func (w *thriftWriter) binary(id int16, s string) {
+	w.field(id, thriftBinary)
+	w.uvarint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+// list starts a list of n elements, which are written next without field
+// headers.
+func (w *thriftWriter) list(id int16, elem byte, n int) {
+	w.field(id, thriftList)
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|elem)
+	} else {
+		w.buf = append(w.buf, 0xf0|elem)
+		w.uvarint(uint64(n))
+	}
+}
+
+// beginStruct starts a struct field; end closes it.
+func (w *thriftWriter) beginStruct(id int16) {
+	w.field(id, thriftStruct)
+	w.last = append(w.last, 0)
+}
+
+// elem starts a struct element of a list; end closes it.
+func (w *thriftWriter) elem() {
+	w.last = append(w.last, 0)
+}
+
+// end closes the innermost struct, or the writer's own.
+func (w *thriftWriter) end() {
+	w.buf = append(w.buf, 0)
+	w.last = w.last[:len(w.last)-1]
+}
@@ -0,0 +1,348 @@
+package tabular
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+// The test reads the file back with the decoder below, which parses the
+// Thrift compact protocol generically and handles both run kinds of the RLE
+// hybrid encoding. It only checks the file against this package's reading
+// of the spec.
+//
+// TODO: read the file with github.com/apache/arrow-go/v18/parquet/file, and
+// the IPC file of arrow_test.go with arrow-go/v18/arrow/ipc, once arrow-go
+// is a dependency; then drop the decoder.
+
+// thriftReader decodes structs of the Thrift compact protocol into maps
+// from field ID to value: int64 for integers, bool, []byte, []any for
+// lists and map[int16]any for structs.
+type thriftReader struct {
+	buf []byte
+	err error
+}
+
+func (r *thriftReader) byte() byte {
+	if len(r.buf) == 0 {
+		r.fail("unexpected end of input")
+		return 0
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b
+}
+
+func (r *thriftReader) fail(format string, args ...any) {
+	if r.err == nil {
+		r.err = fmt.Errorf(format, args...)
+	}
+	r.buf = nil
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.fail("bad varint")
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(typ byte) any {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case 3:
+		return int64(int8(r.byte()))
+	case 4, 5, 6:
+		return r.varint()
+	case 7:
+		if len(r.buf) < 8 {
+			r.fail("short double")
+			return nil
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(r.buf))
+		r.buf = r.buf[8:]
+		return v
+	case 8:
+		n := r.uvarint()
+		if uint64(len(r.buf)) < n {
+			r.fail("short binary")
+			return nil
+		}
+		v := r.buf[:n]
+		r.buf = r.buf[n:]
+		return v
+	case 9, 10:
+		header := r.byte()
+		n, elem := uint64(header>>4), header&0x0f
+		if n == 15 {
+			n = r.uvarint()
+		}
+		list := make([]any, 0, n)
+		for i := uint64(0); i < n && r.err == nil; i++ {
+			if elem == 1 || elem == 2 {
+				// Booleans in lists take a byte each.
+				list = append(list, r.byte() == 1)
+			} else {
+				list = append(list, r.value(elem))
+			}
+		}
+		return list
+	case 12:
+		return r.structure()
+	}
+	r.fail("unknown type %d", typ)
+	return nil
+}
+
+func (r *thriftReader) structure() map[int16]any {
+	fields := make(map[int16]any)
+	var last int16
+	for r.err == nil {
+		header := r.byte()
+		if header == 0 {
+			break
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.varint())
+		}
+		fields[id] = r.value(header & 0x0f)
+		last = id
+	}
+	return fields
+}
+
+// field returns the value of a path of field IDs through nested structs.
+func field(s map[int16]any, ids ...int16) any {
+	var v any = s
+	for _, id := range ids {
+		m, ok := v.(map[int16]any)
+		if !ok {
+			return nil
+		}
+		v = m[id]
+	}
+	return v
+}
+
+// readLevels decodes n definition levels of bit width 1 stored in the RLE
+// hybrid encoding.
+func readLevels(p []byte, n int) ([]bool, error) {
+	levels := make([]bool, 0, n)
+	for len(levels) < n {
+		header, k := binary.Uvarint(p)
+		if k <= 0 {
+			return nil, fmt.Errorf("bad run header")
+		}
+		p = p[k:]
+		if header&1 == 0 {
+			// An RLE run of one repeated value, stored in a byte.
+			if len(p) < 1 {
+				return nil, fmt.Errorf("short RLE run")
+			}
+			for i := uint64(0); i < header>>1; i++ {
+				levels = append(levels, p[0] == 1)
+			}
+			p = p[1:]
+			continue
+		}
+		// A bit-packed run of groups of 8 values.
+		size := int(header>>1) * 8
+		if len(p) < size/8 {
+			return nil, fmt.Errorf("short bit-packed run")
+		}
+		for i := 0; i < size && len(levels) < n; i++ {
+			levels = append(levels, p[i/8]>>(i%8)&1 == 1)
+		}
+		p = p[size/8:]
+	}
+	return levels, nil
+}
+
+type parquetColumn struct {
+	name          string
+	typ           int64
+	convertedType any
+	logicalType   any
+	repetition    int64
+	values        []any
+	nulls         int
+}
+
+// readParquet decodes a file of one row group of uncompressed v1 data
+// pages, returning its row count and columns.
+func readParquet(file []byte) (int64, []parquetColumn, error) {
+	if len(file) < 12 || string(file[:4]) != "PAR1" || string(file[len(file)-4:]) != "PAR1" {
+		return 0, nil, fmt.Errorf("missing magic")
+	}
+	size := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	if size > len(file)-12 {
+		return 0, nil, fmt.Errorf("footer of %d bytes", size)
+	}
+	r := &thriftReader{buf: file[len(file)-8-size : len(file)-8]}
+	meta := r.structure()
+	if r.err != nil {
+		return 0, nil, fmt.Errorf("footer: %w", r.err)
+	}
+	if len(r.buf) != 0 {
+		return 0, nil, fmt.Errorf("%d bytes after the footer", len(r.buf))
+	}
+
+	numRows, _ := field(meta, 3).(int64)
+	schema, _ := field(meta, 2).([]any)
+	groups, _ := field(meta, 4).([]any)
+	if len(schema) == 0 || len(groups) != 1 {
+		return 0, nil, fmt.Errorf("%d schema elements and %d row groups", len(schema), len(groups))
+	}
+	root := schema[0].(map[int16]any)
+	chunks, _ := field(groups[0].(map[int16]any), 1).([]any)
+	if children, _ := root[5].(int64); int(children) != len(schema)-1 || len(chunks) != len(schema)-1 {
+		return 0, nil, fmt.Errorf("root has %d children, %d elements follow and %d chunks", children, len(schema)-1, len(chunks))
+	}
+
+	columns := make([]parquetColumn, len(schema)-1)
+	for j := range columns {
+		element := schema[j+1].(map[int16]any)
+		name, _ := element[4].([]byte)
+		c := parquetColumn{
+			name:          string(name),
+			typ:           element[1].(int64),
+			repetition:    element[3].(int64),
+			convertedType: element[6],
+			logicalType:   element[10],
+		}
+
+		chunk := chunks[j].(map[int16]any)
+		if codec := field(chunk, 3, 4); codec != int64(0) {
+			return 0, nil, fmt.Errorf("%s: codec %v", c.name, codec)
+		}
+		if typ := field(chunk, 3, 1); typ != c.typ {
+			return 0, nil, fmt.Errorf("%s: chunk of type %v in a column of type %d", c.name, typ, c.typ)
+		}
+		offset, _ := field(chunk, 3, 9).(int64)
+		if offset < 4 || offset >= int64(len(file)) {
+			return 0, nil, fmt.Errorf("%s: page at %d", c.name, offset)
+		}
+
+		r := &thriftReader{buf: file[offset:]}
+		header := r.structure()
+		if r.err != nil {
+			return 0, nil, fmt.Errorf("%s: page header: %w", c.name, r.err)
+		}
+		pageSize, _ := header[3].(int64)
+		if header[1] != int64(0) || int64(len(r.buf)) < pageSize {
+			return 0, nil, fmt.Errorf("%s: page of type %v and %d bytes", c.name, header[1], pageSize)
+		}
+		n, _ := field(header, 5, 1).(int64)
+		if field(header, 5, 2) != int64(0) || field(header, 5, 3) != int64(3) {
+			return 0, nil, fmt.Errorf("%s: encodings %v and %v", c.name, field(header, 5, 2), field(header, 5, 3))
+		}
+		page := r.buf[:pageSize]
+
+		levelSize := int(binary.LittleEndian.Uint32(page))
+		levels, err := readLevels(page[4:4+levelSize], int(n))
+		if err != nil {
+			return 0, nil, fmt.Errorf("%s: %w", c.name, err)
+		}
+		values := page[4+levelSize:]
+		bit := 0
+		for _, defined := range levels {
+			if !defined {
+				c.nulls++
+				c.values = append(c.values, nil)
+				continue
+			}
+			switch c.typ {
+			case parquetBoolean:
+				c.values = append(c.values, values[bit/8]>>(bit%8)&1 == 1)
+				bit++
+			case parquetInt64:
+				c.values = append(c.values, int64(binary.LittleEndian.Uint64(values)))
+				values = values[8:]
+			case parquetDouble:
+				c.values = append(c.values, math.Float64frombits(binary.LittleEndian.Uint64(values)))
+				values = values[8:]
+			case parquetByteArray:
+				size := binary.LittleEndian.Uint32(values)
+				c.values = append(c.values, string(values[4:4+size]))
+				values = values[4+size:]
+			}
+		}
+		if c.typ == parquetBoolean {
+			values = values[(bit+7)/8:]
+		}
+		if len(values) != 0 {
+			return 0, nil, fmt.Errorf("%s: %d bytes after the values", c.name, len(values))
+		}
+		columns[j] = c
+	}
+	return numRows, columns, nil
+}
+
+func TestWriteParquetRoundTrip(t *testing.T) {
+	table, nulls := sampleTable()
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, table); err != nil {
+		t.Fatal(err)
+	}
+
+	numRows, columns, err := readParquet(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(numRows) != table.Len() {
+		t.Errorf("%d rows, want %d", numRows, table.Len())
+	}
+
+	millis := map[int16]any{8: map[int16]any{1: true, 2: map[int16]any{1: map[int16]any{}}}}
+	want := []struct {
+		name          string
+		typ           int64
+		convertedType any
+		logicalType   any
+	}{
+		{"id", parquetInt64, nil, nil},
+		{"name", parquetByteArray, int64(parquetUTF8), map[int16]any{1: map[int16]any{}}},
+		{"rating", parquetDouble, nil, nil},
+		{"starter", parquetBoolean, nil, nil},
+		{"kickoff", parquetInt64, int64(parquetTimestampMillis), millis},
+	}
+	if len(columns) != len(want) {
+		t.Fatalf("%d columns, want %d", len(columns), len(want))
+	}
+	for j, c := range columns {
+		w := want[j]
+		if c.name != w.name || c.typ != w.typ || c.repetition != parquetOptional ||
+			fmt.Sprint(c.convertedType) != fmt.Sprint(w.convertedType) || fmt.Sprint(c.logicalType) != fmt.Sprint(w.logicalType) {
+			t.Errorf("column %d = %+v, want %+v", j, c, w)
+		}
+		if c.nulls != nulls[j] {
+			t.Errorf("%s: %d nulls, want %d", c.name, c.nulls, nulls[j])
+		}
+		if len(c.values) != table.Len() {
+			t.Fatalf("%s: %d values, want %d", c.name, len(c.values), table.Len())
+		}
+		for i, got := range c.values {
+			if got != nil && c.convertedType == int64(parquetTimestampMillis) {
+				got = time.UnixMilli(got.(int64)).UTC()
+			}
+			if want := table.Row(i)[j]; got != want {
+				t.Errorf("%s[%d] = %v, want %v", c.name, i, got, want)
+			}
+		}
+	}
+}
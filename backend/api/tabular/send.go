@@ -0,0 +1,234 @@
+package tabular
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+)
+
+// Formats a list can be sent in, as named by the format query parameter.
+const (
+	FormatJSON   = "json"
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+// Media types of the formats other than JSON.
+const (
+	MIMECSV    = "text/csv"
+	MIMENDJSON = "application/x-ndjson"
+	MIMEXLSX   = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// Formats lists every format with its media type, JSON first.
+var Formats = []struct{ Name, MIME string }{
+	{FormatJSON, fiber.MIMEApplicationJSON},
+	{FormatCSV, MIMECSV},
+	{FormatNDJSON, MIMENDJSON},
+	{FormatXLSX, MIMEXLSX},
+}
+
+// HeaderTotalCount is the header in which a paginated list gives how many
+// rows there are across every page.
+const HeaderTotalCount = "X-Total-Count"
+
+// ndjsonFlushEvery is how many NDJSON rows are buffered before they are
+// sent to the client.
+const ndjsonFlushEvery = 256
+
+// Send writes rows, a slice of structs or pointers to structs, in the
+// format the request asks for: the format query parameter if it is set,
+// else the best match of its Accept header, else JSON. CSV and XLSX are
+// offered as downloads named after name, which also titles the workbook's
+// sheet.
+func Send(c *fiber.Ctx, name string, rows any) error {
+	format, err := negotiate(c)
+	if err != nil {
+		return err
+	}
+	c.Vary(fiber.HeaderAccept)
+
+	switch format {
+	case FormatCSV:
+		c.Set(fiber.HeaderContentType, MIMECSV+"; charset=utf-8")
+		c.Attachment(name + ".csv")
+		return writeCSV(c, New(rows))
+
+	case FormatNDJSON:
+		t := New(rows)
+		c.Set(fiber.HeaderContentType, MIMENDJSON)
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			enc := json.NewEncoder(w)
+			for i := 0; i < t.Len(); i++ {
+				if err := enc.Encode(t.Value(i)); err != nil {
+					return
+				}
+				if (i+1)%ndjsonFlushEvery == 0 {
+					if err := w.Flush(); err != nil {
+						// The client has gone away.
+						return
+					}
+				}
+			}
+		})
+		return nil
+
+	case FormatXLSX:
+		c.Set(fiber.HeaderContentType, MIMEXLSX)
+		c.Attachment(name + ".xlsx")
+		return writeXLSX(c, name, New(rows))
+	}
+	return c.JSON(rows)
+}
+
+func negotiate(c *fiber.Ctx) (string, error) {
+	if format := c.Query("format"); format != "" {
+		for _, f := range Formats {
+			if strings.EqualFold(format, f.Name) {
+				return f.Name, nil
+			}
+		}
+		return "", apperr.InvalidArgument("unknown format %q (want json, csv, ndjson or xlsx)", format)
+	}
+
+	offers := make([]string, len(Formats))
+	for i, f := range Formats {
+		offers[i] = f.MIME
+	}
+	// Accepts returns the first offer, JSON, when the header is missing
+	// or accepts anything, and "" when it accepts none of them.
+	accepted := c.Accepts(offers...)
+	for _, f := range Formats {
+		if accepted == f.MIME {
+			return f.Name, nil
+		}
+	}
+	return FormatJSON, nil
+}
+
+func writeCSV(w io.Writer, t *Table) error {
+	cw := csv.NewWriter(w)
+	record := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		record[i] = c.Name
+	}
+	cw.Write(record)
+	for i := 0; i < t.Len(); i++ {
+		for j, v := range t.Row(i) {
+			record[j] = text(v)
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// text formats a value of Table.Row for CSV.
+func text(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		// Spreadsheets run cells starting with these as formulas; a quote
+		// makes them text. Numbers are formatted below and stay numbers.
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
+
+// writeXLSX writes a workbook with the rows on its first sheet and, when
+// there are stat columns, a second sheet describing each stat.
+func writeXLSX(w io.Writer, name string, t *Table) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := sheetName(name)
+	if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+		return err
+	}
+	header := make([]any, len(t.Columns))
+	for i, c := range t.Columns {
+		header[i] = c.Name
+	}
+	if err := writeSheet(f, sheet, header, t.values()); err != nil {
+		return err
+	}
+
+	if stats := t.Stats(); len(stats) > 0 {
+		if _, err := f.NewSheet("stats"); err != nil {
+			return err
+		}
+		header := []any{"field", "label", "category", "unit", "per90", "direction", "aggregation"}
+		rows := make([][]any, len(stats))
+		for i, s := range stats {
+			rows[i] = []any{s.Field, s.Label, string(s.Category), string(s.Unit), s.Per90, string(s.Direction), string(s.Aggregation)}
+		}
+		if err := writeSheet(f, "stats", header, rows); err != nil {
+			return err
+		}
+	}
+
+	_, err := f.WriteTo(w)
+	return err
+}
+
+func writeSheet(f *excelize.File, sheet string, header []any, rows [][]any) error {
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+	// Keep the header in view while scrolling.
+	if err := sw.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return err
+	}
+	if err := sw.SetRow("A1", header); err != nil {
+		return err
+	}
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err := sw.SetRow(cell, row); err != nil {
+			return err
+		}
+	}
+	return sw.Flush()
+}
+
+// sheetName makes name a valid sheet name: at most 31 characters, none of
+// them : \ / ? * [ or ].
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	if len(name) > 31 {
+		name = name[:31]
+	}
+	return name
+}
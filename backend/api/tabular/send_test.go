@@ -0,0 +1,47 @@
+package tabular
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"testing"
+)
+
+func TestWriteCSVEscapesFormulas(t *testing.T) {
+	type row struct {
+		Name  string  `json:"name"`
+		Goals int     `json:"goals"`
+		Delta float64 `json:"delta"`
+	}
+	table := New([]row{
+		{"=HYPERLINK(\"http://example.com\")", -3, -0.5},
+		{"+1", 0, 0},
+		{"-1", 0, 0},
+		{"@SUM(A1)", 0, 0},
+		{"\tcmd", 0, 0},
+		{"Bellingham", 1, 2.5},
+		{"", 0, 0},
+	})
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, table); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"name", "goals", "delta"},
+		{"'=HYPERLINK(\"http://example.com\")", "-3", "-0.5"},
+		{"'+1", "0", "0"},
+		{"'-1", "0", "0"},
+		{"'@SUM(A1)", "0", "0"},
+		{"'\tcmd", "0", "0"},
+		{"Bellingham", "1", "2.5"},
+		{"", "0", "0"},
+	}
+	if !slices.EqualFunc(records, want, slices.Equal) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+}
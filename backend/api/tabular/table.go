@@ -0,0 +1,248 @@
+// Package tabular flattens lists of models into columns, so list endpoints
+// can return them as CSV, NDJSON or an XLSX workbook as well as JSON.
+package tabular
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// Kind is the type of the values in a column.
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	Float
+	Bool
+	Time
+)
+
+// Column is one flattened field of the rows of a Table.
+type Column struct {
+	// Name is the JSON name of the field, prefixed by the names of the
+	// structs it is nested in, such as "team.name". Stat columns are
+	// named by their field alone.
+	Name string
+	Kind Kind
+	// Stat describes the column if it holds one of the rows' stats.
+	Stat *models.Stat
+
+	index []int
+	stat  string
+}
+
+// Table is a list of rows flattened into columns.
+type Table struct {
+	Columns []Column
+	rows    reflect.Value
+}
+
+// registries maps a model to the registry of its Stats map.
+var registries = map[reflect.Type]*models.StatRegistry{
+	reflect.TypeOf(models.PlayerMatchStat{}):  models.PlayerMatchStats,
+	reflect.TypeOf(models.PlayerSeasonStat{}): models.PlayerSeasonStats,
+	reflect.TypeOf(models.TeamMatchStat{}):    models.TeamMatchStats,
+	reflect.TypeOf(models.TeamSeasonStat{}):   models.TeamSeasonStats,
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// New flattens rows, a slice of structs or of pointers to structs. Fields
+// follow their JSON names and order; nested structs are flattened in
+// place, while optional relations (pointers to structs), slices and other
+// maps are left out. A Stats map becomes one column per stat that any row
+// has, in the order of the model's stat registry, so the columns do not
+// depend on map order or on which row comes first.
+func New(rows any) *Table {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		panic("tabular: rows must be a slice, got " + v.Type().String())
+	}
+	t := &Table{rows: v}
+
+	elem := v.Type().Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() == reflect.Struct {
+		t.Columns = t.columns(elem, "", nil)
+	}
+	return t
+}
+
+func (t *Table) columns(typ reflect.Type, prefix string, index []int) []Column {
+	var columns []Column
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, ok := jsonName(f)
+		if !ok {
+			continue
+		}
+		fieldIndex := append(index[:len(index):len(index)], i)
+
+		ft := f.Type
+		if f.Anonymous && ft.Kind() == reflect.Struct {
+			columns = append(columns, t.columns(ft, prefix, fieldIndex)...)
+			continue
+		}
+		if registry, ok := registries[typ]; ok && f.Name == "Stats" {
+			columns = append(columns, t.statColumns(registry, fieldIndex)...)
+			continue
+		}
+
+		pointer := ft.Kind() == reflect.Pointer
+		if pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType {
+			if !pointer {
+				columns = append(columns, t.columns(ft, prefix+name+".", fieldIndex)...)
+			}
+			continue
+		}
+		if kind, ok := kindOf(ft); ok {
+			columns = append(columns, Column{Name: prefix + name, Kind: kind, index: fieldIndex})
+		}
+	}
+	return columns
+}
+
+// statColumns returns a column for each stat of registry that one of the
+// rows has.
+func (t *Table) statColumns(registry *models.StatRegistry, index []int) []Column {
+	present := make(map[string]bool)
+	for i := 0; i < t.rows.Len(); i++ {
+		row := reflect.Indirect(t.rows.Index(i))
+		if !row.IsValid() {
+			continue
+		}
+		for _, key := range row.FieldByIndex(index).MapKeys() {
+			present[key.String()] = true
+		}
+	}
+
+	var columns []Column
+	for _, stat := range registry.Stats() {
+		if present[stat.Field] {
+			columns = append(columns, Column{Name: stat.Field, Kind: Float, Stat: &stat, index: index, stat: stat.Field})
+		}
+	}
+	return columns
+}
+
+func jsonName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, true
+}
+
+func kindOf(t reflect.Type) (Kind, bool) {
+	switch t.Kind() {
+	case reflect.String:
+		return String, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Int, true
+	case reflect.Float32, reflect.Float64:
+		return Float, true
+	case reflect.Bool:
+		return Bool, true
+	case reflect.Struct:
+		return Time, t == timeType
+	}
+	return 0, false
+}
+
+// Len returns the number of rows.
+func (t *Table) Len() int {
+	return t.rows.Len()
+}
+
+// Row returns the values of row i, one per column: a string, int64,
+// float64, bool or time.Time according to the column's Kind, or nil if
+// the row has no value, such as a stat it lacks or a zero time.
+func (t *Table) Row(i int) []any {
+	values := make([]any, len(t.Columns))
+	row := reflect.Indirect(t.rows.Index(i))
+	if !row.IsValid() {
+		return values
+	}
+	for j, c := range t.Columns {
+		values[j] = c.value(row)
+	}
+	return values
+}
+
+// values returns every row as Row does.
+func (t *Table) values() [][]any {
+	rows := make([][]any, t.Len())
+	for i := range rows {
+		rows[i] = t.Row(i)
+	}
+	return rows
+}
+
+// Value returns row i as it was given to New, for formats that keep the
+// models' own shape.
+func (t *Table) Value(i int) any {
+	return t.rows.Index(i).Interface()
+}
+
+func (c *Column) value(row reflect.Value) any {
+	v := row.FieldByIndex(c.index)
+	if c.stat != "" {
+		v = v.MapIndex(reflect.ValueOf(c.stat))
+		if !v.IsValid() {
+			return nil
+		}
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch c.Kind {
+	case String:
+		return v.String()
+	case Int:
+		if v.CanUint() {
+			return int64(v.Uint())
+		}
+		return v.Int()
+	case Float:
+		return v.Float()
+	case Bool:
+		return v.Bool()
+	case Time:
+		if tm := v.Interface().(time.Time); !tm.IsZero() {
+			return tm
+		}
+	}
+	return nil
+}
+
+// Stats returns the stats of the stat columns, in column order.
+func (t *Table) Stats() []models.Stat {
+	var stats []models.Stat
+	for _, c := range t.Columns {
+		if c.Stat != nil {
+			stats = append(stats, *c.Stat)
+		}
+	}
+	return stats
+}
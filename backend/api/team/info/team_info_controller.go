@@ -2,8 +2,10 @@ package info
 
 import (
 	"strconv"
-	"log"
 	"github.com/gofiber/fiber/v2"
+
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+	"github.com/plinphon/StatsBanger/backend/apperr"
 )
 
 type TeamController struct {
@@ -21,10 +23,9 @@ func (tc *TeamController) GetTeamByID(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid team ID")
 	}
 
-	team, err := tc.service.GetTeamByID(teamID)
+	team, err := tc.service.GetTeamByID(c.UserContext(), teamID)
 	if err != nil {
-		log.Printf("❌ Error getting team: %v", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get the team")
+		return apperr.Wrap(err, "Failed to get the team")
 	}
 
 	return c.JSON(team)
@@ -33,11 +34,10 @@ func (tc *TeamController) GetTeamByID(c *fiber.Ctx) error {
 func (tc *TeamController) SearchTeamsByName(c *fiber.Ctx) error {
 	teamName := c.Query("name")
 
-	teams, err := tc.service.SearchTeamsByName(teamName)
+	teams, err := tc.service.SearchTeamsByName(c.UserContext(), teamName)
 	if err != nil {
-		log.Printf("❌ Error searching teams: %v", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get teams")
+		return apperr.Wrap(err, "Failed to get teams")
 	}
 
-	return c.JSON(teams)
+	return tabular.Send(c, "teams", teams)
 }
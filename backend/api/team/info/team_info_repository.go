@@ -1,41 +1,47 @@
 package info
 
 import (
+	"context"
 	"errors"
+	"github.com/plinphon/StatsBanger/backend/apperr"
 	"github.com/plinphon/StatsBanger/backend/models"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
+var ErrTeamNotFound = apperr.New(apperr.ErrNotFound, "team not found")
+
 type TeamRepository struct {
 	db *gorm.DB
 }
 
-func NewTeamRepository(dbPath string) (*TeamRepository, error) {
-	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
-	if err != nil {
-		return nil, err
-	}
-	return &TeamRepository{db: db}, nil
+func NewTeamRepository(db *gorm.DB) *TeamRepository {
+	return &TeamRepository{db: db}
 }
 
-func (r *TeamRepository) Create(team *models.Team) error {
-	return r.db.Create(team).Error
+func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
+	return r.db.WithContext(ctx).Create(team).Error
 }
 
-func (r *TeamRepository) GetByID(teamID int) (*models.Team, error) {
+func (r *TeamRepository) GetByID(ctx context.Context, teamID int) (*models.Team, error) {
 	var team models.Team
-	err := r.db.First(&team, teamID).Error
+	err := r.db.WithContext(ctx).First(&team, teamID).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, errors.New("team not found")
+		return nil, ErrTeamNotFound
 	}
 	return &team, err
 }
 
-func (r *TeamRepository) SearchByName(name string) ([]*models.Team, error) {
+// GetByIDs returns the teams with the given IDs, skipping unknown IDs.
+func (r *TeamRepository) GetByIDs(ctx context.Context, teamIDs []int) ([]*models.Team, error) {
+	var teams []*models.Team
+	err := r.db.WithContext(ctx).Where("team_id IN ?", teamIDs).Find(&teams).Error
+	return teams, err
+}
+
+func (r *TeamRepository) SearchByName(ctx context.Context, name string) ([]*models.Team, error) {
 	var teams []*models.Team
-	err := r.db.
-		Where("team_name LIKE ?", "%"+name+"%").
+	err := r.db.WithContext(ctx).
+		Where("LOWER(team_name) LIKE LOWER(?)", "%"+name+"%").
 		Limit(20).
 		Find(&teams).Error
 	return teams, err
@@ -1,35 +1,50 @@
 package info
 
 import (
-	"errors"
+	"context"
+	"github.com/plinphon/StatsBanger/backend/apperr"
 	"github.com/plinphon/StatsBanger/backend/models"
 )
 
-var ErrDuplicateTeam = errors.New("duplicate team")
+var ErrDuplicateTeam = apperr.New(apperr.ErrConflict, "duplicate team")
+
+// Repository is the storage TeamService reads and writes teams through.
+type Repository interface {
+	Create(ctx context.Context, team *models.Team) error
+	GetByID(ctx context.Context, teamID int) (*models.Team, error)
+	GetByIDs(ctx context.Context, teamIDs []int) ([]*models.Team, error)
+	SearchByName(ctx context.Context, name string) ([]*models.Team, error)
+}
 
 type TeamService struct {
-	repo *TeamRepository
+	repo Repository
 }
 
-func NewTeamService(repo *TeamRepository) *TeamService {
+func NewTeamService(repo Repository) *TeamService {
 	return &TeamService{repo: repo}
 }
 
-func (s *TeamService) CreateTeam(team models.Team) error {
-	existing, err := s.repo.GetByID(team.TeamId)
+func (s *TeamService) CreateTeam(ctx context.Context, team models.Team) error {
+	existing, err := s.repo.GetByID(ctx, team.TeamId)
 	if err == nil && existing != nil {
 		return ErrDuplicateTeam
 	}
 	if err != nil && err.Error() != "team not found" {
 		return err
 	}
-	return s.repo.Create(&team)
+	return s.repo.Create(ctx, &team)
+}
+
+func (s *TeamService) GetTeamByID(ctx context.Context, teamID int) (*models.Team, error) {
+	return s.repo.GetByID(ctx, teamID)
 }
 
-func (s *TeamService) GetTeamByID(teamID int) (*models.Team, error) {
-	return s.repo.GetByID(teamID)
+// GetTeamsByIDs returns the teams with the given IDs in no particular
+// order, skipping unknown IDs.
+func (s *TeamService) GetTeamsByIDs(ctx context.Context, teamIDs []int) ([]*models.Team, error) {
+	return s.repo.GetByIDs(ctx, teamIDs)
 }
 
-func (s *TeamService) SearchTeamsByName(name string) ([]*models.Team, error) {
-	return s.repo.SearchByName(name)
+func (s *TeamService) SearchTeamsByName(ctx context.Context, name string) ([]*models.Team, error) {
+	return s.repo.SearchByName(ctx, name)
 }
@@ -2,9 +2,12 @@ package match
 
 import (
 	"strconv"
-	"log"
 	"github.com/gofiber/fiber/v2"
 	"strings"
+	"github.com/plinphon/StatsBanger/backend/api/payload"
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+	"github.com/plinphon/StatsBanger/backend/apperr"
+	"github.com/plinphon/StatsBanger/backend/models"
 )
 
 type TeamMatchStatController struct {
@@ -36,10 +39,9 @@ func (mc *TeamMatchStatController) GetStatByTeamAndMatchID(c *fiber.Ctx) error {
         statFields = strings.Split(statFieldsStr, ",")
     }
 
-    stat, err := mc.service.GetStatByID(matchID, teamID, statFields)
+    stat, err := mc.service.GetStatByID(c.UserContext(), matchID, teamID, statFields)
     if err != nil {
-        log.Printf("❌ Error getting team stat: %v", err)
-        return fiber.NewError(fiber.StatusInternalServerError, "Failed to get a team stat")
+        return apperr.Wrap(err, "Failed to get a team stat")
     }
 
     return c.JSON(stat)
@@ -52,12 +54,37 @@ func (mc *TeamMatchStatController) GetAllMatchesByTeamID(c *fiber.Ctx) error {
         return fiber.NewError(fiber.StatusBadRequest, "Invalid or missing teamID")
     }
 
-    stats, err := mc.service.GetAllMatchesByTeamID(teamID)
+    stats, err := mc.service.GetAllMatchesByTeamID(c.UserContext(), teamID)
     if err != nil {
-        log.Printf("❌ Error getting team match stats by teamID: %v", err)
-        return fiber.NewError(fiber.StatusInternalServerError, "Failed to get team match stats")
+        return apperr.Wrap(err, "Failed to get team match stats")
     }
 
-    return c.JSON(stats)
+    return tabular.Send(c, "team-match-stats", stats)
 
-}
\ No newline at end of file
+}
+
+func (mc *TeamMatchStatController) CreateStats(c *fiber.Ctx) error {
+	stats, err := payload.DecodeRows[models.TeamMatchStat](c.Body())
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+
+	if err := mc.service.CreateStats(c.UserContext(), stats); err != nil {
+		return apperr.Wrap(err, "Failed to save team match stats")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(stats)
+}
+
+func (mc *TeamMatchStatController) UpsertStats(c *fiber.Ctx) error {
+	stats, err := payload.DecodeRows[models.TeamMatchStat](c.Body())
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+
+	if err := mc.service.UpsertStats(c.UserContext(), stats); err != nil {
+		return apperr.Wrap(err, "Failed to save team match stats")
+	}
+
+	return c.JSON(stats)
+}
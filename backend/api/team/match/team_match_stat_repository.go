@@ -1,150 +1,98 @@
 package match
 
 import (
+	"context"
 	"errors"
-	"fmt"
-	"strings"
 
+	"github.com/plinphon/StatsBanger/backend/apperr"
 	"github.com/plinphon/StatsBanger/backend/models"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"database/sql"
+	"github.com/plinphon/StatsBanger/backend/database"
+	"github.com/plinphon/StatsBanger/backend/statquery"
 )
 
+var ErrStatNotFound = apperr.New(apperr.ErrNotFound, "team match stat not found")
+
 type TeamMatchStatRepository struct {
 	db *gorm.DB
 }
 
 
-func NewTeamMatchStatRepository(dbPath string) (*TeamMatchStatRepository, error) {
-	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
-	if err != nil {
-		return nil, err
-	}
+func NewTeamMatchStatRepository(db *gorm.DB) *TeamMatchStatRepository {
+	return &TeamMatchStatRepository{db: db}
+}
 
+var teamMatchStatKeys = []string{"match_id", "team_id"}
 
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, err
+func (r *TeamMatchStatRepository) Create(ctx context.Context, stats []models.TeamMatchStat) error {
+	err := database.InsertRows(r.db.WithContext(ctx), "team_match_stat", teamMatchRows(stats))
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrDuplicateMatch
 	}
-	if err := sqlDB.Ping(); err != nil {
-		return nil, err
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return ErrUnknownReference
 	}
-
-	return &TeamMatchStatRepository{db: db}, nil
+	return err
 }
 
-/*
-func (r *TeamMatchStatRepository) Create(stat models.TeamMatchStat) error {
-	query := `
-	INSERT INTO team_match_stat (
-		match_id, team_id, ball_possession, expected_goals, big_chances, total_shots,
-		goalkeeper_saves, corner_kicks, fouls, passes, tackles, free_kicks, yellow_cards, red_cards,
-		shots_on_target, hit_woodwork, shots_off_target, blocked_shots, shots_inside_box, shots_outside_box,
-		big_chances_scored, big_chances_missed, through_balls, touches_in_penalty_area, fouled_in_final_third,
-		offsides, accurate_passes, throw_ins, final_third_entries, final_third_phase, long_balls, crosses,
-		duels, dispossessed, ground_duels, aerial_duels, dribbles, tackles_won, total_tackles,
-		interceptions, recoveries, clearances, total_saves, goals_prevented, goal_kicks, big_saves,
-		high_claims, punches, errors_lead_to_a_shot, errors_lead_to_a_goal, penalty_saves
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-
-	_, err := r.db.Exec(query,
-		stat.MatchId, stat.TeamId, stat.BallPossession, stat.ExpectedGoals, stat.BigChances,
-		stat.TotalShots, stat.GoalkeeperSaves, stat.CornerKicks, stat.Fouls, stat.Passes,
-		stat.Tackles, stat.FreeKicks, stat.YellowCards, stat.RedCards, stat.ShotsOnTarget,
-		stat.HitWoodwork, stat.ShotsOffTarget, stat.BlockedShots, stat.ShotsInsideBox, stat.ShotsOutsideBox,
-		stat.BigChancesScored, stat.BigChancesMissed, stat.ThroughBalls, stat.TouchesInPenaltyArea,
-		stat.FouledInFinalThird, stat.Offsides, stat.AccuratePasses, stat.ThrowIns, stat.FinalThirdEntries,
-		stat.FinalThirdPhase, stat.LongBalls, stat.Crosses, stat.Duels, stat.Dispossessed,
-		stat.GroundDuels, stat.AerialDuels, stat.Dribbles, stat.TacklesWon, stat.TotalTackles,
-		stat.Interceptions, stat.Recoveries, stat.Clearances, stat.TotalSaves, stat.GoalsPrevented,
-		stat.GoalKicks, stat.BigSaves, stat.HighClaims, stat.Punches, stat.ErrorsLeadToAShot,
-		stat.ErrorsLeadToAGoal, stat.PenaltySaves,
-	)
-
+// Upsert inserts stats, overwriting the given stat columns of rows that
+// already exist.
+func (r *TeamMatchStatRepository) Upsert(ctx context.Context, stats []models.TeamMatchStat) error {
+	err := database.UpsertRows(r.db.WithContext(ctx), "team_match_stat", teamMatchStatKeys, teamMatchRows(stats))
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return ErrUnknownReference
+	}
 	return err
 }
-*/
-
-func (r *TeamMatchStatRepository) GetById(matchId int, teamId int, statFields []string) (*models.TeamMatchStat, error) {
-    var stat models.TeamMatchStat
 
-  
-    err := r.db.
-		Preload("Match.HomeTeam").
-		Preload("Match.AwayTeam").
-        Preload("Team").
-        Where("match_id = ? AND team_id = ?", matchId, teamId).
-        First(&stat).Error
-
-    if err != nil {
-        if errors.Is(err, gorm.ErrRecordNotFound) {
-            return nil, errors.New("team match stat not found")
-        }
-        return nil, err
-    }
-
-    if len(statFields) == 0 {
-        statFields = make([]string, 0, len(models.ValidTeamMatchFields))
-        for field := range models.ValidTeamMatchFields {
-            statFields = append(statFields, field)
-        }
-    } else {
-        validFields := make([]string, 0, len(statFields))
-        for _, field := range statFields {
-            if field == "" {
-                continue
-            }
-            if !models.ValidTeamMatchFields[field] {
-                return nil, fmt.Errorf("invalid stat field: %s", field)
-            }
-            validFields = append(validFields, field)
-        }
-        statFields = validFields
-    }
-
-    if len(statFields) == 0 {
-        stat.Stats = map[string]*float64{}
-        return &stat, nil
-    }
-
-    columns := strings.Join(statFields, ", ")
-    query := fmt.Sprintf("SELECT %s FROM team_match_stat WHERE match_id = ? AND team_id = ?", columns)
-
-    row := r.db.Raw(query, matchId, teamId).Row()
-
-    values := make([]sql.NullFloat64, len(statFields))
-    scanTargets := make([]interface{}, len(statFields))
-    for i := range values {
-        scanTargets[i] = &values[i]
-    }
+func teamMatchRows(stats []models.TeamMatchStat) []database.Row {
+	rows := make([]database.Row, len(stats))
+	for i, s := range stats {
+		row := database.Row{"match_id": s.MatchId, "team_id": s.TeamId}
+		for field, value := range s.Stats {
+			row[field] = value
+		}
+		rows[i] = row
+	}
+	return rows
+}
 
-    err = row.Scan(scanTargets...)
-    if err != nil {
-        if err == sql.ErrNoRows {
-            // No stat row found, return empty stats map
-            stat.Stats = map[string]*float64{}
-            return &stat, nil
-        }
-        return nil, fmt.Errorf("failed to scan stat fields: %w", err)
+func (r *TeamMatchStatRepository) GetById(ctx context.Context, matchId int, teamId int, statFields []string) (*models.TeamMatchStat, error) {
+    stat, err := statquery.First(r.db.WithContext(ctx), statquery.TeamMatchStats, statquery.Query{
+        Fields: statFields,
+        Filters: []statquery.Filter{
+            statquery.Eq("match_id", matchId),
+            statquery.Eq("team_id", teamId),
+        },
+    })
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, ErrStatNotFound
     }
+    return stat, err
+}
 
-    statMap := make(map[string]*float64, len(statFields))
-    for i, field := range statFields {
-        if values[i].Valid {
-            val := values[i].Float64
-            statMap[field] = &val
-        }
-    }
-    stat.Stats = statMap
+// GetByMatchIds returns the stats of both teams in the given matches,
+// ordered by match.
+func (r *TeamMatchStatRepository) GetByMatchIds(ctx context.Context, matchIds []int, statFields []string) ([]*models.TeamMatchStat, error) {
+	return statquery.Find(r.db.WithContext(ctx), statquery.TeamMatchStats, statquery.Query{
+		Fields:  statFields,
+		Filters: []statquery.Filter{statquery.In("match_id", matchIds)},
+	})
+}
 
-    return &stat, nil
+// GetByTeamIds returns the stats of the given teams in every match,
+// ordered by match.
+func (r *TeamMatchStatRepository) GetByTeamIds(ctx context.Context, teamIds []int, statFields []string) ([]*models.TeamMatchStat, error) {
+	return statquery.Find(r.db.WithContext(ctx), statquery.TeamMatchStats, statquery.Query{
+		Fields:  statFields,
+		Filters: []statquery.Filter{statquery.In("team_id", teamIds)},
+	})
 }
 
-func (r *TeamMatchStatRepository) GetAllMatchesByTeamID(teamID int) ([]models.TeamMatchStat, error) {
+func (r *TeamMatchStatRepository) GetAllMatchesByTeamID(ctx context.Context, teamID int) ([]models.TeamMatchStat, error) {
     var stats []models.TeamMatchStat
 
-    err := r.db.
+    err := r.db.WithContext(ctx).
 		Preload("Match.HomeTeam").
 		Preload("Match.AwayTeam").
         Preload("Team").
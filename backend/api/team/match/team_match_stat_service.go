@@ -1,35 +1,76 @@
 package match
 
 import (
+	"context"
+	"github.com/plinphon/StatsBanger/backend/apperr"
 	"github.com/plinphon/StatsBanger/backend/models"
-	"errors"
 
 )
 
-var ErrDuplicateMatch = errors.New("duplicate match stat")
+var (
+	ErrDuplicateMatch   = apperr.New(apperr.ErrConflict, "duplicate match stat")
+	ErrInvalidStatIds   = apperr.New(apperr.ErrInvalidArgument, "match and team Ids must be positive")
+	ErrUnknownReference = apperr.New(apperr.ErrInvalidArgument, "the match and team of a stat must exist")
+)
+
+// Repository is the storage TeamMatchStatService reads and writes match stats through.
+type Repository interface {
+	GetById(ctx context.Context, matchId int, teamId int, statFields []string) (*models.TeamMatchStat, error)
+	GetByMatchIds(ctx context.Context, matchIds []int, statFields []string) ([]*models.TeamMatchStat, error)
+	GetByTeamIds(ctx context.Context, teamIds []int, statFields []string) ([]*models.TeamMatchStat, error)
+	GetAllMatchesByTeamID(ctx context.Context, teamID int) ([]models.TeamMatchStat, error)
+	Create(ctx context.Context, stats []models.TeamMatchStat) error
+	Upsert(ctx context.Context, stats []models.TeamMatchStat) error
+}
 
 type TeamMatchStatService struct {
-	repo *TeamMatchStatRepository
+	repo Repository
 }
 
-func NewTeamMatchStatService(repo *TeamMatchStatRepository) *TeamMatchStatService {
+func NewTeamMatchStatService(repo Repository) *TeamMatchStatService {
     return &TeamMatchStatService{repo: repo}
 }
-/*
-func (s *TeamMatchStatService) CreateStat(stat models.TeamMatchStat) error {
 
-	if existing, _ := s.repo.GetByID(stat.MatchID, stat.TeamID); existing != nil {
-		return ErrDuplicateMatch
+func (s *TeamMatchStatService) CreateStats(ctx context.Context, stats []models.TeamMatchStat) error {
+	if err := validateStats(stats); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, stats)
+}
+
+func (s *TeamMatchStatService) UpsertStats(ctx context.Context, stats []models.TeamMatchStat) error {
+	if err := validateStats(stats); err != nil {
+		return err
 	}
+	return s.repo.Upsert(ctx, stats)
+}
+
+func validateStats(stats []models.TeamMatchStat) error {
+	for _, stat := range stats {
+		if stat.MatchId <= 0 || stat.TeamId <= 0 {
+			return ErrInvalidStatIds
+		}
+		if err := models.ValidateStats(stat.Stats, models.TeamMatchStats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (s *TeamMatchStatService) GetStatByID(ctx context.Context, matchID int, teamID int, statFields []string) (*models.TeamMatchStat, error) {
+	return s.repo.GetById(ctx, matchID, teamID, statFields)
+}
 
-	return s.repo.Create(stat)
+// GetStatsByMatchIDs returns both teams' stats in several matches at once.
+func (s *TeamMatchStatService) GetStatsByMatchIDs(ctx context.Context, matchIDs []int, statFields []string) ([]*models.TeamMatchStat, error) {
+	return s.repo.GetByMatchIds(ctx, matchIDs, statFields)
 }
-*/
-func (s *TeamMatchStatService) GetStatByID(matchID int, teamID int, statFields []string) (*models.TeamMatchStat, error) {
-	return s.repo.GetById(matchID, teamID, statFields)
+
+// GetStatsByTeamIDs returns the match stats of several teams at once.
+func (s *TeamMatchStatService) GetStatsByTeamIDs(ctx context.Context, teamIDs []int, statFields []string) ([]*models.TeamMatchStat, error) {
+	return s.repo.GetByTeamIds(ctx, teamIDs, statFields)
 }
 
-func (s *TeamMatchStatService) GetAllMatchesByTeamID(teamID int) ([]models.TeamMatchStat, error) {
-    return s.repo.GetAllMatchesByTeamID(teamID)
+func (s *TeamMatchStatService) GetAllMatchesByTeamID(ctx context.Context, teamID int) ([]models.TeamMatchStat, error) {
+    return s.repo.GetAllMatchesByTeamID(ctx, teamID)
 }
 
@@ -2,11 +2,13 @@ package season
 
 import (
 	"strconv"
-	"log"
 	"github.com/gofiber/fiber/v2"
 	"strings"
 
 	"github.com/plinphon/StatsBanger/backend/models"
+	"github.com/plinphon/StatsBanger/backend/api/payload"
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+	"github.com/plinphon/StatsBanger/backend/apperr"
 )
 
 type TeamSeasonStatController struct {
@@ -50,22 +52,18 @@ func (tc *TeamSeasonStatController) GetTeamStatsWithMeta(c *fiber.Ctx) error {
 	statFieldsQuery := c.Query("statFields", "")
 	var statFields []string
 	if statFieldsQuery == "" {
-		statFields = make([]string, 0, len(models.ValidTeamSeasonFields))
-		for field := range models.ValidTeamSeasonFields {
-			statFields = append(statFields, field)
-		}
+		statFields = models.TeamSeasonStats.Fields()
 	} else {
 		statFields = strings.Split(statFieldsQuery, ",")
 	}
 
 	// Call service with teamIDs
-	teamStats, err := tc.service.GetTeamStatsWithMeta(statFields, uniqueTournamentID, seasonID, teamIDs)
+	teamStats, err := tc.service.GetTeamStatsWithMeta(c.UserContext(), statFields, uniqueTournamentID, seasonID, teamIDs)
 	if err != nil {
-		log.Printf("❌ Error getting team stats: %v", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get team stats")
+		return apperr.Wrap(err, "Failed to get team stats")
 	}
 
-	return c.JSON(teamStats)
+	return tabular.Send(c, "team-season-stats", teamStats)
 }
 
 func (mc *TeamSeasonStatController) GetTopTeamsByStat(c *fiber.Ctx) error {
@@ -97,12 +95,36 @@ func (mc *TeamSeasonStatController) GetTopTeamsByStat(c *fiber.Ctx) error {
 		}
 	}
 
-	topTeams, err := mc.service.GetTopTeamsByStat(statName, uniqueTournamentID, seasonID, limit)
+	topTeams, err := mc.service.GetTopTeamsByStat(c.UserContext(), statName, uniqueTournamentID, seasonID, limit)
 	if err != nil {
-		log.Printf("❌ Error getting top teams by stat: %v", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get top teams by stat")
+		return apperr.Wrap(err, "Failed to get top teams by stat")
+	}
+
+	return tabular.Send(c, "top-teams", topTeams)
+}
+
+func (tc *TeamSeasonStatController) CreateStats(c *fiber.Ctx) error {
+	stats, err := payload.DecodeRows[models.TeamSeasonStat](c.Body())
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+
+	if err := tc.service.CreateStats(c.UserContext(), stats); err != nil {
+		return apperr.Wrap(err, "Failed to save team season stats")
 	}
 
-	return c.JSON(topTeams)
+	return c.Status(fiber.StatusCreated).JSON(stats)
 }
 
+func (tc *TeamSeasonStatController) UpsertStats(c *fiber.Ctx) error {
+	stats, err := payload.DecodeRows[models.TeamSeasonStat](c.Body())
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+
+	if err := tc.service.UpsertStats(c.UserContext(), stats); err != nil {
+		return apperr.Wrap(err, "Failed to save team season stats")
+	}
+
+	return c.JSON(stats)
+}
@@ -1,38 +1,68 @@
 package season
 
 import (
+	"context"
+	"github.com/plinphon/StatsBanger/backend/apperr"
 	"github.com/plinphon/StatsBanger/backend/models"
-	"errors"
 
 
 )
 
-var ErrDuplicateSeasonStat = errors.New("duplicate season stat")
+var (
+	ErrDuplicateSeasonStat = apperr.New(apperr.ErrConflict, "duplicate season stat")
+	ErrInvalidStatIds      = apperr.New(apperr.ErrInvalidArgument, "team, tournament and season Ids must be positive")
+	ErrUnknownReference    = apperr.New(apperr.ErrInvalidArgument, "the team, tournament and season of a stat must exist")
+)
+
+// Repository is the storage TeamSeasonStatService reads and writes season stats through.
+type Repository interface {
+	GetMultipleStatsByTeamId(ctx context.Context, statFields []string, uniqueTournamentId int, seasonId int, teamIds []int) ([]*models.TeamSeasonStat, error)
+	GetTopTeamsByStat(ctx context.Context, statField string, uniqueTournamentId int, seasonId int, limit int) ([]models.TopTeamStatResult, error)
+	Create(ctx context.Context, stats []models.TeamSeasonStat) error
+	Upsert(ctx context.Context, stats []models.TeamSeasonStat) error
+}
 
 type TeamSeasonStatService struct {
-	repo *TeamSeasonStatRepository
+	repo Repository
 }
 
-func NewTeamSeasonStatService(repo *TeamSeasonStatRepository) *TeamSeasonStatService {
+func NewTeamSeasonStatService(repo Repository) *TeamSeasonStatService {
     return &TeamSeasonStatService{repo: repo}
 }
-/*
-func (s *TeamSeasonStatService) CreateStat(stat models.TeamSeasonStat) error {
 
-	if existing, _ := s.repo.GetByID(stat.UniqueTournamentID, stat.SeasonID, stat.TeamID); existing != nil {
-		return ErrDuplicateSeasonStat
+func (s *TeamSeasonStatService) CreateStats(ctx context.Context, stats []models.TeamSeasonStat) error {
+	if err := validateStats(stats); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, stats)
+}
+
+func (s *TeamSeasonStatService) UpsertStats(ctx context.Context, stats []models.TeamSeasonStat) error {
+	if err := validateStats(stats); err != nil {
+		return err
 	}
+	return s.repo.Upsert(ctx, stats)
+}
 
-	return s.repo.Create(stat)
+func validateStats(stats []models.TeamSeasonStat) error {
+	for _, stat := range stats {
+		if stat.TeamID <= 0 || stat.UniqueTournamentID <= 0 || stat.SeasonID <= 0 {
+			return ErrInvalidStatIds
+		}
+		if err := models.ValidateStats(stat.Stats, models.TeamSeasonStats); err != nil {
+			return err
+		}
+	}
+	return nil
 }
-*/
 func (s *TeamSeasonStatService) GetTeamStatsWithMeta(
+	ctx context.Context,
 	statFields []string,
 	tournamentId int,
 	seasonId int,
 	teamIds []int,
 ) ([]*models.TeamSeasonStat, error) {
-	stats, err := s.repo.GetMultipleStatsByTeamId(statFields, tournamentId, seasonId, teamIds)
+	stats, err := s.repo.GetMultipleStatsByTeamId(ctx, statFields, tournamentId, seasonId, teamIds)
 	if err != nil {
 		return nil, err
 	}
@@ -42,11 +72,12 @@ func (s *TeamSeasonStatService) GetTeamStatsWithMeta(
 
 
 func (s *TeamSeasonStatService) GetTopTeamsByStat(
+	ctx context.Context,
     statField string,
     uniqueTournamentId int,
     seasonId int,
     limit int,
 ) ([]models.TopTeamStatResult, error) {
-    return s.repo.GetTopTeamsByStat(statField, uniqueTournamentId, seasonId, limit)
+    return s.repo.GetTopTeamsByStat(ctx, statField, uniqueTournamentId, seasonId, limit)
 }
 
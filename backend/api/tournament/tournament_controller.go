@@ -0,0 +1,41 @@
+package tournament
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+	"github.com/plinphon/StatsBanger/backend/apperr"
+)
+
+type TournamentController struct {
+	service *TournamentService
+}
+
+func NewTournamentController(service *TournamentService) *TournamentController {
+	return &TournamentController{service: service}
+}
+
+func (tc *TournamentController) GetTournaments(c *fiber.Ctx) error {
+	tournaments, err := tc.service.GetTournaments(c.UserContext())
+	if err != nil {
+		return apperr.Wrap(err, "Failed to get tournaments")
+	}
+
+	return tabular.Send(c, "tournaments", tournaments)
+}
+
+func (tc *TournamentController) GetSeasons(c *fiber.Ctx) error {
+	tournamentID, err := strconv.Atoi(c.Params("tournamentID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid tournament ID")
+	}
+
+	seasons, err := tc.service.GetSeasons(c.UserContext(), tournamentID)
+	if err != nil {
+		return apperr.Wrap(err, "Failed to get seasons")
+	}
+
+	return tabular.Send(c, "seasons", seasons)
+}
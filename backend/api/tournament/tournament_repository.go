@@ -0,0 +1,158 @@
+package tournament
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+type TournamentRepository struct {
+	db *gorm.DB
+}
+
+func NewTournamentRepository(db *gorm.DB) *TournamentRepository {
+	return &TournamentRepository{db: db}
+}
+
+type seasonKey struct {
+	UniqueTournamentId int
+	SeasonId           int
+}
+
+// GetSeasons returns every season that has matches, player stats or team
+// stats, with its name and match counts but without years or the current
+// flag. A uniqueTournamentId of 0 means every tournament.
+func (r *TournamentRepository) GetSeasons(ctx context.Context, uniqueTournamentId int) ([]models.Season, error) {
+	db := r.db.WithContext(ctx)
+	scope := func(tx *gorm.DB) *gorm.DB {
+		if uniqueTournamentId > 0 {
+			tx = tx.Where("unique_tournament_id = ?", uniqueTournamentId)
+		}
+		return tx
+	}
+
+	var order []seasonKey
+	seasons := make(map[seasonKey]*models.Season)
+	season := func(key seasonKey) *models.Season {
+		s, ok := seasons[key]
+		if !ok {
+			s = &models.Season{Id: key.SeasonId, UniqueTournamentId: key.UniqueTournamentId}
+			seasons[key] = s
+			order = append(order, key)
+		}
+		return s
+	}
+
+	var matches []struct {
+		UniqueTournamentId int
+		SeasonId           int
+		Matches            int
+		PlayedMatches      int
+		FirstMatch         time.Time `gorm:"serializer:unixseconds"`
+		LastMatch          time.Time `gorm:"serializer:unixseconds"`
+	}
+	err := db.Table("match_info").
+		Scopes(scope).
+		Select("unique_tournament_id, season_id, COUNT(*) AS matches, COUNT(home_score) AS played_matches, " +
+			"MIN(current_period_start_timestamp) AS first_match, MAX(current_period_start_timestamp) AS last_match").
+		Group("unique_tournament_id, season_id").
+		Scan(&matches).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range matches {
+		s := season(seasonKey{m.UniqueTournamentId, m.SeasonId})
+		s.Matches, s.PlayedMatches = m.Matches, m.PlayedMatches
+		if !m.FirstMatch.IsZero() {
+			s.FirstMatch, s.LastMatch = &m.FirstMatch, &m.LastMatch
+		}
+	}
+
+	for _, table := range []string{"player_stat", "team_stat"} {
+		var keys []seasonKey
+		err := db.Table(table).
+			Scopes(scope).
+			Distinct("unique_tournament_id", "season_id").
+			Scan(&keys).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			s := season(key)
+			if table == "player_stat" {
+				s.HasPlayerStats = true
+			} else {
+				s.HasTeamStats = true
+			}
+		}
+	}
+	if len(order) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int, len(order))
+	for i, key := range order {
+		ids[i] = key.SeasonId
+	}
+	var names []struct {
+		SeasonId   int
+		SeasonName *string
+		Year       *string
+	}
+	err = db.Table("season_info").
+		Select("season_id, season_name, year").
+		Where("season_id IN ?", ids).
+		Scan(&names).Error
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[int]int, len(names))
+	for i, n := range names {
+		byId[n.SeasonId] = i
+	}
+
+	out := make([]models.Season, len(order))
+	for i, key := range order {
+		s := seasons[key]
+		if j, ok := byId[key.SeasonId]; ok {
+			s.Name = deref(names[j].SeasonName)
+			s.Year = deref(names[j].Year)
+		}
+		out[i] = *s
+	}
+	return out, nil
+}
+
+// GetTournaments returns the name and country of the given tournaments.
+// Tournaments missing from unique_tournament_info are left out.
+func (r *TournamentRepository) GetTournaments(ctx context.Context, ids []int) ([]models.Tournament, error) {
+	var rows []struct {
+		UniqueTournamentId int
+		TournamentName     *string
+		Country            *string
+	}
+	err := r.db.WithContext(ctx).
+		Table("unique_tournament_info").
+		Select("unique_tournament_id, tournament_name, country").
+		Where("unique_tournament_id IN ?", ids).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tournaments := make([]models.Tournament, len(rows))
+	for i, row := range rows {
+		tournaments[i] = models.Tournament{Id: row.UniqueTournamentId, Name: deref(row.TournamentName), Country: deref(row.Country)}
+	}
+	return tournaments, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,162 @@
+package tournament
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+var (
+	ErrTournamentNotFound  = apperr.New(apperr.ErrNotFound, "tournament not found")
+	ErrInvalidTournamentId = apperr.New(apperr.ErrInvalidArgument, "tournament Id must be positive")
+)
+
+// Repository is the storage TournamentService reads the catalog through.
+type Repository interface {
+	GetSeasons(ctx context.Context, uniqueTournamentId int) ([]models.Season, error)
+	GetTournaments(ctx context.Context, ids []int) ([]models.Tournament, error)
+}
+
+type TournamentService struct {
+	repo Repository
+}
+
+func NewTournamentService(repo Repository) *TournamentService {
+	return &TournamentService{repo: repo}
+}
+
+// GetTournaments returns every tournament with matches or stats, ordered
+// by ID.
+func (s *TournamentService) GetTournaments(ctx context.Context) ([]models.Tournament, error) {
+	seasons, err := s.repo.GetSeasons(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	byTournament := make(map[int][]models.Season)
+	var ids []int
+	for _, season := range seasons {
+		if _, ok := byTournament[season.UniqueTournamentId]; !ok {
+			ids = append(ids, season.UniqueTournamentId)
+		}
+		byTournament[season.UniqueTournamentId] = append(byTournament[season.UniqueTournamentId], season)
+	}
+	sort.Ints(ids)
+	if len(ids) == 0 {
+		return []models.Tournament{}, nil
+	}
+
+	named, err := s.repo.GetTournaments(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]models.Tournament, len(named))
+	for _, t := range named {
+		names[t.Id] = t
+	}
+
+	tournaments := make([]models.Tournament, len(ids))
+	for i, id := range ids {
+		t := names[id]
+		t.Id = id
+		seasons := byTournament[id]
+		rank(seasons)
+		t.Seasons = len(seasons)
+		t.CurrentSeasonId = seasons[0].Id
+		for _, season := range seasons {
+			t.Matches += season.Matches
+			if season.StartYear > 0 && (t.StartYear == 0 || season.StartYear < t.StartYear) {
+				t.StartYear = season.StartYear
+			}
+			if season.EndYear > t.EndYear {
+				t.EndYear = season.EndYear
+			}
+		}
+		tournaments[i] = t
+	}
+	return tournaments, nil
+}
+
+// GetSeasons returns the seasons of a tournament, newest first; the first
+// is the current one.
+func (s *TournamentService) GetSeasons(ctx context.Context, uniqueTournamentId int) ([]models.Season, error) {
+	if uniqueTournamentId <= 0 {
+		return nil, ErrInvalidTournamentId
+	}
+	seasons, err := s.repo.GetSeasons(ctx, uniqueTournamentId)
+	if err != nil {
+		return nil, err
+	}
+	if len(seasons) == 0 {
+		return nil, ErrTournamentNotFound
+	}
+	rank(seasons)
+	return seasons, nil
+}
+
+// rank fills in the years of the seasons of one tournament, sorts them
+// newest first and marks the first as current. Seasons are compared by
+// start year, then by first match, then by ID.
+func rank(seasons []models.Season) {
+	for i := range seasons {
+		seasons[i].StartYear, seasons[i].EndYear = years(seasons[i])
+		seasons[i].Current = false
+	}
+	sort.SliceStable(seasons, func(i, j int) bool {
+		a, b := seasons[i], seasons[j]
+		if a.StartYear != b.StartYear {
+			return a.StartYear > b.StartYear
+		}
+		if a.FirstMatch != nil && b.FirstMatch != nil && !a.FirstMatch.Equal(*b.FirstMatch) {
+			return a.FirstMatch.After(*b.FirstMatch)
+		}
+		if (a.FirstMatch == nil) != (b.FirstMatch == nil) {
+			return a.FirstMatch != nil
+		}
+		return a.Id > b.Id
+	})
+	seasons[0].Current = true
+}
+
+// years returns the calendar years a season spans. Its year label comes
+// as "2024", "2023/2024" or "23/24"; two-digit years take the century of
+// its first match, or the 2000s. Without a label that parses, the years
+// of its first and last matches are used.
+func years(s models.Season) (int, int) {
+	century := 2000
+	if s.FirstMatch != nil {
+		century = s.FirstMatch.Year() / 100 * 100
+	}
+
+	var parsed []int
+	for _, part := range strings.FieldsFunc(s.Year, func(r rune) bool { return r == '/' || r == '-' }) {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 {
+			parsed = nil
+			break
+		}
+		if n < 100 {
+			n += century
+		}
+		parsed = append(parsed, n)
+	}
+	switch len(parsed) {
+	case 1:
+		return parsed[0], parsed[0]
+	case 2:
+		start, end := parsed[0], parsed[1]
+		if end < start {
+			// "99/00"
+			end += 100
+		}
+		return start, end
+	}
+
+	if s.FirstMatch != nil {
+		return s.FirstMatch.Year(), s.LastMatch.Year()
+	}
+	return 0, 0
+}
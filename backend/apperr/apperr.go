@@ -0,0 +1,83 @@
+// Package apperr classifies the errors the API returns. Services and
+// repositories create errors of a Kind; the HTTP error handler maps the
+// kind to a status code, so controllers pass errors through instead of
+// choosing a status themselves.
+package apperr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of error. Test for them with errors.Is.
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrConflict        = errors.New("conflict")
+)
+
+// Error is an error of a kind, or an internal error with a message that is
+// safe to show to clients.
+type Error struct {
+	// Kind is ErrNotFound, ErrInvalidArgument, ErrConflict or nil for an
+	// internal error.
+	Kind    error
+	Message string
+	// Err is the underlying cause, if any.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// New returns an error of kind. It is meant for package-level sentinels
+// such as ErrMatchNotFound, which callers still match with errors.Is.
+func New(kind error, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+func NotFound(format string, args ...interface{}) error {
+	return New(ErrNotFound, fmt.Sprintf(format, args...))
+}
+
+func InvalidArgument(format string, args ...interface{}) error {
+	return New(ErrInvalidArgument, fmt.Sprintf(format, args...))
+}
+
+func Conflict(format string, args ...interface{}) error {
+	return New(ErrConflict, fmt.Sprintf(format, args...))
+}
+
+// Wrap marks an unclassified err as internal, with message as what
+// clients see. Errors that already have a kind are returned unchanged.
+func Wrap(err error, message string) error {
+	if err == nil || Kind(err) != nil {
+		return err
+	}
+	return &Error{Message: message, Err: err}
+}
+
+// Kind returns the kind of err, or nil for internal errors.
+func Kind(err error) error {
+	for _, kind := range []error{ErrNotFound, ErrInvalidArgument, ErrConflict} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
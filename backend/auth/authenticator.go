@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+)
+
+// cacheTTL is how long a looked-up key is trusted before it is read
+// again, and so how long a revoked key keeps working at most.
+const cacheTTL = 30 * time.Second
+
+// KeyStore looks up keys by ID.
+type KeyStore interface {
+	GetByID(ctx context.Context, id string) (*Key, error)
+}
+
+// Authenticator resolves presented tokens to keys, caching lookups.
+type Authenticator struct {
+	store KeyStore
+	now   func() time.Time
+
+	mu        sync.Mutex
+	cache     map[string]cachedKey
+	lastSweep time.Time
+}
+
+type cachedKey struct {
+	key     *Key
+	expires time.Time
+}
+
+func NewAuthenticator(store KeyStore) *Authenticator {
+	return &Authenticator{store: store, now: time.Now, cache: make(map[string]cachedKey)}
+}
+
+// Authenticate returns the key token belongs to, or ErrInvalidKey when
+// the token is malformed, unknown, wrong or revoked.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (*Key, error) {
+	id, err := ParseToken(token)
+	if err != nil {
+		return nil, err
+	}
+	key, err := a.lookup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || key.Revoked() ||
+		subtle.ConstantTimeCompare([]byte(HashToken(token)), []byte(key.Hash)) != 1 {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+func (a *Authenticator) lookup(ctx context.Context, id string) (*Key, error) {
+	now := a.now()
+
+	a.mu.Lock()
+	cached, ok := a.cache[id]
+	a.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.key, nil
+	}
+
+	key, err := a.store.GetByID(ctx, id)
+	if errors.Is(err, apperr.ErrNotFound) {
+		// Unknown IDs are not cached: anyone can make up new ones.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	if now.Sub(a.lastSweep) >= cacheTTL {
+		for cachedID, c := range a.cache {
+			if !now.Before(c.expires) {
+				delete(a.cache, cachedID)
+			}
+		}
+		a.lastSweep = now
+	}
+	a.cache[id] = cachedKey{key: key, expires: now.Add(cacheTTL)}
+	a.mu.Unlock()
+	return key, nil
+}
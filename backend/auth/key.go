@@ -0,0 +1,121 @@
+// Package auth authenticates API clients. Clients present API keys that are
+// stored hashed in the api_key table; each key has scopes and its own rate
+// limit and daily quota. Requests without a key are anonymous.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Scopes a key can be granted. ScopeAdmin grants the server's metrics.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+// tokenPrefix starts every key, so leaked keys are easy to recognise.
+const tokenPrefix = "sb_"
+
+// ErrInvalidKey is returned for keys that are malformed, unknown or revoked.
+var ErrInvalidKey = errors.New("invalid API key")
+
+// Key is a row of api_key.
+type Key struct {
+	// ID is the public part of the key; it is safe to log.
+	ID   string `gorm:"primaryKey;column:key_id" json:"id"`
+	Name string `gorm:"column:name" json:"name"`
+	Hash string `gorm:"column:key_hash" json:"-"`
+	// Scopes is a comma-separated list such as "read,write".
+	Scopes string `gorm:"column:scopes" json:"scopes"`
+	// RateLimit is the number of requests allowed per minute and
+	// DailyQuota the number per day; 0 means unlimited.
+	RateLimit  int        `gorm:"column:rate_limit" json:"rateLimit"`
+	DailyQuota int        `gorm:"column:daily_quota" json:"dailyQuota"`
+	CreatedAt  time.Time  `gorm:"column:created_at;serializer:unixseconds" json:"createdAt"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at;serializer:unixseconds" json:"revokedAt,omitempty"`
+}
+
+func (Key) TableName() string {
+	return "api_key"
+}
+
+// Has reports whether k grants scope. Write keys may also read.
+func (k *Key) Has(scope string) bool {
+	for _, s := range strings.Split(k.Scopes, ",") {
+		if s == scope || (s == ScopeWrite && scope == ScopeRead) {
+			return true
+		}
+	}
+	return false
+}
+
+// Revoked reports whether k has been revoked.
+func (k *Key) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// ParseScopes validates a comma-separated scope list and returns it in
+// canonical form.
+func ParseScopes(list string) (string, error) {
+	granted := make(map[string]bool)
+	for _, s := range strings.Split(list, ",") {
+		switch s = strings.TrimSpace(s); s {
+		case ScopeRead, ScopeWrite, ScopeAdmin:
+			granted[s] = true
+		case "":
+		default:
+			return "", fmt.Errorf("unknown scope %q (want read, write or admin)", s)
+		}
+	}
+	var scopes []string
+	for _, s := range []string{ScopeRead, ScopeWrite, ScopeAdmin} {
+		if granted[s] {
+			scopes = append(scopes, s)
+		}
+	}
+	if len(scopes) == 0 {
+		return "", errors.New("at least one scope is required")
+	}
+	return strings.Join(scopes, ","), nil
+}
+
+// NewToken returns a new random key: sb_<id>_<secret>.
+func NewToken() (id, token string, err error) {
+	idBytes := make([]byte, 6)
+	secret := make([]byte, 24)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	id = hex.EncodeToString(idBytes)
+	return id, tokenPrefix + id + "_" + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// ParseToken returns the key ID of token.
+func ParseToken(token string) (string, error) {
+	rest, ok := strings.CutPrefix(token, tokenPrefix)
+	if !ok {
+		return "", ErrInvalidKey
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return "", ErrInvalidKey
+	}
+	return id, nil
+}
+
+// HashToken returns the hash stored for token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
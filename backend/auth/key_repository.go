@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+)
+
+// ErrKeyNotFound is returned by Revoke for unknown key IDs.
+var ErrKeyNotFound = apperr.New(apperr.ErrNotFound, "API key not found")
+
+type KeyRepository struct {
+	db *gorm.DB
+}
+
+func NewKeyRepository(db *gorm.DB) *KeyRepository {
+	return &KeyRepository{db: db}
+}
+
+// Create stores key under the hash of token. key.ID must be the ID of
+// token.
+func (r *KeyRepository) Create(ctx context.Context, key *Key, token string) error {
+	id, err := ParseToken(token)
+	if err != nil || id != key.ID {
+		return errors.New("token does not belong to key " + key.ID)
+	}
+	key.Hash = HashToken(token)
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	}
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// GetByID returns the key with id, revoked or not.
+func (r *KeyRepository) GetByID(ctx context.Context, id string) (*Key, error) {
+	var key Key
+	err := r.db.WithContext(ctx).First(&key, "key_id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// List returns every key ordered by creation time.
+func (r *KeyRepository) List(ctx context.Context) ([]Key, error) {
+	var keys []Key
+	err := r.db.WithContext(ctx).Order("created_at, key_id").Find(&keys).Error
+	return keys, err
+}
+
+// Revoke marks the key with id as revoked. Revoking a revoked key keeps
+// the original revocation time.
+func (r *KeyRepository) Revoke(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Model(&Key{}).
+		Where("key_id = ?", id).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", time.Now().Unix())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
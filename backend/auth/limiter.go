@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter counts requests per client in fixed windows that open with the
+// first request of the client. Counts live in
+// memory, so every replica enforces limits on its own and a restart
+// resets them.
+type Limiter struct {
+	period time.Duration
+
+	mu        sync.Mutex
+	windows   map[string]*window
+	lastSweep time.Time
+}
+
+type window struct {
+	start time.Time
+	count int
+}
+
+// Usage is the state of a client's window after a request was counted.
+type Usage struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// NewLimiter returns a Limiter with windows of period.
+func NewLimiter(period time.Duration) *Limiter {
+	return &Limiter{period: period, windows: make(map[string]*window)}
+}
+
+// Allow counts a request of client at now against limit and reports
+// whether it is within the limit. A limit of 0 or less allows everything.
+func (l *Limiter) Allow(client string, limit int, now time.Time) (Usage, bool) {
+	if limit <= 0 {
+		return Usage{}, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	w, ok := l.windows[client]
+	if !ok || now.Sub(w.start) >= l.period {
+		w = &window{start: now}
+		l.windows[client] = w
+	}
+	usage := Usage{Limit: limit, Reset: w.start.Add(l.period)}
+	if w.count >= limit {
+		return usage, false
+	}
+	w.count++
+	usage.Remaining = limit - w.count
+	return usage, true
+}
+
+// Refund takes back a request of client counted by Allow at now, for
+// requests rejected by a later check.
+func (l *Limiter) Refund(client string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if w, ok := l.windows[client]; ok && w.count > 0 && now.Sub(w.start) < l.period {
+		w.count--
+	}
+}
+
+// sweep drops expired windows, at most once per period, so clients that
+// went away do not pile up.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.period {
+		return
+	}
+	for client, w := range l.windows {
+		if now.Sub(w.start) >= l.period {
+			delete(l.windows, client)
+		}
+	}
+	l.lastSweep = now
+}
@@ -0,0 +1,215 @@
+// Package client is a Go client of the StatsBanger HTTP API. Its methods
+// map one to one to the REST endpoints and return the models the server
+// serializes; failed requests return an *Error whose kind can be tested
+// with errors.Is, as on the server.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// userAgent identifies the client in the server's access log.
+const userAgent = "statsbanger-go-client"
+
+// Retry is a retry policy. Requests are retried after network errors and
+// 502, 503 and 504 responses if they are idempotent, and after 429
+// responses whatever their method, since the server rejected them before
+// doing anything.
+type Retry struct {
+	// MaxAttempts is the number of tries of a request, the first one
+	// included; 1 disables retries.
+	MaxAttempts int
+	// MinBackoff is the longest wait before the first retry. Each retry
+	// doubles it, up to MaxBackoff, and waits a random part of it. A
+	// Retry-After header is honored instead when it is at most
+	// MaxBackoff; a longer one, such as an exhausted daily quota, ends
+	// the retries.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetry is the policy of clients created without WithRetry.
+var DefaultRetry = Retry{MaxAttempts: 4, MinBackoff: 250 * time.Millisecond, MaxBackoff: 10 * time.Second}
+
+// Client calls the API at one base URL. It is safe for concurrent use.
+type Client struct {
+	base   *url.URL
+	http   *http.Client
+	apiKey string
+	retry  Retry
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends requests through hc instead of http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithAPIKey authenticates every request with key. Reads work without a
+// key unless the server disables anonymous reads; writes need a key with
+// the write scope.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithRetry replaces DefaultRetry.
+func WithRetry(r Retry) Option {
+	return func(c *Client) { c.retry = r }
+}
+
+// New returns a client of the API served at baseURL, such as
+// https://api.statsbanger.com.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("client: invalid base URL: %w", err)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, fmt.Errorf("client: invalid base URL %q: want an http or https URL", baseURL)
+	}
+	c := &Client{base: base, http: http.DefaultClient, retry: DefaultRetry}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c, nil
+}
+
+// get decodes the response to GET path?query into out.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	return c.do(ctx, http.MethodGet, path, query, nil, out)
+}
+
+// do sends a request, retrying it as the policy allows, and decodes a
+// successful response into out unless out is nil. An io.Writer out gets
+// the body as it is. GET and PUT requests are idempotent.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	idempotent := method == http.MethodGet || method == http.MethodPut
+	return c.call(ctx, method, path, query, in, out, idempotent)
+}
+
+func (c *Client) call(ctx context.Context, method, path string, query url.Values, in, out any, idempotent bool) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("client: encode request: %w", err)
+		}
+	}
+
+	u := c.base.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, method, u.String(), body)
+		if err == nil && resp.StatusCode < 300 {
+			return decode(resp, out)
+		}
+
+		var wait time.Duration
+		retry := false
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			retry = idempotent
+		} else {
+			err = readError(resp)
+			var apiErr *Error
+			errors.As(err, &apiErr)
+			switch apiErr.Status {
+			case http.StatusTooManyRequests:
+				retry = true
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				retry = idempotent
+			}
+			wait = apiErr.RetryAfter
+		}
+		if !retry || attempt >= c.retry.MaxAttempts || wait > c.retry.MaxBackoff {
+			return err
+		}
+
+		if wait == 0 {
+			wait = c.backoff(attempt)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	return c.http.Do(req)
+}
+
+// backoff returns the wait before retry number attempt, with full jitter.
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.retry.MinBackoff << (attempt - 1)
+	if ceiling <= 0 || ceiling > c.retry.MaxBackoff {
+		ceiling = c.retry.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(ceiling))) + 1
+}
+
+func decode(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+	if out == nil {
+		_, err := io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	if w, ok := out.(io.Writer); ok {
+		_, err := io.Copy(w, resp.Body)
+		return err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("client: decode response: %w", err)
+	}
+	return nil
+}
+
+// ints formats IDs as the comma separated lists the API takes.
+func ints(ids []int) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.Itoa(id)
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,245 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	"github.com/plinphon/StatsBanger/backend/auth"
+	"github.com/plinphon/StatsBanger/backend/client"
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/container"
+	"github.com/plinphon/StatsBanger/backend/fixture"
+	"github.com/plinphon/StatsBanger/backend/middleware"
+	"github.com/plinphon/StatsBanger/backend/models"
+	"github.com/plinphon/StatsBanger/backend/routes"
+)
+
+const writeKey = "sb_clientwriter_c2VjcmV0"
+
+var season = client.Season{UniqueTournamentID: fixture.TournamentID, SeasonID: fixture.SeasonID}
+
+// fastRetry keeps retried tests quick.
+var fastRetry = client.Retry{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+// newServer serves the API over the fixture database and returns its URL.
+func newServer(t *testing.T) string {
+	t.Helper()
+
+	c := container.NewWithDB(config.Default(), fixture.Open(t))
+	t.Cleanup(func() { c.Close() })
+	key := &auth.Key{ID: "clientwriter", Name: "client tests", Scopes: auth.ScopeWrite}
+	if err := c.Keys.Create(context.Background(), key, writeKey); err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(middleware.RequestID())
+	routes.SetupRoutes(app, c)
+
+	srv := httptest.NewServer(adaptor.FiberApp(app))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func newClient(t *testing.T, url string, opts ...client.Option) *client.Client {
+	t.Helper()
+	c, err := client.New(url, append([]client.Option{client.WithRetry(fastRetry)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestReads(t *testing.T) {
+	c := newClient(t, newServer(t))
+	ctx := context.Background()
+
+	match, err := c.GetMatch(ctx, fixture.AthleticBetisID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match.HomeTeamId != fixture.AthleticID || match.AwayTeam.TeamId != fixture.BetisID || match.CurrentPeriodStartTimestamp.IsZero() {
+		t.Errorf("match = %+v", match)
+	}
+
+	played := false
+	fixtures, err := c.ListMatches(ctx, client.MatchQuery{TeamID: fixture.RealMadridID, Side: "home", Played: &played})
+	if err != nil || len(fixtures) != 1 || fixtures[0].HomeScore != nil || fixtures[0].AwayTeam.TeamName == "" {
+		t.Errorf("ListMatches = %+v, %v", fixtures, err)
+	}
+
+	player, err := c.GetPlayer(ctx, fixture.BellinghamID)
+	if err != nil || player.PlayerName != "Jude Bellingham" {
+		t.Errorf("GetPlayer = %+v, %v", player, err)
+	}
+	teams, err := c.SearchTeams(ctx, "madrid")
+	if err != nil || len(teams) != 1 || teams[0].TeamId != fixture.RealMadridID {
+		t.Errorf("SearchTeams = %+v, %v", teams, err)
+	}
+
+	stat, err := c.TeamMatchStat(ctx, fixture.AthleticBetisID, fixture.AthleticID, "ball_possession")
+	if err != nil || len(stat.Stats) != 1 || *stat.Stats["ball_possession"] != 52 {
+		t.Errorf("TeamMatchStat = %+v, %v", stat, err)
+	}
+
+	ratings, err := c.PlayerMatchStats(ctx, fixture.AthleticRealMadridID, "rating")
+	if err != nil || len(ratings) != 6 {
+		t.Fatalf("PlayerMatchStats = %d rows, %v", len(ratings), err)
+	}
+	for _, r := range ratings {
+		if r.Stats["rating"] == nil || r.Stats["goals"] != nil {
+			t.Errorf("player %d: stats = %v", r.PlayerId, r.Stats)
+		}
+	}
+
+	seasonStats, err := c.PlayerSeasonStats(ctx, season, []int{fixture.BellinghamID, fixture.ViniciusID}, "goals")
+	if err != nil || len(seasonStats) != 2 {
+		t.Errorf("PlayerSeasonStats = %+v, %v", seasonStats, err)
+	}
+
+	top, err := c.TopPlayersByStat(ctx, season, "goals", 1, "M")
+	if err != nil || len(top) != 1 || top[0].PlayerID != fixture.BellinghamID {
+		t.Errorf("TopPlayersByStat = %+v, %v", top, err)
+	}
+
+	tournaments, err := c.Tournaments(ctx)
+	if err != nil || len(tournaments) != 1 || tournaments[0].CurrentSeasonId != fixture.SeasonID {
+		t.Errorf("Tournaments = %+v, %v", tournaments, err)
+	}
+	if current, err := c.CurrentSeason(ctx, fixture.TournamentID); err != nil || current != season {
+		t.Errorf("CurrentSeason = %+v, %v", current, err)
+	}
+
+	stats, err := c.Stats(ctx, models.TeamMatchStats.Entity)
+	if err != nil || len(stats) != len(models.TeamMatchStats.Fields()) || stats[0].Field != "ball_possession" {
+		t.Errorf("Stats = %+v, %v", stats, err)
+	}
+
+	var data struct {
+		Match struct{ HomeTeam struct{ Name string } }
+	}
+	err = c.GraphQL(ctx, `query($id: Int!) { match(id: $id) { homeTeam { name } } }`,
+		map[string]any{"id": fixture.AthleticBetisID}, &data)
+	if err != nil || data.Match.HomeTeam.Name != "Athletic Bilbao" {
+		t.Errorf("GraphQL = %+v, %v", data, err)
+	}
+	var gqlErr *client.GraphQLError
+	if err := c.GraphQL(ctx, `{ nosuchfield }`, nil, nil); !errors.As(err, &gqlErr) {
+		t.Errorf("invalid GraphQL query: %v", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	url := newServer(t)
+	c := newClient(t, url)
+	ctx := context.Background()
+
+	_, err := c.GetMatch(ctx, 1)
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("GetMatch(1) = %v, want a not found *Error", err)
+	}
+	if apiErr.Status != http.StatusNotFound || apiErr.Detail != "match not found" ||
+		apiErr.Instance != "/api/match/1" || apiErr.RequestID == "" {
+		t.Errorf("error = %+v", apiErr)
+	}
+
+	if _, err := c.PlayerMatchStats(ctx, fixture.AthleticBetisID, "bogus"); !errors.Is(err, client.ErrInvalidArgument) {
+		t.Errorf("invalid stat field: %v", err)
+	}
+
+	match := models.Match{Id: 99, UniqueTournamentId: fixture.TournamentID, SeasonId: fixture.SeasonID,
+		HomeTeamId: fixture.AthleticID, AwayTeamId: fixture.BetisID}
+	if err := c.CreateMatch(ctx, match); !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("write without a key: %v", err)
+	}
+	if err := newClient(t, url, client.WithAPIKey("sb_nosuchkey_c2VjcmV0")).CreateMatch(ctx, match); !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("write with an unknown key: %v", err)
+	}
+
+	writer := newClient(t, url, client.WithAPIKey(writeKey))
+	if err := writer.CreateMatch(ctx, match); err != nil {
+		t.Fatalf("CreateMatch: %v", err)
+	}
+	if err := writer.CreateMatch(ctx, match); !errors.Is(err, client.ErrConflict) {
+		t.Errorf("duplicate match: %v", err)
+	}
+	match.Matchday = 7
+	if err := writer.UpsertMatch(ctx, match); err != nil {
+		t.Fatalf("UpsertMatch: %v", err)
+	}
+	if got, err := c.GetMatch(ctx, 99); err != nil || got.Matchday != 7 {
+		t.Errorf("GetMatch(99) = %+v, %v", got, err)
+	}
+}
+
+// flaky answers with each status in turn, then 200 with an empty list.
+func flaky(t *testing.T, header http.Header, statuses ...int) (*atomic.Int32, string) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		if n <= len(statuses) {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(statuses[n-1])
+			w.Write([]byte(`{"type":"about:blank","title":"Unavailable","status":503}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(srv.Close)
+	return &calls, srv.URL
+}
+
+func TestRetries(t *testing.T) {
+	ctx := context.Background()
+
+	calls, url := flaky(t, nil, http.StatusServiceUnavailable, http.StatusBadGateway)
+	if _, err := newClient(t, url).SearchTeams(ctx, "x"); err != nil || calls.Load() != 3 {
+		t.Errorf("GET after two 5xx: %v after %d calls, want success after 3", err, calls.Load())
+	}
+
+	calls, url = flaky(t, nil, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	_, err := newClient(t, url).SearchTeams(ctx, "x")
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusServiceUnavailable || calls.Load() != 3 {
+		t.Errorf("GET after three 503: %v after %d calls, want the 503 after 3", err, calls.Load())
+	}
+
+	calls, url = flaky(t, nil, http.StatusServiceUnavailable)
+	if err := newClient(t, url).CreateTeamMatchStats(ctx, nil); err == nil || calls.Load() != 1 {
+		t.Errorf("POST after a 503: %v after %d calls, want the 503 after 1", err, calls.Load())
+	}
+
+	calls, url = flaky(t, http.Header{"Retry-After": {"0"}}, http.StatusTooManyRequests)
+	if err := newClient(t, url).CreateTeamMatchStats(ctx, nil); err != nil || calls.Load() != 2 {
+		t.Errorf("POST after a 429: %v after %d calls, want success after 2", err, calls.Load())
+	}
+
+	// A wait past MaxBackoff, such as an exhausted daily quota, is not
+	// worth retrying.
+	calls, url = flaky(t, http.Header{"Retry-After": {"3600"}}, http.StatusTooManyRequests)
+	_, err = newClient(t, url).SearchTeams(ctx, "x")
+	if !errors.Is(err, client.ErrRateLimited) || !errors.As(err, &apiErr) || apiErr.RetryAfter != time.Hour || calls.Load() != 1 {
+		t.Errorf("GET after a long Retry-After: %v after %d calls", err, calls.Load())
+	}
+
+	_, url = flaky(t, nil, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	slow := newClient(t, url, client.WithRetry(client.Retry{MaxAttempts: 3, MinBackoff: time.Hour, MaxBackoff: time.Hour}))
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := slow.SearchTeams(ctx, "x"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("cancelled during backoff: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+)
+
+// Kinds of Error. Test for them with errors.Is. The first three are the
+// server's own apperr kinds.
+var (
+	ErrNotFound        = apperr.ErrNotFound
+	ErrInvalidArgument = apperr.ErrInvalidArgument
+	ErrConflict        = apperr.ErrConflict
+	// ErrUnauthorized is a missing, invalid or revoked API key.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is an API key without the scope a request needs.
+	ErrForbidden = errors.New("forbidden")
+	// ErrRateLimited is an exhausted rate limit or daily quota.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// maxErrorBody bounds how much of a response that is not a problem
+// document is kept as the detail of its Error.
+const maxErrorBody = 512
+
+// Error is an error response of the API, decoded from the RFC 7807
+// problem document the server answers with.
+type Error struct {
+	Status   int    `json:"status"`
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// RequestID is the X-Request-ID of the response, which the server
+	// logs the request under.
+	RequestID string `json:"-"`
+	// RetryAfter is the wait the server asked for, if any.
+	RetryAfter time.Duration `json:"-"`
+}
+
+func (e *Error) Error() string {
+	msg := "statsbanger: " + strconv.Itoa(e.Status) + " " + e.Title
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// Unwrap returns the kind of e, or nil for server errors.
+func (e *Error) Unwrap() error {
+	switch e.Status {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusBadRequest:
+		return ErrInvalidArgument
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// readError turns an error response into an *Error. Responses that are
+// not problem documents, such as those of a proxy, keep the start of
+// their body as the detail.
+func readError(resp *http.Response) error {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	e := &Error{}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/problem+json") ||
+		json.Unmarshal(body, e) != nil {
+		e = &Error{Detail: strings.TrimSpace(string(body[:min(len(body), maxErrorBody)]))}
+	}
+	e.Status = resp.StatusCode
+	if e.Title == "" {
+		e.Title = http.StatusText(resp.StatusCode)
+	}
+	e.RequestID = resp.Header.Get("X-Request-ID")
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		e.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return e
+}
@@ -0,0 +1,14 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/url"
+)
+
+// Export writes every row of table in a season to w as a file in format:
+// table is player_stat, team_stat, player_match_stat or team_match_stat,
+// and format parquet or arrow.
+func (c *Client) Export(ctx context.Context, season Season, table, format string, w io.Writer) error {
+	return c.get(ctx, "/api/export/"+url.PathEscape(table)+"."+url.PathEscape(format), season.query(), w)
+}
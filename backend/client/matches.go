@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// GetMatch returns a match with both teams.
+func (c *Client) GetMatch(ctx context.Context, matchID int) (*models.Match, error) {
+	var match models.Match
+	if err := c.get(ctx, "/api/match/"+strconv.Itoa(matchID), nil, &match); err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// MatchQuery filters ListMatches. Zero fields match every match.
+type MatchQuery struct {
+	UniqueTournamentID int
+	SeasonID           int
+	Matchday           int
+	TeamID             int
+	// Side is "home" or "away" to only list the team's matches there.
+	Side string
+	// From and To bound the kick-off, both inclusive.
+	From, To time.Time
+	// Played lists only matches with a score, or only those without.
+	Played *bool
+	// Descending lists the latest kick-off first.
+	Descending bool
+	// Limit is the page size, 50 if zero; Offset skips matches.
+	Limit, Offset int
+}
+
+func (q MatchQuery) query() url.Values {
+	v := url.Values{}
+	for name, n := range map[string]int{
+		"uniqueTournamentID": q.UniqueTournamentID,
+		"seasonID":           q.SeasonID,
+		"matchday":           q.Matchday,
+		"teamID":             q.TeamID,
+		"limit":              q.Limit,
+		"offset":             q.Offset,
+	} {
+		if n != 0 {
+			v.Set(name, strconv.Itoa(n))
+		}
+	}
+	if q.Side != "" {
+		v.Set("side", q.Side)
+	}
+	if !q.From.IsZero() {
+		v.Set("from", q.From.Format(time.RFC3339))
+	}
+	if !q.To.IsZero() {
+		v.Set("to", q.To.Format(time.RFC3339))
+	}
+	if q.Played != nil {
+		v.Set("played", strconv.FormatBool(*q.Played))
+	}
+	if q.Descending {
+		v.Set("order", "desc")
+	}
+	return v
+}
+
+// ListMatches returns a page of the matches that pass q, with both teams,
+// ordered by kick-off.
+func (c *Client) ListMatches(ctx context.Context, q MatchQuery) ([]models.Match, error) {
+	var matches []models.Match
+	err := c.get(ctx, "/api/match", q.query(), &matches)
+	return matches, err
+}
+
+// CreateMatch creates a match. It fails with ErrConflict if the ID is
+// taken.
+func (c *Client) CreateMatch(ctx context.Context, match models.Match) error {
+	return c.do(ctx, http.MethodPost, "/api/match", nil, match, nil)
+}
+
+// UpsertMatch creates a match or replaces the one with its ID.
+func (c *Client) UpsertMatch(ctx context.Context, match models.Match) error {
+	return c.do(ctx, http.MethodPut, "/api/match/"+strconv.Itoa(match.Id), nil, match, nil)
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// StatEntities lists the entities that have stats, such as player-season.
+func (c *Client) StatEntities(ctx context.Context) ([]string, error) {
+	var entities []string
+	err := c.get(ctx, "/api/meta/stats", nil, &entities)
+	return entities, err
+}
+
+// Stats describes the stats of entity in display order.
+func (c *Client) Stats(ctx context.Context, entity string) ([]models.Stat, error) {
+	var meta struct {
+		Stats []models.Stat `json:"stats"`
+	}
+	err := c.get(ctx, "/api/meta/stats/"+url.PathEscape(entity), nil, &meta)
+	return meta.Stats, err
+}
+
+// GraphQLError is a GraphQL response that carries errors. Data holds
+// whatever part of the result resolved.
+type GraphQLError struct {
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path,omitempty"`
+		Extensions map[string]any `json:"extensions,omitempty"`
+	}
+	Data json.RawMessage
+}
+
+func (e *GraphQLError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Message
+	}
+	return "statsbanger: graphql: " + strings.Join(messages, "; ")
+}
+
+// GraphQL runs query with variables against /graphql and decodes its data
+// into out. A response with errors returns a *GraphQLError.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, out any) error {
+	in := struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables,omitempty"`
+	}{query, variables}
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors json.RawMessage `json:"errors"`
+	}
+	// The schema has no mutations, so every query may be retried.
+	if err := c.call(ctx, http.MethodPost, "/graphql", nil, in, &resp, true); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		e := &GraphQLError{Data: resp.Data}
+		if err := json.Unmarshal(resp.Errors, &e.Errors); err != nil {
+			return errors.New("statsbanger: graphql: malformed errors")
+		}
+		return e
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Data, out)
+}
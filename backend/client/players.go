@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+func (c *Client) GetPlayer(ctx context.Context, playerID int) (*models.Player, error) {
+	var player models.Player
+	if err := c.get(ctx, "/api/player/"+strconv.Itoa(playerID), nil, &player); err != nil {
+		return nil, err
+	}
+	return &player, nil
+}
+
+// SearchPlayers returns up to 20 players whose name contains name,
+// ignoring case.
+func (c *Client) SearchPlayers(ctx context.Context, name string) ([]models.Player, error) {
+	var players []models.Player
+	err := c.get(ctx, "/api/player", url.Values{"name": {name}}, &players)
+	return players, err
+}
+
+// PlayerMatchStats returns every player's stats in one match: the given
+// fields, or every stat if there are none.
+func (c *Client) PlayerMatchStats(ctx context.Context, matchID int, fields ...string) ([]models.PlayerMatchStat, error) {
+	query := url.Values{"matchID": {strconv.Itoa(matchID)}}
+	setFields(query, fields)
+	var stats []models.PlayerMatchStat
+	err := c.get(ctx, "/api/player-match-stat", query, &stats)
+	return stats, err
+}
+
+// PlayerMatchStatsByPlayer returns a player's stats in every match they
+// played.
+func (c *Client) PlayerMatchStatsByPlayer(ctx context.Context, playerID int) ([]models.PlayerMatchStat, error) {
+	var stats []models.PlayerMatchStat
+	err := c.get(ctx, "/api/player-match-stat/player/"+strconv.Itoa(playerID), nil, &stats)
+	return stats, err
+}
+
+// PlayerMatchStat returns a player's stats in one match.
+func (c *Client) PlayerMatchStat(ctx context.Context, playerID, matchID int) (*models.PlayerMatchStat, error) {
+	var stat models.PlayerMatchStat
+	path := "/api/player-match-stat/player/" + strconv.Itoa(playerID) + "/match/" + strconv.Itoa(matchID)
+	if err := c.get(ctx, path, nil, &stat); err != nil {
+		return nil, err
+	}
+	return &stat, nil
+}
+
+// CreatePlayerMatchStats creates stat rows. If any row exists, it fails
+// with ErrConflict and creates none.
+func (c *Client) CreatePlayerMatchStats(ctx context.Context, stats []models.PlayerMatchStat) error {
+	return c.do(ctx, http.MethodPost, "/api/player-match-stat", nil, stats, nil)
+}
+
+// UpsertPlayerMatchStats creates stat rows, overwriting the stats they
+// carry in rows that exist.
+func (c *Client) UpsertPlayerMatchStats(ctx context.Context, stats []models.PlayerMatchStat) error {
+	return c.do(ctx, http.MethodPut, "/api/player-match-stat", nil, stats, nil)
+}
+
+// PlayerSeasonStats returns the season stats of the given players, or of
+// every player if there are none: the given fields, or every stat if
+// there are none.
+func (c *Client) PlayerSeasonStats(ctx context.Context, season Season, playerIDs []int, fields ...string) ([]models.PlayerSeasonStat, error) {
+	query := season.query()
+	if len(playerIDs) > 0 {
+		query.Set("playerID", ints(playerIDs))
+	}
+	setFields(query, fields)
+	var stats []models.PlayerSeasonStat
+	err := c.get(ctx, "/api/player-season-stat", query, &stats)
+	return stats, err
+}
+
+// TopPlayersByStat ranks the players of a season by stat; a limit of 0
+// means no limit. A position of D, M, F or G keeps only players of that
+// position.
+func (c *Client) TopPlayersByStat(ctx context.Context, season Season, stat string, limit int, position string) ([]models.TopPlayerStatResult, error) {
+	query := season.query()
+	query.Set("statFields", stat)
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if position != "" {
+		query.Set("position", position)
+	}
+	var top []models.TopPlayerStatResult
+	err := c.get(ctx, "/api/player-season-stat/top-players", query, &top)
+	return top, err
+}
+
+// CreatePlayerSeasonStats creates stat rows. If any row exists, it fails
+// with ErrConflict and creates none.
+func (c *Client) CreatePlayerSeasonStats(ctx context.Context, stats []models.PlayerSeasonStat) error {
+	return c.do(ctx, http.MethodPost, "/api/player-season-stat", nil, stats, nil)
+}
+
+// UpsertPlayerSeasonStats creates stat rows, overwriting the stats they
+// carry in rows that exist.
+func (c *Client) UpsertPlayerSeasonStats(ctx context.Context, stats []models.PlayerSeasonStat) error {
+	return c.do(ctx, http.MethodPut, "/api/player-season-stat", nil, stats, nil)
+}
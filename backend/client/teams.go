@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// Season identifies one season of a tournament.
+type Season struct {
+	UniqueTournamentID int
+	SeasonID           int
+}
+
+func (s Season) query() url.Values {
+	return url.Values{
+		"uniqueTournamentID": {strconv.Itoa(s.UniqueTournamentID)},
+		"seasonID":           {strconv.Itoa(s.SeasonID)},
+	}
+}
+
+func (c *Client) GetTeam(ctx context.Context, teamID int) (*models.Team, error) {
+	var team models.Team
+	if err := c.get(ctx, "/api/team/"+strconv.Itoa(teamID), nil, &team); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// SearchTeams returns up to 20 teams whose name contains name, ignoring
+// case.
+func (c *Client) SearchTeams(ctx context.Context, name string) ([]models.Team, error) {
+	var teams []models.Team
+	err := c.get(ctx, "/api/team", url.Values{"name": {name}}, &teams)
+	return teams, err
+}
+
+// TeamMatchStat returns a team's stats in one match: the given fields, or
+// every stat if there are none.
+func (c *Client) TeamMatchStat(ctx context.Context, matchID, teamID int, fields ...string) (*models.TeamMatchStat, error) {
+	query := url.Values{"matchID": {strconv.Itoa(matchID)}, "teamID": {strconv.Itoa(teamID)}}
+	setFields(query, fields)
+	var stat models.TeamMatchStat
+	if err := c.get(ctx, "/api/team-match-stat", query, &stat); err != nil {
+		return nil, err
+	}
+	return &stat, nil
+}
+
+// TeamMatchStatsByTeam returns a team's stats in every match it played.
+func (c *Client) TeamMatchStatsByTeam(ctx context.Context, teamID int) ([]models.TeamMatchStat, error) {
+	var stats []models.TeamMatchStat
+	err := c.get(ctx, "/api/team-match-stat/team/"+strconv.Itoa(teamID), nil, &stats)
+	return stats, err
+}
+
+// CreateTeamMatchStats creates stat rows. If any row exists, it fails
+// with ErrConflict and creates none.
+func (c *Client) CreateTeamMatchStats(ctx context.Context, stats []models.TeamMatchStat) error {
+	return c.do(ctx, http.MethodPost, "/api/team-match-stat", nil, stats, nil)
+}
+
+// UpsertTeamMatchStats creates stat rows, overwriting the stats they carry
+// in rows that exist.
+func (c *Client) UpsertTeamMatchStats(ctx context.Context, stats []models.TeamMatchStat) error {
+	return c.do(ctx, http.MethodPut, "/api/team-match-stat", nil, stats, nil)
+}
+
+// TeamSeasonStats returns the season stats of the given teams, or of every
+// team if there are none: the given fields, or every stat if there are
+// none.
+func (c *Client) TeamSeasonStats(ctx context.Context, season Season, teamIDs []int, fields ...string) ([]models.TeamSeasonStat, error) {
+	query := season.query()
+	if len(teamIDs) > 0 {
+		query.Set("teamID", ints(teamIDs))
+	}
+	setFields(query, fields)
+	var stats []models.TeamSeasonStat
+	err := c.get(ctx, "/api/team-season-stat", query, &stats)
+	return stats, err
+}
+
+// TopTeamsByStat ranks the teams of a season by stat; a limit of 0 means
+// no limit.
+func (c *Client) TopTeamsByStat(ctx context.Context, season Season, stat string, limit int) ([]models.TopTeamStatResult, error) {
+	query := season.query()
+	query.Set("statFields", stat)
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var top []models.TopTeamStatResult
+	err := c.get(ctx, "/api/team-season-stat/top-teams", query, &top)
+	return top, err
+}
+
+// CreateTeamSeasonStats creates stat rows. If any row exists, it fails
+// with ErrConflict and creates none.
+func (c *Client) CreateTeamSeasonStats(ctx context.Context, stats []models.TeamSeasonStat) error {
+	return c.do(ctx, http.MethodPost, "/api/team-season-stat", nil, stats, nil)
+}
+
+// UpsertTeamSeasonStats creates stat rows, overwriting the stats they
+// carry in rows that exist.
+func (c *Client) UpsertTeamSeasonStats(ctx context.Context, stats []models.TeamSeasonStat) error {
+	return c.do(ctx, http.MethodPut, "/api/team-season-stat", nil, stats, nil)
+}
+
+func setFields(query url.Values, fields []string) {
+	if len(fields) > 0 {
+		query.Set("statFields", strings.Join(fields, ","))
+	}
+}
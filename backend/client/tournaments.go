@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// Tournaments returns every tournament with matches or stats, by ID.
+func (c *Client) Tournaments(ctx context.Context) ([]models.Tournament, error) {
+	var tournaments []models.Tournament
+	err := c.get(ctx, "/api/tournaments", nil, &tournaments)
+	return tournaments, err
+}
+
+// Seasons returns the seasons of a tournament, latest first.
+func (c *Client) Seasons(ctx context.Context, uniqueTournamentID int) ([]models.Season, error) {
+	var seasons []models.Season
+	err := c.get(ctx, "/api/tournaments/"+strconv.Itoa(uniqueTournamentID)+"/seasons", nil, &seasons)
+	return seasons, err
+}
+
+// CurrentSeason returns the latest season of a tournament, for callers
+// that have not picked one.
+func (c *Client) CurrentSeason(ctx context.Context, uniqueTournamentID int) (Season, error) {
+	seasons, err := c.Seasons(ctx, uniqueTournamentID)
+	if err != nil {
+		return Season{}, err
+	}
+	if len(seasons) == 0 {
+		return Season{}, ErrNotFound
+	}
+	return Season{UniqueTournamentID: uniqueTournamentID, SeasonID: seasons[0].Id}, nil
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+	"github.com/plinphon/StatsBanger/backend/client"
+	"github.com/plinphon/StatsBanger/backend/models"
+
+	export "github.com/plinphon/StatsBanger/backend/api/export"
+)
+
+// laLiga is the tournament of the bundled database, used when -tournament
+// is not given.
+const laLiga = 8
+
+// A command parses its arguments into a runner, which reads the result
+// from a source.
+type command func(args []string, stderr io.Writer) (runner, error)
+
+type runner func(ctx context.Context, src source) (*result, error)
+
+var commands = map[string]command{
+	"top-players": parseTopPlayers,
+	"player":      parsePlayer,
+	"team-table":  parseTeamTable,
+	"export":      parseExport,
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("statsbanger "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+// seasonFlags adds -tournament and -season to fs.
+func seasonFlags(fs *flag.FlagSet) *client.Season {
+	season := &client.Season{}
+	fs.IntVar(&season.UniqueTournamentID, "tournament", laLiga, "unique tournament ID")
+	fs.IntVar(&season.SeasonID, "season", 0, "season ID (default the tournament's current season)")
+	return season
+}
+
+func checkSeason(season *client.Season) error {
+	if season.SeasonID < 0 || season.UniqueTournamentID <= 0 {
+		return errors.New("-season and -tournament must be positive IDs")
+	}
+	return nil
+}
+
+// resolveSeason fills in the tournament's current season if -season was
+// not given.
+func resolveSeason(ctx context.Context, src source, season *client.Season) error {
+	if season.SeasonID != 0 {
+		return nil
+	}
+	seasons, err := src.Seasons(ctx, season.UniqueTournamentID)
+	if err != nil {
+		return err
+	}
+	if len(seasons) == 0 {
+		return fmt.Errorf("%w: tournament %d has no seasons", apperr.ErrNotFound, season.UniqueTournamentID)
+	}
+	season.SeasonID = seasons[0].Id
+	return nil
+}
+
+func noArgs(name string, positional []string) error {
+	if len(positional) > 0 {
+		return fmt.Errorf("%s: unexpected argument %q", name, positional[0])
+	}
+	return nil
+}
+
+func parseTopPlayers(args []string, stderr io.Writer) (runner, error) {
+	fs := newFlagSet("top-players", stderr)
+	season := seasonFlags(fs)
+	stat := fs.String("stat", "", "player season stat to rank by (required)")
+	position := fs.String("position", "", "only players in this position: D, M, F or G")
+	limit := fs.Int("limit", 10, "number of players (0 = all)")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	if err := noArgs("top-players", positional); err != nil {
+		return nil, err
+	}
+	if *stat == "" {
+		return nil, errors.New("top-players: -stat is required")
+	}
+	if err := checkSeason(season); err != nil {
+		return nil, err
+	}
+	if *limit < 0 {
+		return nil, errors.New("-limit must not be negative")
+	}
+
+	return func(ctx context.Context, src source) (*result, error) {
+		if err := resolveSeason(ctx, src, season); err != nil {
+			return nil, err
+		}
+		top, err := src.TopPlayers(ctx, *season, *stat, *limit, *position)
+		if err != nil {
+			return nil, err
+		}
+		res := &result{value: top, header: []string{"rank", "player_id", "player", "position", *stat}}
+		for i, p := range top {
+			res.rows = append(res.rows, []any{i + 1, p.PlayerID, p.PlayerName, p.Position, p.StatValue})
+		}
+		return res, nil
+	}, nil
+}
+
+// defaultMatchStats are the columns of "player -matches" without -stats.
+const defaultMatchStats = "minutes_played,goals,goal_assist,rating"
+
+func parsePlayer(args []string, stderr io.Writer) (runner, error) {
+	fs := newFlagSet("player", stderr)
+	matches := fs.Bool("matches", false, "list the player's stats in every match they played")
+	stats := fs.String("stats", defaultMatchStats, "comma-separated player match stats listed by -matches")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(positional) != 1 {
+		return nil, errors.New("usage: statsbanger player ID [-matches] [-stats FIELD,...]")
+	}
+	playerID, err := strconv.Atoi(positional[0])
+	if err != nil || playerID <= 0 {
+		return nil, fmt.Errorf("invalid player ID %q", positional[0])
+	}
+	fields := fieldList(*stats)
+
+	return func(ctx context.Context, src source) (*result, error) {
+		p, err := src.Player(ctx, playerID)
+		if err != nil {
+			return nil, err
+		}
+		if !*matches {
+			return &result{
+				value:  p,
+				header: []string{"player_id", "player", "position", "age", "birthday", "height", "preferred_foot", "nationality"},
+				rows: [][]any{{p.PlayerId, p.PlayerName, p.Position, p.Age, p.Birthday.Format("2006-01-02"),
+					p.Height, p.PreferredFoot, p.Nationality}},
+			}, nil
+		}
+
+		history, err := src.PlayerMatches(ctx, playerID, fields)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(history, func(i, j int) bool {
+			return history[i].Match.CurrentPeriodStartTimestamp.Before(history[j].Match.CurrentPeriodStartTimestamp)
+		})
+		res := &result{value: history, header: append([]string{"date", "match_id", "home", "away", "score", "team"}, fields...)}
+		for i := range history {
+			h := &history[i]
+			h.Player = *p
+			row := []any{h.Match.CurrentPeriodStartTimestamp.Format("2006-01-02"), h.MatchId,
+				h.Match.HomeTeam.TeamName, h.Match.AwayTeam.TeamName, score(h.Match), h.Team.TeamName}
+			for _, field := range fields {
+				row = append(row, h.Stats[field])
+			}
+			res.rows = append(res.rows, row)
+		}
+		return res, nil
+	}, nil
+}
+
+// score returns "home-away", or "" for a match that has not been played.
+func score(m models.Match) string {
+	if m.HomeScore == nil || m.AwayScore == nil {
+		return ""
+	}
+	return strconv.Itoa(*m.HomeScore) + "-" + strconv.Itoa(*m.AwayScore)
+}
+
+// defaultTableStats are the columns of "team-table" without -stats.
+const defaultTableStats = "matches,goals_scored,goals_conceded,expected_goals,expected_goals_conceded,clean_sheets,average_ball_possession,avg_rating"
+
+func parseTeamTable(args []string, stderr io.Writer) (runner, error) {
+	fs := newFlagSet("team-table", stderr)
+	season := seasonFlags(fs)
+	stats := fs.String("stats", defaultTableStats, "comma-separated team season stats to list")
+	sortBy := fs.String("sort", "goals_scored", "team season stat to rank by, best first")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	if err := noArgs("team-table", positional); err != nil {
+		return nil, err
+	}
+	if err := checkSeason(season); err != nil {
+		return nil, err
+	}
+	// Ranking needs the direction of the stat, which only the registry
+	// knows; the fields themselves are checked by the source.
+	rank, ok := models.TeamSeasonStats.Lookup(*sortBy)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", models.ErrInvalidStatField, *sortBy)
+	}
+	fields := fieldList(*stats)
+	query := fields
+	if !slices.Contains(fields, rank.Field) {
+		query = append(fields[:len(fields):len(fields)], rank.Field)
+	}
+
+	return func(ctx context.Context, src source) (*result, error) {
+		if err := resolveSeason(ctx, src, season); err != nil {
+			return nil, err
+		}
+		teams, err := src.TeamSeasonStats(ctx, *season, query)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(teams, func(i, j int) bool {
+			return better(rank, teams[i].Stats[rank.Field], teams[j].Stats[rank.Field])
+		})
+		res := &result{value: teams, header: append([]string{"rank", "team_id", "team"}, fields...)}
+		for i, t := range teams {
+			row := []any{i + 1, t.TeamID, t.Team.TeamName}
+			for _, field := range fields {
+				row = append(row, t.Stats[field])
+			}
+			res.rows = append(res.rows, row)
+		}
+		return res, nil
+	}, nil
+}
+
+// better reports whether a ranks before b in stat. Missing values rank
+// last.
+func better(stat models.Stat, a, b *float64) bool {
+	switch {
+	case a == nil:
+		return false
+	case b == nil:
+		return true
+	case stat.Direction == models.LowerIsBetter:
+		return *a < *b
+	}
+	return *a > *b
+}
+
+func parseExport(args []string, stderr io.Writer) (runner, error) {
+	fs := newFlagSet("export", stderr)
+	season := seasonFlags(fs)
+	out := fs.String("o", "", "file to write (default TABLE.FORMAT in the current directory)")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(positional) != 1 {
+		return nil, errors.New("usage: statsbanger export [-season ID] [-o FILE] TABLE.parquet|TABLE.arrow")
+	}
+	table, format, ok := cutLast(positional[0], ".")
+	if !ok {
+		return nil, fmt.Errorf("export: %q has no .parquet or .arrow extension", positional[0])
+	}
+	if err := export.CheckTable(table); err != nil {
+		return nil, err
+	}
+	if _, err := export.LookupFormat(format); err != nil {
+		return nil, err
+	}
+	if err := checkSeason(season); err != nil {
+		return nil, err
+	}
+	path := *out
+	if path == "" {
+		path = positional[0]
+	}
+
+	return func(ctx context.Context, src source) (*result, error) {
+		if err := resolveSeason(ctx, src, season); err != nil {
+			return nil, err
+		}
+		n, err := writeFile(path, func(w io.Writer) error {
+			return src.Export(ctx, *season, table, format, w)
+		})
+		if err != nil {
+			return nil, err
+		}
+		summary := exportSummary{Table: table, File: path, Bytes: n}
+		return &result{
+			value:  summary,
+			header: []string{"table", "file", "bytes"},
+			rows:   [][]any{{summary.Table, summary.File, summary.Bytes}},
+		}, nil
+	}, nil
+}
+
+// exportSummary is what export prints once the file is written.
+type exportSummary struct {
+	Table string `json:"table"`
+	File  string `json:"file"`
+	Bytes int64  `json:"bytes"`
+}
+
+// writeFile replaces the file at path with what write writes, returning
+// its size. The output goes to a temporary file in the same directory
+// that is renamed over path only once write succeeds, so a failure leaves
+// neither a partial file nor a truncated one that was there before.
+func writeFile(path string, write func(io.Writer) error) (int64, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	w := &countingWriter{w: f}
+	err = write(w)
+	if err == nil {
+		// CreateTemp makes the file private to its owner.
+		err = f.Chmod(0o644)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return 0, err
+	}
+	return w.n, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,171 @@
+// Command statsbanger queries StatsBanger data from a terminal, either
+// straight from a local database or from a running server:
+//
+//	statsbanger top-players -stat goals -season 52376 -position F
+//	statsbanger -format csv player 991011 -matches
+//	statsbanger -server https://api.statsbanger.com team-table
+//	statsbanger export -season 52376 player_match_stat.parquet
+//
+// Results are printed as an aligned table, JSON or CSV; export writes a
+// season of a stat table to a Parquet or Arrow file instead.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/plinphon/StatsBanger/backend/client"
+	"github.com/plinphon/StatsBanger/backend/config"
+)
+
+const usage = `usage: statsbanger [flags] <command> [command flags]
+
+commands:
+  top-players -stat FIELD [-season ID] [-position D|M|F|G] [-limit N]
+                     the players leading a season stat
+  player ID [-matches] [-stats FIELD,...]
+                     a player's profile, or their stats in every match
+  team-table [-season ID] [-stats FIELD,...] [-sort FIELD]
+                     every team of a season ranked by a season stat
+  export [-season ID] [-o FILE] TABLE.parquet|TABLE.arrow
+                     write a season of player_stat, team_stat,
+                     player_match_stat or team_match_stat to a file
+
+The season commands also take -tournament ID (default 8, La Liga), and
+without -season read the tournament's current season. Run
+"statsbanger <command> -h" for its flags.
+
+flags:`
+
+// Environment variables read by the flags of the same name.
+const (
+	envServer = "STATSBANGER_SERVER"
+	envAPIKey = "STATSBANGER_API_KEY"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		// Errors from the client already carry the prefix.
+		fmt.Fprintln(os.Stderr, "statsbanger:", strings.TrimPrefix(err.Error(), "statsbanger: "))
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("statsbanger", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	server := fs.String("server", os.Getenv(envServer), "base URL of a StatsBanger server to query instead of a local database (env "+envServer+")")
+	apiKey := fs.String("api-key", os.Getenv(envAPIKey), "API key sent to -server (env "+envAPIKey+")")
+	dbPath := fs.String("db", "", "SQLite database to query (default $STATSBANGER_DB_PATH or laligaDB.db)")
+	format := fs.String("format", formatTable, "output format: table, json or csv")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	if !validFormat(*format) {
+		return fmt.Errorf("unknown format %q (want table, json or csv)", *format)
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		return fmt.Errorf("unknown command %q (want top-players, player, team-table or export)", fs.Arg(0))
+	}
+
+	// Flags are checked before the source is opened, so a typo does not
+	// wait on a database or a server.
+	runCmd, err := cmd(fs.Args()[1:], stderr)
+	if err != nil {
+		return err
+	}
+
+	src, err := open(*server, *apiKey, *dbPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	res, err := runCmd(ctx, src)
+	if err != nil {
+		return err
+	}
+	return res.write(stdout, *format)
+}
+
+// open returns the remote source for server if it is set, and the local
+// database otherwise.
+func open(server, apiKey, dbPath string) (source, error) {
+	if server != "" {
+		opts := []client.Option{}
+		if apiKey != "" {
+			opts = append(opts, client.WithAPIKey(apiKey))
+		}
+		c, err := client.New(server, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return newRemoteSource(c), nil
+	}
+
+	// The config file and STATSBANGER_DB_* variables of the server apply
+	// here too; nothing else in the config matters to the CLI.
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		return nil, err
+	}
+	if dbPath != "" {
+		cfg.DBPath = dbPath
+	}
+	cfg.DBReadOnly = true
+	if cfg.DBDriver == config.DriverSQLite {
+		if _, err := os.Stat(cfg.DBPath); err != nil {
+			return nil, fmt.Errorf("database %s: %w", cfg.DBPath, err)
+		}
+	}
+	return openLocalSource(cfg)
+}
+
+// parse parses the flags before and after positional arguments, so that
+// both "player -matches 42" and "player 42 -matches" work.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// fieldList splits a comma-separated list of stat fields.
+func fieldList(s string) []string {
+	var fields []string
+	for _, field := range strings.Split(s, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
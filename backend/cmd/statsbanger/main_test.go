@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+	"github.com/plinphon/StatsBanger/backend/client"
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/container"
+	"github.com/plinphon/StatsBanger/backend/fixture"
+	"github.com/plinphon/StatsBanger/backend/middleware"
+	"github.com/plinphon/StatsBanger/backend/routes"
+)
+
+// sources returns a local source and a remote one serving the same
+// fixture database.
+func sources(t *testing.T) map[string]source {
+	t.Helper()
+
+	local := container.NewWithDB(config.Default(), fixture.Open(t))
+	t.Cleanup(func() { local.Close() })
+
+	served := container.NewWithDB(config.Default(), fixture.Open(t))
+	t.Cleanup(func() { served.Close() })
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	routes.SetupRoutes(app, served)
+	srv := httptest.NewServer(adaptor.FiberApp(app))
+	t.Cleanup(srv.Close)
+	c, err := client.New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]source{"local": newLocalSource(local), "remote": newRemoteSource(c)}
+}
+
+func execute(src source, format string, args ...string) (string, error) {
+	runCmd, err := commands[args[0]](args[1:], io.Discard)
+	if err != nil {
+		return "", err
+	}
+	res, err := runCmd(context.Background(), src)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	err = res.write(&out, format)
+	return out.String(), err
+}
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		format string
+		args   []string
+		want   string
+	}{
+		{formatTable, []string{"top-players", "-stat", "goals", "-season", "52376", "-limit", "2"}, `
+RANK  PLAYER_ID  PLAYER           POSITION  GOALS
+1     991011     Jude Bellingham  M         19
+2     868812     Vinícius Júnior  F         15
+`},
+		{formatCSV, []string{"player", "991011", "-matches", "-stats", "goals,rating"}, `
+date,match_id,home,away,score,team,goals,rating
+2023-08-12,11369286,Athletic Bilbao,Real Madrid,0-2,Real Madrid,1,8.4
+2024-03-31,11368707,Real Madrid,Athletic Bilbao,2-0,Real Madrid,1,7.8
+`},
+		{formatCSV, []string{"team-table", "-stats", "goals_scored", "-sort", "goals_conceded"}, `
+rank,team_id,team,goals_scored
+1,2829,Real Madrid,87
+2,2825,Athletic Bilbao,61
+3,2816,Real Betis,48
+`},
+	}
+	for name, src := range sources(t) {
+		for _, tt := range tests {
+			got, err := execute(src, tt.format, tt.args...)
+			if err != nil {
+				t.Errorf("%s %v: %v", name, tt.args, err)
+				continue
+			}
+			if want := strings.TrimPrefix(tt.want, "\n"); got != want {
+				t.Errorf("%s %v:\ngot\n%s\nwant\n%s", name, tt.args, got, want)
+			}
+		}
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	for name, src := range sources(t) {
+		if _, err := execute(src, formatTable, "player", "1"); !errors.Is(err, apperr.ErrNotFound) {
+			t.Errorf("%s: unknown player: %v", name, err)
+		}
+		if _, err := execute(src, formatTable, "player", "1", "-matches"); !errors.Is(err, apperr.ErrNotFound) {
+			t.Errorf("%s: matches of an unknown player: %v", name, err)
+		}
+		if _, err := execute(src, formatTable, "top-players", "-stat", "bogus", "-season", "52376"); !errors.Is(err, apperr.ErrInvalidArgument) {
+			t.Errorf("%s: unknown stat: %v", name, err)
+		}
+		if _, err := execute(src, formatTable, "team-table", "-tournament", "1"); !errors.Is(err, apperr.ErrNotFound) {
+			t.Errorf("%s: current season of an unknown tournament: %v", name, err)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"top-players", "-season", "52376"},
+		{"top-players", "-stat", "goals", "-season", "-1"},
+		{"player"},
+		{"player", "abc"},
+		{"team-table", "-season", "52376", "-sort", "bogus"},
+		{"export", "-season", "52376", "team_stat"},
+		{"export", "-season", "52376", "teams.arrow"},
+		{"export", "-season", "52376", "team_stat.csv"},
+		{"export", "-tournament", "0", "team_stat.arrow"},
+	} {
+		if _, err := commands[args[0]](args[1:], io.Discard); err == nil {
+			t.Errorf("%v: no error", args)
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	files := make(map[string][]byte)
+	for name, src := range sources(t) {
+		path := filepath.Join(dir, name+".arrow")
+		got, err := execute(src, formatCSV, "export", "-season", "52376", "-o", path, "player_stat.arrow")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("table,file,bytes\nplayer_stat,%s,%d\n", path, len(data)); got != want {
+			t.Errorf("%s:\ngot\n%s\nwant\n%s", name, got, want)
+		}
+		if !bytes.HasPrefix(data, []byte("ARROW1")) || !bytes.HasSuffix(data, []byte("ARROW1")) {
+			t.Errorf("%s: not an Arrow file", name)
+		}
+		files[name] = data
+
+		path = filepath.Join(dir, name+".parquet")
+		if _, err := execute(src, formatTable, "export", "-season", "1", "-o", path, "team_stat.parquet"); !errors.Is(err, apperr.ErrNotFound) {
+			t.Errorf("%s: empty season: %v", name, err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s: failed export left %s behind", name, path)
+		}
+
+		// A failed export keeps the file it would have replaced.
+		if err := os.WriteFile(path, []byte("previous"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := execute(src, formatTable, "export", "-season", "1", "-o", path, "team_stat.parquet"); err == nil {
+			t.Errorf("%s: empty season exported", name)
+		}
+		if data, err := os.ReadFile(path); err != nil || string(data) != "previous" {
+			t.Errorf("%s: failed export replaced %s with %q (%v)", name, path, data, err)
+		}
+		os.Remove(path)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(files) {
+		t.Errorf("%d files in the export directory, want %d", len(entries), len(files))
+	}
+	if !bytes.Equal(files["local"], files["remote"]) {
+		t.Error("local and remote exports differ")
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats accepted by -format.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+func validFormat(format string) bool {
+	return format == formatTable || format == formatJSON || format == formatCSV
+}
+
+// result is the output of a command. JSON prints value, the models as the
+// API returns them; the table and CSV formats print header and rows.
+type result struct {
+	value  any
+	header []string
+	rows   [][]any
+}
+
+func (r *result) write(w io.Writer, format string) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r.value)
+
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(r.header)
+		for _, row := range r.rows {
+			record := make([]string, len(row))
+			for i, v := range row {
+				record[i] = cell(v, -1)
+			}
+			cw.Write(record)
+		}
+		cw.Flush()
+		return cw.Error()
+
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(r.header, "\t")))
+		for _, row := range r.rows {
+			for i, v := range row {
+				if i > 0 {
+					fmt.Fprint(tw, "\t")
+				}
+				// Tables round to two decimals; CSV keeps every digit.
+				fmt.Fprint(tw, cell(v, 2))
+			}
+			fmt.Fprintln(tw)
+		}
+		return tw.Flush()
+	}
+}
+
+// cell formats v with at most prec decimals, or as many as it needs if prec
+// is negative. Missing stats are empty.
+func cell(v any, prec int) string {
+	switch v := v.(type) {
+	case *float64:
+		if v == nil {
+			return ""
+		}
+		return cell(*v, prec)
+	case float64:
+		s := strconv.FormatFloat(v, 'f', prec, 64)
+		if prec > 0 && strings.Contains(s, ".") {
+			s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+		}
+		return s
+	default:
+		return fmt.Sprint(v)
+	}
+}
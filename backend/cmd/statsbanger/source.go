@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/plinphon/StatsBanger/backend/client"
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/container"
+	"github.com/plinphon/StatsBanger/backend/models"
+
+	export "github.com/plinphon/StatsBanger/backend/api/export"
+	player "github.com/plinphon/StatsBanger/backend/api/player/info"
+	playerMatchStat "github.com/plinphon/StatsBanger/backend/api/player/match"
+	playerSeasonStat "github.com/plinphon/StatsBanger/backend/api/player/season"
+	teamSeasonStat "github.com/plinphon/StatsBanger/backend/api/team/season"
+	tournament "github.com/plinphon/StatsBanger/backend/api/tournament"
+)
+
+// source is where the commands read their data from: a local database or a
+// StatsBanger server. Both return the same models.
+type source interface {
+	TopPlayers(ctx context.Context, season client.Season, stat string, limit int, position string) ([]models.TopPlayerStatResult, error)
+	Player(ctx context.Context, playerID int) (*models.Player, error)
+	// PlayerMatches returns the given stats of a player in every match
+	// they played, with the match and both teams.
+	PlayerMatches(ctx context.Context, playerID int, fields []string) ([]models.PlayerMatchStat, error)
+	// TeamSeasonStats returns the given stats of every team in a season.
+	TeamSeasonStats(ctx context.Context, season client.Season, fields []string) ([]models.TeamSeasonStat, error)
+	// Export writes every row of a stat table in a season to w as a file
+	// in format, parquet or arrow.
+	Export(ctx context.Context, season client.Season, table, format string, w io.Writer) error
+	// Seasons returns the seasons of a tournament, latest first.
+	Seasons(ctx context.Context, uniqueTournamentID int) ([]models.Season, error)
+	Close() error
+}
+
+// localSource reads a database through the same services as the server.
+type localSource struct {
+	c *container.Container
+
+	players           *player.PlayerService
+	playerMatchStats  *playerMatchStat.PlayerMatchStatService
+	playerSeasonStats *playerSeasonStat.PlayerSeasonStatService
+	teamSeasonStats   *teamSeasonStat.TeamSeasonStatService
+	exports           *export.ExportService
+	tournaments       *tournament.TournamentService
+}
+
+func openLocalSource(cfg *config.Config) (*localSource, error) {
+	c, err := container.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return newLocalSource(c), nil
+}
+
+func newLocalSource(c *container.Container) *localSource {
+	return &localSource{
+		c: c,
+
+		players:           player.NewPlayerService(c.Players),
+		playerMatchStats:  playerMatchStat.NewPlayerMatchStatService(c.PlayerMatchStats),
+		playerSeasonStats: playerSeasonStat.NewPlayerSeasonStatService(c.PlayerSeasonStats),
+		teamSeasonStats:   teamSeasonStat.NewTeamSeasonStatService(c.TeamSeasonStats),
+		exports:           export.NewExportService(c.Exports),
+		tournaments:       tournament.NewTournamentService(c.Tournaments),
+	}
+}
+
+func (s *localSource) TopPlayers(ctx context.Context, season client.Season, stat string, limit int, position string) ([]models.TopPlayerStatResult, error) {
+	return s.playerSeasonStats.GetTopPlayersByStat(ctx, stat, season.UniqueTournamentID, season.SeasonID, limit, position)
+}
+
+func (s *localSource) Player(ctx context.Context, playerID int) (*models.Player, error) {
+	return s.players.GetPlayerByID(ctx, playerID)
+}
+
+func (s *localSource) PlayerMatches(ctx context.Context, playerID int, fields []string) ([]models.PlayerMatchStat, error) {
+	stats, err := s.playerMatchStats.GetStatsByPlayerIDs(ctx, []int{playerID}, fields)
+	return values(stats), err
+}
+
+func (s *localSource) TeamSeasonStats(ctx context.Context, season client.Season, fields []string) ([]models.TeamSeasonStat, error) {
+	stats, err := s.teamSeasonStats.GetTeamStatsWithMeta(ctx, fields, season.UniqueTournamentID, season.SeasonID, nil)
+	return values(stats), err
+}
+
+func (s *localSource) Export(ctx context.Context, season client.Season, table, format string, w io.Writer) error {
+	f, err := export.LookupFormat(format)
+	if err != nil {
+		return err
+	}
+	t, err := s.exports.GetTable(ctx, table, season.UniqueTournamentID, season.SeasonID)
+	if err != nil {
+		return err
+	}
+	return f.Write(w, t)
+}
+
+func (s *localSource) Seasons(ctx context.Context, uniqueTournamentID int) ([]models.Season, error) {
+	return s.tournaments.GetSeasons(ctx, uniqueTournamentID)
+}
+
+func (s *localSource) Close() error {
+	return s.c.Close()
+}
+
+func values[T any](rows []*T) []T {
+	out := make([]T, len(rows))
+	for i, row := range rows {
+		out[i] = *row
+	}
+	return out
+}
+
+// remoteSource reads from a StatsBanger server.
+type remoteSource struct {
+	c *client.Client
+}
+
+func newRemoteSource(c *client.Client) *remoteSource {
+	return &remoteSource{c: c}
+}
+
+func (s *remoteSource) TopPlayers(ctx context.Context, season client.Season, stat string, limit int, position string) ([]models.TopPlayerStatResult, error) {
+	return s.c.TopPlayersByStat(ctx, season, stat, limit, position)
+}
+
+func (s *remoteSource) Player(ctx context.Context, playerID int) (*models.Player, error) {
+	return s.c.GetPlayer(ctx, playerID)
+}
+
+func (s *remoteSource) TeamSeasonStats(ctx context.Context, season client.Season, fields []string) ([]models.TeamSeasonStat, error) {
+	return s.c.TeamSeasonStats(ctx, season, nil, fields...)
+}
+
+func (s *remoteSource) Export(ctx context.Context, season client.Season, table, format string, w io.Writer) error {
+	return s.c.Export(ctx, season, table, format, w)
+}
+
+func (s *remoteSource) Seasons(ctx context.Context, uniqueTournamentID int) ([]models.Season, error) {
+	return s.c.Seasons(ctx, uniqueTournamentID)
+}
+
+func (s *remoteSource) Close() error {
+	return nil
+}
+
+// playerMatchesQuery fetches a player's match stats. The REST route for
+// them returns the rows without their stats, so this goes through GraphQL.
+const playerMatchesQuery = `query($id: Int!, $fields: [String!]) {
+  player(id: $id) {
+    matchStats {
+      match {
+        id uniqueTournamentId seasonId matchday homeScore awayScore currentPeriodStartTimestamp
+        homeTeam { id name }
+        awayTeam { id name }
+      }
+      team { id name }
+      stats(fields: $fields) { field value }
+    }
+  }
+}`
+
+func (s *remoteSource) PlayerMatches(ctx context.Context, playerID int, fields []string) ([]models.PlayerMatchStat, error) {
+	type team struct {
+		ID   int
+		Name string
+	}
+	var data struct {
+		Player *struct {
+			MatchStats []struct {
+				Match *struct {
+					ID                          int
+					UniqueTournamentID          int
+					SeasonID                    int
+					Matchday                    int
+					HomeScore                   *int
+					AwayScore                   *int
+					CurrentPeriodStartTimestamp time.Time
+					HomeTeam                    *team
+					AwayTeam                    *team
+				}
+				Team  *team
+				Stats []struct {
+					Field string
+					Value *float64
+				}
+			}
+		}
+	}
+	vars := map[string]any{"id": playerID}
+	if len(fields) > 0 {
+		vars["fields"] = fields
+	}
+	if err := s.c.GraphQL(ctx, playerMatchesQuery, vars, &data); err != nil {
+		return nil, err
+	}
+	if data.Player == nil {
+		return nil, player.ErrPlayerNotFound
+	}
+
+	out := make([]models.PlayerMatchStat, 0, len(data.Player.MatchStats))
+	for _, row := range data.Player.MatchStats {
+		stat := models.PlayerMatchStat{PlayerId: playerID, Stats: make(map[string]*float64, len(row.Stats))}
+		for _, v := range row.Stats {
+			stat.Stats[v.Field] = v.Value
+		}
+		if row.Team != nil {
+			stat.TeamId = row.Team.ID
+			stat.Team = models.Team{TeamId: row.Team.ID, TeamName: row.Team.Name}
+		}
+		if m := row.Match; m != nil {
+			stat.MatchId = m.ID
+			stat.Match = models.Match{
+				Id:                          m.ID,
+				UniqueTournamentId:          m.UniqueTournamentID,
+				SeasonId:                    m.SeasonID,
+				Matchday:                    m.Matchday,
+				HomeScore:                   m.HomeScore,
+				AwayScore:                   m.AwayScore,
+				CurrentPeriodStartTimestamp: m.CurrentPeriodStartTimestamp,
+			}
+			if m.HomeTeam != nil {
+				stat.Match.HomeTeamId = m.HomeTeam.ID
+				stat.Match.HomeTeam = models.Team{TeamId: m.HomeTeam.ID, TeamName: m.HomeTeam.Name}
+			}
+			if m.AwayTeam != nil {
+				stat.Match.AwayTeamId = m.AwayTeam.ID
+				stat.Match.AwayTeam = models.Team{TeamId: m.AwayTeam.ID, TeamName: m.AwayTeam.Name}
+			}
+		}
+		out = append(out, stat)
+	}
+	return out, nil
+}
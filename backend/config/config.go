@@ -0,0 +1,438 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvConfigFile names the environment variable holding the path of an
+// optional JSON config file. Every other setting has its own STATSBANGER_*
+// variable; see settings below.
+const EnvConfigFile = "STATSBANGER_CONFIG"
+
+// Config holds every setting the backend needs at startup.
+type Config struct {
+	Host        string   `json:"host"`
+	Port        int      `json:"port"`
+	CORSOrigins []string `json:"corsOrigins"`
+
+	// TrustedProxies lists the addresses and CIDR ranges of the reverse
+	// proxies in front of the server. Only requests from them have their
+	// client IP taken from ProxyHeader, which the proxy must set to the
+	// address it received the request from rather than append to.
+	TrustedProxies []string `json:"trustedProxies"`
+	ProxyHeader    string   `json:"proxyHeader"`
+
+	// DBDriver selects the storage backend: "sqlite" uses DBPath and the
+	// SQLite settings below, "postgres" connects to DBDSN.
+	DBDriver string `json:"dbDriver"`
+	DBDSN    string `json:"dbDsn"`
+
+	DBPath     string `json:"dbPath"`
+	DBReadOnly bool   `json:"dbReadOnly"`
+	// DBJournalMode is applied to the SQLite file on every read-write
+	// open. Empty leaves the file's own mode alone, since switching it
+	// rewrites the file.
+	DBJournalMode     string   `json:"dbJournalMode"`
+	DBBusyTimeout     Duration `json:"dbBusyTimeout"`
+	DBMaxOpenConns    int      `json:"dbMaxOpenConns"`
+	DBMaxIdleConns    int      `json:"dbMaxIdleConns"`
+	DBConnMaxLifetime Duration `json:"dbConnMaxLifetime"`
+
+	// SchemaCheck decides what happens when the stat registries disagree
+	// with the database columns at startup: "strict" refuses to start,
+	// "warn" logs the mismatches and "off" skips the check.
+	SchemaCheck string `json:"schemaCheck"`
+
+	// LogLevel is the least severe level written: debug, info, warn or
+	// error. At debug every SQL statement is logged. LogFormat is "text"
+	// or "json".
+	LogLevel  string `json:"logLevel"`
+	LogFormat string `json:"logFormat"`
+	// DBSlowQuery is the duration above which a query is logged as slow;
+	// 0 disables slow query logging.
+	DBSlowQuery Duration `json:"dbSlowQuery"`
+
+	// ShutdownTimeout is how long the server waits for in-flight requests
+	// after SIGTERM before closing their connections; 0 waits for as long
+	// as they take.
+	ShutdownTimeout Duration `json:"shutdownTimeout"`
+
+	// AnonymousReads lets clients without an API key use the read
+	// endpoints, limited to AnonymousRateLimit requests per minute per IP
+	// address (0 = unlimited). Writes always need a key with the write
+	// scope.
+	AnonymousReads     bool `json:"anonymousReads"`
+	AnonymousRateLimit int  `json:"anonymousRateLimit"`
+
+	// GraphQLMaxRows bounds the stat rows one GraphQL request may resolve
+	// into nested lists (0 = unlimited). Every level of nesting multiplies
+	// them, well before the depth limit is reached.
+	GraphQLMaxRows int `json:"graphqlMaxRows"`
+}
+
+// Default returns the configuration used when nothing else is specified.
+func Default() *Config {
+	return &Config{
+		Host:        "",
+		Port:        3000,
+		CORSOrigins: []string{"https://www.statsbanger.com"},
+
+		TrustedProxies: nil,
+		ProxyHeader:    "X-Forwarded-For",
+
+		DBDriver: "sqlite",
+
+		DBPath:            "laligaDB.db",
+		DBReadOnly:        false,
+		DBJournalMode:     "",
+		DBBusyTimeout:     Duration(5 * time.Second),
+		DBMaxOpenConns:    8,
+		DBMaxIdleConns:    8,
+		DBConnMaxLifetime: Duration(30 * time.Minute),
+
+		SchemaCheck: SchemaCheckWarn,
+
+		LogLevel:    "info",
+		LogFormat:   LogFormatText,
+		DBSlowQuery: Duration(200 * time.Millisecond),
+
+		ShutdownTimeout: Duration(15 * time.Second),
+
+		AnonymousReads:     true,
+		AnonymousRateLimit: 60,
+
+		GraphQLMaxRows: 10000,
+	}
+}
+
+// setting binds one Config field to a command-line flag and an environment
+// variable. Both are parsed through the same flag.Value so they accept
+// identical syntax.
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	value func(c *Config) flag.Value
+}
+
+var settings = []setting{
+	{"host", "STATSBANGER_HOST", "interface to listen on",
+		func(c *Config) flag.Value { return (*stringValue)(&c.Host) }},
+	{"port", "STATSBANGER_PORT", "port to listen on",
+		func(c *Config) flag.Value { return (*intValue)(&c.Port) }},
+	{"cors-origins", "STATSBANGER_CORS_ORIGINS", "comma-separated list of allowed CORS origins",
+		func(c *Config) flag.Value { return (*listValue)(&c.CORSOrigins) }},
+	{"trusted-proxies", "STATSBANGER_TRUSTED_PROXIES", "comma-separated addresses or CIDR ranges of reverse proxies whose proxy header is trusted",
+		func(c *Config) flag.Value { return (*listValue)(&c.TrustedProxies) }},
+	{"proxy-header", "STATSBANGER_PROXY_HEADER", "header in which trusted proxies pass the client IP",
+		func(c *Config) flag.Value { return (*stringValue)(&c.ProxyHeader) }},
+
+	{"db-driver", "STATSBANGER_DB_DRIVER", "storage backend, sqlite or postgres",
+		func(c *Config) flag.Value { return (*stringValue)(&c.DBDriver) }},
+	{"db-dsn", "STATSBANGER_DB_DSN", "PostgreSQL connection string (postgres driver only)",
+		func(c *Config) flag.Value { return (*stringValue)(&c.DBDSN) }},
+	{"db", "STATSBANGER_DB_PATH", "path to the SQLite database",
+		func(c *Config) flag.Value { return (*stringValue)(&c.DBPath) }},
+	{"db-read-only", "STATSBANGER_DB_READ_ONLY", "open the database read-only",
+		func(c *Config) flag.Value { return (*boolValue)(&c.DBReadOnly) }},
+	{"db-journal-mode", "STATSBANGER_DB_JOURNAL_MODE", "SQLite journal mode to switch the file to (WAL, DELETE, TRUNCATE, PERSIST, MEMORY, OFF; empty = leave it as is)",
+		func(c *Config) flag.Value { return (*stringValue)(&c.DBJournalMode) }},
+	{"db-busy-timeout", "STATSBANGER_DB_BUSY_TIMEOUT", "how long SQLite waits on a locked database",
+		func(c *Config) flag.Value { return &c.DBBusyTimeout }},
+	{"db-max-open-conns", "STATSBANGER_DB_MAX_OPEN_CONNS", "maximum number of open database connections (0 = unlimited)",
+		func(c *Config) flag.Value { return (*intValue)(&c.DBMaxOpenConns) }},
+	{"db-max-idle-conns", "STATSBANGER_DB_MAX_IDLE_CONNS", "maximum number of idle database connections",
+		func(c *Config) flag.Value { return (*intValue)(&c.DBMaxIdleConns) }},
+	{"db-conn-max-lifetime", "STATSBANGER_DB_CONN_MAX_LIFETIME", "maximum time a connection may be reused (0 = forever)",
+		func(c *Config) flag.Value { return &c.DBConnMaxLifetime }},
+
+	{"schema-check", "STATSBANGER_SCHEMA_CHECK", "startup check of stat registries against the database: strict, warn or off",
+		func(c *Config) flag.Value { return (*stringValue)(&c.SchemaCheck) }},
+
+	{"log-level", "STATSBANGER_LOG_LEVEL", "least severe level logged: debug, info, warn or error",
+		func(c *Config) flag.Value { return (*stringValue)(&c.LogLevel) }},
+	{"log-format", "STATSBANGER_LOG_FORMAT", "log output format, text or json",
+		func(c *Config) flag.Value { return (*stringValue)(&c.LogFormat) }},
+	{"db-slow-query", "STATSBANGER_DB_SLOW_QUERY", "log queries that take longer than this (0 = never)",
+		func(c *Config) flag.Value { return &c.DBSlowQuery }},
+
+	{"shutdown-timeout", "STATSBANGER_SHUTDOWN_TIMEOUT", "how long to wait for in-flight requests on shutdown (0 = no limit)",
+		func(c *Config) flag.Value { return &c.ShutdownTimeout }},
+
+	{"anonymous-reads", "STATSBANGER_ANONYMOUS_READS", "allow reads without an API key",
+		func(c *Config) flag.Value { return (*boolValue)(&c.AnonymousReads) }},
+	{"anonymous-rate-limit", "STATSBANGER_ANONYMOUS_RATE_LIMIT", "requests per minute per IP address without an API key (0 = unlimited)",
+		func(c *Config) flag.Value { return (*intValue)(&c.AnonymousRateLimit) }},
+
+	{"graphql-max-rows", "STATSBANGER_GRAPHQL_MAX_ROWS", "stat rows one GraphQL request may resolve into nested lists (0 = unlimited)",
+		func(c *Config) flag.Value { return (*intValue)(&c.GraphQLMaxRows) }},
+}
+
+const usage = `usage: statsbanger [flags] [command] [args]
+
+The flags below are global: they go before the command, which defaults to
+serve. Anything after the command belongs to it.
+
+flags:`
+
+// Load builds the configuration from, in increasing order of precedence:
+// the defaults, an optional JSON config file, STATSBANGER_* environment
+// variables and command-line flags. It returns the arguments left over
+// after flag parsing so callers can dispatch subcommands on them.
+func Load(args []string) (*Config, []string, error) {
+	cfg := Default()
+
+	// Flags are parsed into a scratch copy first: the config file they may
+	// point at has to be applied before them.
+	scratch := Default()
+	fs := flag.NewFlagSet("statsbanger", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	configFile := fs.String("config", "", "path to a JSON config file (env "+EnvConfigFile+")")
+	for _, s := range settings {
+		fs.Var(s.value(scratch), s.flag, s.usage+" (env "+s.env+")")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	path := os.Getenv(EnvConfigFile)
+	if set["config"] {
+		path = *configFile
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if v, ok := os.LookupEnv(s.env); ok {
+			if err := s.value(cfg).Set(v); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+
+	for _, s := range settings {
+		if set[s.flag] {
+			if err := s.value(cfg).Set(fs.Lookup(s.flag).Value.String()); err != nil {
+				return nil, nil, fmt.Errorf("invalid -%s: %w", s.flag, err)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Storage backends accepted by DBDriver.
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// Modes accepted by SchemaCheck.
+const (
+	SchemaCheckStrict = "strict"
+	SchemaCheckWarn   = "warn"
+	SchemaCheckOff    = "off"
+)
+
+// Formats accepted by LogFormat.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+var journalModes = map[string]bool{
+	"":         true,
+	"WAL":      true,
+	"DELETE":   true,
+	"TRUNCATE": true,
+	"PERSIST":  true,
+	"MEMORY":   true,
+	"OFF":      true,
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Port < 1 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port must be between 1 and 65535, got %d", c.Port))
+	}
+	if len(c.CORSOrigins) == 0 {
+		errs = append(errs, errors.New("at least one CORS origin is required"))
+	}
+	for i, origin := range c.CORSOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid CORS origin %q", origin))
+			continue
+		}
+		// Browsers send the Origin header without a trailing slash.
+		c.CORSOrigins[i] = strings.TrimRight(origin, "/")
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Errorf("invalid trusted proxy %q (want an IP address or CIDR range)", proxy))
+		}
+	}
+	if len(c.TrustedProxies) > 0 && strings.TrimSpace(c.ProxyHeader) == "" {
+		errs = append(errs, errors.New("proxy header is required with trusted proxies"))
+	}
+
+	c.DBDriver = strings.ToLower(c.DBDriver)
+	switch c.DBDriver {
+	case DriverSQLite:
+		if strings.TrimSpace(c.DBPath) == "" {
+			errs = append(errs, errors.New("db path must not be empty"))
+		}
+	case DriverPostgres:
+		if strings.TrimSpace(c.DBDSN) == "" {
+			errs = append(errs, errors.New("db dsn is required by the postgres driver"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown db driver %q (want sqlite or postgres)", c.DBDriver))
+	}
+	c.DBJournalMode = strings.ToUpper(c.DBJournalMode)
+	if !journalModes[c.DBJournalMode] {
+		errs = append(errs, fmt.Errorf("unknown db journal mode %q", c.DBJournalMode))
+	}
+	if c.DBBusyTimeout < 0 {
+		errs = append(errs, errors.New("db busy timeout must not be negative"))
+	}
+	if c.DBMaxOpenConns < 0 || c.DBMaxIdleConns < 0 {
+		errs = append(errs, errors.New("db connection limits must not be negative"))
+	}
+	if c.DBMaxOpenConns > 0 && c.DBMaxIdleConns > c.DBMaxOpenConns {
+		errs = append(errs, fmt.Errorf("db max idle conns (%d) exceeds max open conns (%d)", c.DBMaxIdleConns, c.DBMaxOpenConns))
+	}
+	if c.DBConnMaxLifetime < 0 {
+		errs = append(errs, errors.New("db conn max lifetime must not be negative"))
+	}
+	c.SchemaCheck = strings.ToLower(c.SchemaCheck)
+	switch c.SchemaCheck {
+	case SchemaCheckStrict, SchemaCheckWarn, SchemaCheckOff:
+	default:
+		errs = append(errs, fmt.Errorf("unknown schema check mode %q (want strict, warn or off)", c.SchemaCheck))
+	}
+	c.LogLevel = strings.ToLower(c.LogLevel)
+	if _, err := c.Level(); err != nil {
+		errs = append(errs, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", c.LogLevel))
+	}
+	c.LogFormat = strings.ToLower(c.LogFormat)
+	if c.LogFormat != LogFormatText && c.LogFormat != LogFormatJSON {
+		errs = append(errs, fmt.Errorf("unknown log format %q (want text or json)", c.LogFormat))
+	}
+	if c.DBSlowQuery < 0 {
+		errs = append(errs, errors.New("db slow query threshold must not be negative"))
+	}
+	if c.ShutdownTimeout < 0 {
+		errs = append(errs, errors.New("shutdown timeout must not be negative"))
+	}
+	if c.AnonymousRateLimit < 0 {
+		errs = append(errs, errors.New("anonymous rate limit must not be negative"))
+	}
+	if c.GraphQLMaxRows < 0 {
+		errs = append(errs, errors.New("graphql max rows must not be negative"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Addr returns the address passed to fiber.App.Listen.
+func (c *Config) Addr() string {
+	return c.Host + ":" + strconv.Itoa(c.Port)
+}
+
+// Level returns LogLevel as a slog.Level.
+func (c *Config) Level() (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(c.LogLevel))
+	return level, err
+}
+
+// Log writes the effective configuration to the default logger.
+func (c *Config) Log() {
+	slog.Info("config", "listen_address", c.Addr(), "cors_origins", strings.Join(c.CORSOrigins, ", "))
+	if len(c.TrustedProxies) > 0 {
+		slog.Info("config", "trusted_proxies", strings.Join(c.TrustedProxies, ", "), "proxy_header", c.ProxyHeader)
+	}
+	if c.DBDriver == DriverPostgres {
+		slog.Info("config", "database", "postgres "+c.redactedDSN(), "read_only", c.DBReadOnly)
+	} else {
+		journal := c.DBJournalMode
+		if journal == "" {
+			journal = "unchanged"
+		}
+		slog.Info("config", "database", c.DBPath, "read_only", c.DBReadOnly,
+			"journal", journal, "busy_timeout", c.DBBusyTimeout.String())
+	}
+	slog.Info("config", "db_max_open", c.DBMaxOpenConns, "db_max_idle", c.DBMaxIdleConns,
+		"db_max_lifetime", c.DBConnMaxLifetime.String(), "db_slow_query", c.DBSlowQuery.String())
+	slog.Info("config", "schema_check", c.SchemaCheck, "log_level", c.LogLevel, "log_format", c.LogFormat)
+	slog.Info("config", "shutdown_timeout", c.ShutdownTimeout.String())
+	slog.Info("config", "anonymous_reads", enabled(c.AnonymousReads), "anonymous_rate_limit", c.AnonymousRateLimit)
+	slog.Info("config", "graphql_max_rows", c.GraphQLMaxRows)
+}
+
+// redactedDSN returns DBDSN with any password removed, for logging.
+func (c *Config) redactedDSN() string {
+	if u, err := url.Parse(c.DBDSN); err == nil && u.Scheme != "" {
+		return u.Redacted()
+	}
+	// Keyword/value form: host=... password=...
+	fields := strings.Fields(c.DBDSN)
+	for i, field := range fields {
+		if strings.HasPrefix(field, "password=") {
+			fields[i] = "password=xxxxx"
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+func enabled(on bool) string {
+	if on {
+		return "enabled"
+	}
+	return "disabled"
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// unsetEnv removes every STATSBANGER_* variable for the duration of t, so
+// the environment of the test run cannot leak into Load.
+func unsetEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range append([]string{EnvConfigFile}, envNames()...) {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+func envNames() []string {
+	names := make([]string, len(settings))
+	for i, s := range settings {
+		names[i] = s.env
+	}
+	return names
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	unsetEnv(t)
+	file := writeFile(t, `{"port": 4000, "dbPath": "file.db", "logLevel": "warn", "shutdownTimeout": "20s"}`)
+	t.Setenv(EnvConfigFile, file)
+	t.Setenv("STATSBANGER_PORT", "5000")
+	t.Setenv("STATSBANGER_DB_PATH", "env.db")
+
+	cfg, rest, err := Load([]string{"-port", "6000", "migrate", "up"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != 6000 {
+		t.Errorf("port %d, want 6000 from the flag", cfg.Port)
+	}
+	if cfg.DBPath != "env.db" {
+		t.Errorf("db path %q, want env.db from the environment", cfg.DBPath)
+	}
+	if cfg.LogLevel != "warn" || cfg.ShutdownTimeout != Duration(20*time.Second) {
+		t.Errorf("log level %q and shutdown timeout %s, want warn and 20s from the file", cfg.LogLevel, cfg.ShutdownTimeout)
+	}
+	if cfg.AnonymousRateLimit != Default().AnonymousRateLimit {
+		t.Errorf("anonymous rate limit %d, want the default", cfg.AnonymousRateLimit)
+	}
+	if !slices.Equal(rest, []string{"migrate", "up"}) {
+		t.Errorf("remaining args %q", rest)
+	}
+
+	// -config wins over the environment variable naming a file.
+	other := writeFile(t, `{"logLevel": "error"}`)
+	cfg, _, err = Load([]string{"-config", other})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.LogLevel != "error" || cfg.ShutdownTimeout != Default().ShutdownTimeout {
+		t.Errorf("with -config: log level %q, shutdown timeout %s", cfg.LogLevel, cfg.ShutdownTimeout)
+	}
+}
+
+// Flags are parsed into a scratch config and then set again from their
+// String form, so every kind of value has to survive the trip.
+func TestLoadReappliesFlags(t *testing.T) {
+	unsetEnv(t)
+	cfg, _, err := Load([]string{
+		"-cors-origins", "https://a.example, https://b.example/",
+		"-trusted-proxies", "10.0.0.0/8,192.0.2.1",
+		"-db-busy-timeout", "1m30s",
+		"-db-read-only",
+		"-anonymous-reads=false",
+		"-db-max-open-conns", "4", "-db-max-idle-conns", "2",
+		"-host", "127.0.0.1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(cfg.CORSOrigins, []string{"https://a.example", "https://b.example"}) {
+		t.Errorf("cors origins %q", cfg.CORSOrigins)
+	}
+	if !slices.Equal(cfg.TrustedProxies, []string{"10.0.0.0/8", "192.0.2.1"}) {
+		t.Errorf("trusted proxies %q", cfg.TrustedProxies)
+	}
+	if cfg.DBBusyTimeout != Duration(90*time.Second) || !cfg.DBReadOnly || cfg.AnonymousReads ||
+		cfg.DBMaxOpenConns != 4 || cfg.DBMaxIdleConns != 2 || cfg.Host != "127.0.0.1" {
+		t.Errorf("config %+v", cfg)
+	}
+}
+
+// oneWay is a flag.Value whose String cannot be set again.
+type oneWay struct{ set bool }
+
+func (v *oneWay) Set(s string) error {
+	if s == "applied" {
+		return errors.New("cannot apply twice")
+	}
+	v.set = true
+	return nil
+}
+
+func (v *oneWay) String() string {
+	if v.set {
+		return "applied"
+	}
+	return ""
+}
+
+func TestLoadReapplyError(t *testing.T) {
+	unsetEnv(t)
+	saved := settings
+	t.Cleanup(func() { settings = saved })
+	settings = append(settings[:len(settings):len(settings)], setting{"one-way", "STATSBANGER_ONE_WAY", "test setting",
+		func(c *Config) flag.Value { return new(oneWay) }})
+
+	_, _, err := Load([]string{"-one-way", "x"})
+	if err == nil || !strings.Contains(err.Error(), "invalid -one-way: cannot apply twice") {
+		t.Errorf("error %v, want the one of re-applying -one-way", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		env  map[string]string
+		file string
+		args []string
+		want string
+	}{
+		{name: "env int", env: map[string]string{"STATSBANGER_PORT": "http"}, want: "invalid STATSBANGER_PORT"},
+		{name: "env bool", env: map[string]string{"STATSBANGER_ANONYMOUS_READS": "maybe"}, want: "invalid STATSBANGER_ANONYMOUS_READS"},
+		{name: "env duration", env: map[string]string{"STATSBANGER_DB_BUSY_TIMEOUT": "5"}, want: "invalid STATSBANGER_DB_BUSY_TIMEOUT"},
+		{name: "flag int", args: []string{"-port", "http"}, want: "-port"},
+		{name: "flag duration", args: []string{"-shutdown-timeout", "soon"}, want: "-shutdown-timeout"},
+		{name: "unknown flag", args: []string{"-prot", "3000"}, want: "-prot"},
+		{name: "unknown file field", file: `{"prot": 3000}`, want: `unknown field "prot"`},
+		{name: "file duration", file: `{"dbBusyTimeout": "5"}`, want: "parse config file"},
+		{name: "missing file", args: []string{"-config", "/nonexistent/config.json"}, want: "read config file"},
+		{name: "invalid value", env: map[string]string{"STATSBANGER_LOG_FORMAT": "xml"}, want: `unknown log format "xml"`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			unsetEnv(t)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			if tt.file != "" {
+				t.Setenv(EnvConfigFile, writeFile(t, tt.file))
+			}
+			_, _, err := Load(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %v, want one mentioning %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNormalizes(t *testing.T) {
+	cfg := Default()
+	cfg.CORSOrigins = []string{"https://www.statsbanger.com/", "*"}
+	cfg.DBDriver = "SQLite"
+	cfg.DBJournalMode = "wal"
+	cfg.SchemaCheck = "Strict"
+	cfg.LogLevel = "DEBUG"
+	cfg.LogFormat = "JSON"
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(cfg.CORSOrigins, []string{"https://www.statsbanger.com", "*"}) {
+		t.Errorf("cors origins %q", cfg.CORSOrigins)
+	}
+	if cfg.DBDriver != DriverSQLite || cfg.DBJournalMode != "WAL" || cfg.SchemaCheck != SchemaCheckStrict ||
+		cfg.LogLevel != "debug" || cfg.LogFormat != LogFormatJSON {
+		t.Errorf("config %+v", cfg)
+	}
+}
+
+func TestValidateReportsEveryError(t *testing.T) {
+	cfg := Default()
+	cfg.Port = 0
+	cfg.CORSOrigins = []string{"www.statsbanger.com"}
+	cfg.DBJournalMode = "fast"
+	cfg.TrustedProxies = []string{"proxy.internal"}
+	cfg.GraphQLMaxRows = -1
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("no error")
+	}
+	for _, want := range []string{
+		"port must be between 1 and 65535",
+		`invalid CORS origin "www.statsbanger.com"`,
+		`unknown db journal mode "FAST"`,
+		`invalid trusted proxy "proxy.internal"`,
+		"graphql max rows must not be negative",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%v\ndoes not report %s", err, want)
+		}
+	}
+
+	if err := Default().Validate(); err != nil {
+		t.Errorf("defaults: %v", err)
+	}
+}
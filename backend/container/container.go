@@ -0,0 +1,92 @@
+package container
+
+import (
+	"log/slog"
+
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/auth"
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/database"
+	"github.com/plinphon/StatsBanger/backend/metrics"
+
+	export "github.com/plinphon/StatsBanger/backend/api/export"
+	matches "github.com/plinphon/StatsBanger/backend/api/matches"
+	tournament "github.com/plinphon/StatsBanger/backend/api/tournament"
+
+	team "github.com/plinphon/StatsBanger/backend/api/team/info"
+	teamMatchStat "github.com/plinphon/StatsBanger/backend/api/team/match"
+	teamSeasonStat "github.com/plinphon/StatsBanger/backend/api/team/season"
+
+	player "github.com/plinphon/StatsBanger/backend/api/player/info"
+	playerMatchStat "github.com/plinphon/StatsBanger/backend/api/player/match"
+	playerSeasonStat "github.com/plinphon/StatsBanger/backend/api/player/season"
+)
+
+// Container owns the shared database handle and every repository built on
+// top of it. It is created once at startup and handed to the routes.
+type Container struct {
+	Config  *config.Config
+	DB      *gorm.DB
+	Metrics *metrics.Metrics
+
+	Keys *auth.KeyRepository
+
+	Matches     *matches.MatchRepository
+	Tournaments *tournament.TournamentRepository
+
+	Teams           *team.TeamRepository
+	TeamMatchStats  *teamMatchStat.TeamMatchStatRepository
+	TeamSeasonStats *teamSeasonStat.TeamSeasonStatRepository
+
+	Players           *player.PlayerRepository
+	PlayerMatchStats  *playerMatchStat.PlayerMatchStatRepository
+	PlayerSeasonStats *playerSeasonStat.PlayerSeasonStatRepository
+
+	Exports *export.ExportRepository
+}
+
+// New opens the database described by cfg and wires every repository to it.
+func New(cfg *config.Config) (*Container, error) {
+	db, err := database.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithDB(cfg, db), nil
+}
+
+// NewWithDB wires every repository to an already opened database handle.
+// Each repository gets a handle tagged with its name, under which its
+// queries are timed.
+func NewWithDB(cfg *config.Config, db *gorm.DB) *Container {
+	m := metrics.New()
+	if err := m.InstrumentDB(db); err != nil {
+		slog.Warn("metrics: database statements are not timed", "error", err)
+	}
+
+	return &Container{
+		Config:  cfg,
+		DB:      db,
+		Metrics: m,
+
+		Keys: auth.NewKeyRepository(metrics.Tag(db, "api_keys")),
+
+		Matches:     matches.NewMatchRepository(metrics.Tag(db, "matches")),
+		Tournaments: tournament.NewTournamentRepository(metrics.Tag(db, "tournaments")),
+
+		Teams:           team.NewTeamRepository(metrics.Tag(db, "teams")),
+		TeamMatchStats:  teamMatchStat.NewTeamMatchStatRepository(metrics.Tag(db, "team_match_stats")),
+		TeamSeasonStats: teamSeasonStat.NewTeamSeasonStatRepository(metrics.Tag(db, "team_season_stats")),
+
+		Players:           player.NewPlayerRepository(metrics.Tag(db, "players")),
+		PlayerMatchStats:  playerMatchStat.NewPlayerMatchStatRepository(metrics.Tag(db, "player_match_stats")),
+		PlayerSeasonStats: playerSeasonStat.NewPlayerSeasonStatRepository(metrics.Tag(db, "player_season_stats")),
+
+		Exports: export.NewExportRepository(metrics.Tag(db, "exports")),
+	}
+}
+
+// Close closes the shared database handle.
+func (c *Container) Close() error {
+	return database.Close(c.DB)
+}
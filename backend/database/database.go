@@ -0,0 +1,128 @@
+package database
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/logging"
+)
+
+// Open opens the database described by cfg with the configured driver,
+// applies the connection-pool limits, and checks that it is reachable.
+// The returned handle is safe for concurrent use and should be shared by
+// every repository.
+func Open(cfg *config.Config) (*gorm.DB, error) {
+	dialector, err := Dialector(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		TranslateError: true,
+		Logger:         logging.NewGormLogger(slog.Default(), time.Duration(cfg.DBSlowQuery)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("open database %s: %w", Name(cfg), err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.DBConnMaxLifetime))
+
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("ping database %s: %w", Name(cfg), err)
+	}
+
+	return db, nil
+}
+
+// Dialector returns the GORM dialector for the driver selected by cfg.
+func Dialector(cfg *config.Config) (gorm.Dialector, error) {
+	switch cfg.DBDriver {
+	case config.DriverPostgres:
+		pgConfig, err := pgx.ParseConfig(cfg.DBDSN)
+		if err != nil {
+			return nil, fmt.Errorf("parse postgres dsn: %w", err)
+		}
+		if cfg.DBReadOnly {
+			pgConfig.RuntimeParams["default_transaction_read_only"] = "on"
+		}
+		return postgres.New(postgres.Config{Conn: stdlib.OpenDB(*pgConfig)}), nil
+	case config.DriverSQLite, "":
+		return sqlite.Open(DSN(cfg)), nil
+	}
+	return nil, fmt.Errorf("unknown db driver %q", cfg.DBDriver)
+}
+
+// Name describes the database of cfg for error messages, without
+// credentials.
+func Name(cfg *config.Config) string {
+	if cfg.DBDriver == config.DriverPostgres {
+		if pgConfig, err := pgx.ParseConfig(cfg.DBDSN); err == nil {
+			return fmt.Sprintf("postgres://%s:%d/%s", pgConfig.Host, pgConfig.Port, pgConfig.Database)
+		}
+		return "postgres"
+	}
+	return cfg.DBPath
+}
+
+// DSN builds the go-sqlite3 connection string for cfg. The driver runs the
+// pragmas encoded here on every new connection in the pool.
+func DSN(cfg *config.Config) string {
+	params := url.Values{}
+	params.Set("_busy_timeout", fmt.Sprint(time.Duration(cfg.DBBusyTimeout).Milliseconds()))
+	// Enforce the schema's foreign keys as PostgreSQL does.
+	params.Set("_foreign_keys", "1")
+
+	if cfg.DBReadOnly {
+		// Switching the journal mode writes to the database file, so it
+		// is left as-is for read-only handles.
+		params.Set("mode", "ro")
+	} else if cfg.DBJournalMode != "" {
+		params.Set("_journal_mode", cfg.DBJournalMode)
+	}
+
+	// The path is escaped so that a "?" or "#" in it is not taken for the
+	// start of the query or fragment.
+	u := url.URL{Scheme: "file", Opaque: (&url.URL{Path: cfg.DBPath}).EscapedPath(), RawQuery: params.Encode()}
+	return u.String()
+}
+
+// Close releases every connection held by db.
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// QuoteColumns quotes each column name for the dialect of db and joins them
+// with commas, ready to splice into a raw SELECT. Column names must still
+// be checked against a registry first; quoting only keeps mixed-case names
+// such as "penaltyConceded" intact on PostgreSQL.
+func QuoteColumns(db *gorm.DB, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		var b strings.Builder
+		db.Dialector.QuoteTo(&b, column)
+		quoted[i] = b.String()
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/plinphon/StatsBanger/backend/config"
+)
+
+func openFile(t *testing.T, cfg *config.Config) {
+	t.Helper()
+
+	db, err := Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Close(db)
+	if err := db.Exec("CREATE TABLE IF NOT EXISTS t (id INTEGER)").Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// A path may hold characters that delimit the query and fragment of a
+// URI.
+func TestOpenEscapesPath(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.Default()
+	cfg.DBPath = filepath.Join(dir, "la liga?v=2#100%.db")
+	openFile(t, cfg)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != filepath.Base(cfg.DBPath) {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("files %q, want only %q", names, filepath.Base(cfg.DBPath))
+	}
+}
+
+// The default configuration leaves the journal mode of an existing file
+// alone; only an explicit one is applied.
+func TestOpenJournalMode(t *testing.T) {
+	cfg := config.Default()
+	cfg.DBPath = filepath.Join(t.TempDir(), "stats.db")
+
+	mode := func() string {
+		db, err := Open(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer Close(db)
+		var mode string
+		if err := db.Raw("PRAGMA journal_mode").Scan(&mode).Error; err != nil {
+			t.Fatal(err)
+		}
+		return mode
+	}
+
+	openFile(t, cfg)
+	if got := mode(); got != "delete" {
+		t.Errorf("default journal mode = %s, want the file's delete", got)
+	}
+	if _, err := os.Stat(cfg.DBPath + "-wal"); !os.IsNotExist(err) {
+		t.Errorf("default open left a WAL file: %v", err)
+	}
+
+	cfg.DBJournalMode = "WAL"
+	if got := mode(); got != "wal" {
+		t.Errorf("journal mode = %s, want wal", got)
+	}
+	cfg.DBJournalMode = ""
+	if got := mode(); got != "wal" {
+		t.Errorf("default journal mode = %s, want the file's wal", got)
+	}
+}
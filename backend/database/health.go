@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// CoreTables are the tables the API cannot answer without.
+func CoreTables() []string {
+	return []string{
+		models.Match{}.TableName(),
+		models.Team{}.TableName(),
+		models.Player{}.TableName(),
+		models.TeamMatchStat{}.TableName(),
+		models.TeamSeasonStat{}.TableName(),
+		models.PlayerMatchStat{}.TableName(),
+		models.PlayerSeasonStat{}.TableName(),
+	}
+}
+
+// Ready reports whether db can serve the API: the database answers a ping
+// and every core table exists. A nil error means ready.
+func Ready(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping database: %w", err)
+	}
+
+	migrator := db.WithContext(ctx).Migrator()
+	var missing []string
+	for _, table := range CoreTables() {
+		if !migrator.HasTable(table) {
+			missing = append(missing, table)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("check tables: %w", err)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing tables: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// TableCheck compares one stat registry with the live columns of its table.
+type TableCheck struct {
+	Entity string `json:"entity"`
+	Table  string `json:"table"`
+	// MissingTable is set when the table does not exist at all.
+	MissingTable bool `json:"missingTable"`
+	// MissingColumns are registry keys and stats with no matching column;
+	// requesting one of them fails at query time.
+	MissingColumns []string `json:"missingColumns"`
+	// UnregisteredColumns exist in the table but in no registry, so the
+	// API can never return them.
+	UnregisteredColumns []string `json:"unregisteredColumns"`
+}
+
+// OK reports whether the registry and the table agree.
+func (t TableCheck) OK() bool {
+	return !t.MissingTable && len(t.MissingColumns) == 0 && len(t.UnregisteredColumns) == 0
+}
+
+// SchemaReport is the result of CheckSchema.
+type SchemaReport struct {
+	OK     bool         `json:"ok"`
+	Tables []TableCheck `json:"tables"`
+}
+
+// Problems describes every mismatch in the report, one per line.
+func (r *SchemaReport) Problems() []string {
+	var problems []string
+	for _, t := range r.Tables {
+		if t.MissingTable {
+			problems = append(problems, fmt.Sprintf("%s: table %s does not exist", t.Entity, t.Table))
+			continue
+		}
+		if len(t.MissingColumns) > 0 {
+			problems = append(problems, fmt.Sprintf("%s: registry fields missing from %s: %s",
+				t.Entity, t.Table, strings.Join(t.MissingColumns, ", ")))
+		}
+		if len(t.UnregisteredColumns) > 0 {
+			problems = append(problems, fmt.Sprintf("%s: columns of %s missing from the registry: %s",
+				t.Entity, t.Table, strings.Join(t.UnregisteredColumns, ", ")))
+		}
+	}
+	return problems
+}
+
+// CheckSchema compares every stat registry with the columns the database
+// actually has.
+func CheckSchema(db *gorm.DB) (*SchemaReport, error) {
+	report := &SchemaReport{OK: true}
+	for _, entity := range models.StatEntities() {
+		check, err := checkTable(db, models.StatRegistries[entity])
+		if err != nil {
+			return nil, err
+		}
+		report.OK = report.OK && check.OK()
+		report.Tables = append(report.Tables, check)
+	}
+	return report, nil
+}
+
+func checkTable(db *gorm.DB, registry *models.StatRegistry) (TableCheck, error) {
+	check := TableCheck{
+		Entity:              registry.Entity,
+		Table:               registry.Table,
+		MissingColumns:      []string{},
+		UnregisteredColumns: []string{},
+	}
+
+	if !db.Migrator().HasTable(registry.Table) {
+		check.MissingTable = true
+		return check, nil
+	}
+	columns, err := db.Migrator().ColumnTypes(registry.Table)
+	if err != nil {
+		return check, fmt.Errorf("read columns of %s: %w", registry.Table, err)
+	}
+
+	live := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		live[column.Name()] = true
+	}
+
+	known := make(map[string]bool, len(live))
+	for _, field := range append(append([]string(nil), registry.Keys...), registry.Fields()...) {
+		known[field] = true
+		if !live[field] {
+			check.MissingColumns = append(check.MissingColumns, field)
+		}
+	}
+	for name := range live {
+		if !known[name] {
+			check.UnregisteredColumns = append(check.UnregisteredColumns, name)
+		}
+	}
+	sort.Strings(check.UnregisteredColumns)
+	return check, nil
+}
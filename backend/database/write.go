@@ -0,0 +1,67 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Row is one record of a stat table, keyed by column name.
+type Row map[string]interface{}
+
+// InsertRows inserts rows into table in a single transaction. Any row that
+// collides with an existing key aborts the whole batch with
+// gorm.ErrDuplicatedKey.
+func InsertRows(db *gorm.DB, table string, rows []Row) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			if err := InsertRow(tx, table, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpsertRows inserts rows into table in a single transaction. A row whose
+// key columns already exist overwrites the columns it carries and leaves
+// every other column untouched.
+func UpsertRows(db *gorm.DB, table string, keys []string, rows []Row) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			if err := UpsertRow(tx, table, keys, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// InsertRow inserts a single row into table.
+func InsertRow(db *gorm.DB, table string, row Row) error {
+	return db.Table(table).Create(map[string]interface{}(row)).Error
+}
+
+// UpsertRow inserts a single row into table, or overwrites the columns it
+// carries when its key columns already exist.
+func UpsertRow(db *gorm.DB, table string, keys []string, row Row) error {
+	conflict := make([]clause.Column, len(keys))
+	isKey := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		conflict[i] = clause.Column{Name: key}
+		isKey[key] = true
+	}
+
+	var update []string
+	for column := range row {
+		if !isKey[column] {
+			update = append(update, column)
+		}
+	}
+
+	onConflict := clause.OnConflict{Columns: conflict, DoNothing: true}
+	if len(update) > 0 {
+		onConflict = clause.OnConflict{Columns: conflict, DoUpdates: clause.AssignmentColumns(update)}
+	}
+
+	return db.Table(table).Clauses(onConflict).Create(map[string]interface{}(row)).Error
+}
@@ -0,0 +1,160 @@
+// Package fixture builds a small, deterministic copy of the StatsBanger
+// database for tests. The schema comes from the embedded migrations; the
+// rows are a hand-picked slice of the 23/24 LaLiga season.
+//
+// By default every fixture is an in-memory SQLite database. When
+// STATSBANGER_TEST_POSTGRES_DSN points at a PostgreSQL server, each fixture
+// is instead a fresh schema in that database, so the same tests exercise
+// the PostgreSQL backend:
+//
+//	STATSBANGER_TEST_POSTGRES_DSN=postgres://postgres@localhost/statsbanger_test go test ./...
+package fixture
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/plinphon/StatsBanger/backend/migrations"
+)
+
+// EnvPostgresDSN names the environment variable that switches fixtures to
+// PostgreSQL.
+const EnvPostgresDSN = "STATSBANGER_TEST_POSTGRES_DSN"
+
+//go:embed seed.sql
+var seed string
+
+// IDs of the rows in seed.sql, for use in test assertions.
+const (
+	TournamentID = 8
+	SeasonID     = 52376
+
+	BetisID      = 2816
+	AthleticID   = 2825
+	RealMadridID = 2829
+
+	BellinghamID = 991011
+	ViniciusID   = 868812
+	RudigerID    = 142622
+	GuruzetaID   = 605672
+	WilliamsID   = 783374
+	SimonID      = 797291
+	IscoID       = 103417
+	WillianID    = 123223
+	PezzellaID   = 158241
+
+	// Played matches.
+	AthleticRealMadridID = 11369286
+	AthleticBetisID      = 11368591
+	RealMadridAthleticID = 11368707
+
+	// RealMadridBetisID has no score and no stat rows yet.
+	RealMadridBetisID = 11368620
+)
+
+var counter atomic.Int64
+
+var gormConfig = &gorm.Config{
+	Logger:         logger.Default.LogMode(logger.Silent),
+	TranslateError: true,
+}
+
+// Open returns a fresh database loaded with the fixture schema and rows.
+// Every call gets its own database, so tests may run in parallel and mutate
+// their copy freely. It is removed when tb finishes.
+func Open(tb testing.TB) *gorm.DB {
+	tb.Helper()
+
+	var db *gorm.DB
+	var err error
+	if dsn := os.Getenv(EnvPostgresDSN); dsn != "" {
+		db, err = openPostgres(tb, dsn)
+	} else {
+		db, err = openSQLite()
+	}
+	if err != nil {
+		tb.Fatalf("open fixture: %v", err)
+	}
+	// Closing the last connection drops an in-memory SQLite database. The
+	// cleanup runs before the one that drops a PostgreSQL schema.
+	sqlDB, err := db.DB()
+	if err != nil {
+		tb.Fatalf("open fixture: %v", err)
+	}
+	tb.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		tb.Fatalf("open fixture: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		tb.Fatalf("create fixture schema: %v", err)
+	}
+	if err := db.Exec(seed).Error; err != nil {
+		tb.Fatalf("seed fixture: %v", err)
+	}
+
+	return db
+}
+
+func openSQLite() (*gorm.DB, error) {
+	name := fmt.Sprintf("file:fixture-%d?mode=memory&cache=shared&_foreign_keys=1", counter.Add(1))
+
+	db, err := gorm.Open(sqlite.Open(name), gormConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// An in-memory database disappears with its last connection, so keep
+	// one idle connection alive for the lifetime of the handle.
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxIdleConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+
+	return db, nil
+}
+
+// openPostgres creates a schema unique to this process and call, and
+// returns a handle whose search_path points at it.
+func openPostgres(tb testing.TB, dsn string) (*gorm.DB, error) {
+	schema := fmt.Sprintf("fixture_%d_%d", os.Getpid(), counter.Add(1))
+
+	admin, err := gorm.Open(postgres.Open(dsn), gormConfig)
+	if err != nil {
+		return nil, err
+	}
+	created := false
+	tb.Cleanup(func() {
+		if created {
+			admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		}
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		return nil, err
+	}
+	created = true
+
+	pgConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	pgConfig.RuntimeParams["search_path"] = schema
+
+	return gorm.Open(postgres.New(postgres.Config{Conn: stdlib.OpenDB(*pgConfig)}), gormConfig)
+}
@@ -0,0 +1,74 @@
+-- Deterministic fixture rows: three LaLiga 23/24 clubs, nine players and
+-- four matches, one of which has not been played yet.
+
+INSERT INTO unique_tournament_info (unique_tournament_id, tournament_name, country) VALUES
+	(8, 'Laliga', 'Spain');
+
+INSERT INTO season_info (season_id, season_name, year) VALUES
+	(52376, 'LaLiga 23/24', '23/24');
+
+INSERT INTO team_info (team_id, team_name, home_stadium) VALUES
+	(2816, 'Real Betis', 'Benito Villamarín'),
+	(2825, 'Athletic Bilbao', 'San Mamés'),
+	(2829, 'Real Madrid', 'Santiago Bernabéu');
+
+INSERT INTO player_info (player_id, player_name, birthday_timestamp, position, height, preferred_foot, nationality) VALUES
+	(991011, 'Jude Bellingham', 1056844800, 'M', 188.0, 'Right', 'England'),
+	(868812, 'Vinícius Júnior', 963360000, 'F', 176.0, 'Right', 'Brazil'),
+	(142622, 'Antonio Rüdiger', 731116800, 'D', 191.0, 'Right', 'Germany'),
+	(605672, 'Gorka Guruzeta', 842486400, 'F', 188.0, 'Right', 'Spain'),
+	(783374, 'Iñaki Williams', 771638400, 'M', 186.0, 'Right', 'Ghana'),
+	(797291, 'Unai Simón', 865987200, 'G', 189.0, 'Right', 'Spain'),
+	(103417, 'Isco', 703814400, 'M', 176.0, 'Right', 'Spain'),
+	(123223, 'Willian José', 690854400, 'F', 189.0, 'Right', 'Brazil'),
+	(158241, 'Germán Pezzella', 677980800, 'D', 187.0, 'Right', 'Argentina');
+
+INSERT INTO match_info (match_id, unique_tournament_id, season_id, matchday, home_team_id, away_team_id, home_win, home_score, away_score, injury_time1, injury_time2, current_period_start_timestamp) VALUES
+	(11369286, 8, 52376, 1, 2825, 2829, 0, 0, 2, 4, 0, 1691875475),
+	(11368591, 8, 52376, 3, 2825, 2816, 1, 4, 2, 8, 4, 1693171809),
+	(11368707, 8, 52376, 30, 2829, 2825, 1, 2, 0, 2, 4, 1711915357),
+	(11368620, 8, 52376, 38, 2829, 2816, NULL, NULL, NULL, NULL, NULL, 1716667462);
+
+INSERT INTO player_stat (player_id, unique_tournament_id, season_id, team_id, minutes_played, appearances, matches_started, goals, expected_goals, assists, expected_assists, total_shots, key_passes, accurate_passes, total_passes, accurate_passes_percentage, tackles, interceptions, clearances, saves, clean_sheet, goals_conceded, yellow_cards, red_cards, rating) VALUES
+	(991011, 8, 52376, 2829, 2444, 28, 28, 19, 11.8, 6, 5.2, 71, 52, 1106, 1236, 89.5, 39, 12, 14, NULL, NULL, NULL, 8, 1, 7.63),
+	(868812, 8, 52376, 2829, 1960, 26, 24, 15, 13.1, 5, 6.4, 85, 47, 676, 832, 81.3, 19, 6, 3, NULL, NULL, NULL, 6, 0, 7.71),
+	(142622, 8, 52376, 2829, 2482, 30, 28, 1, 1.4, 1, 0.3, 11, 3, 1960, 2110, 92.9, 38, 28, 104, NULL, NULL, NULL, 6, 0, 7.02),
+	(605672, 8, 52376, 2825, 2167, 33, 25, 14, 13.5, 1, 1.9, 58, 19, 230, 349, 65.9, 12, 4, 31, NULL, NULL, NULL, 4, 0, 6.97),
+	(783374, 8, 52376, 2825, 2223, 30, 27, 6, 7.9, 5, 4.1, 57, 38, 440, 585, 75.2, 23, 7, 9, NULL, NULL, NULL, 2, 0, 7.04),
+	(797291, 8, 52376, 2825, 3150, 35, 35, 0, NULL, 0, 0.1, 0, 0, 771, 1092, 70.6, 0, 0, 11, 84, 12, 35, 2, 0, 6.93),
+	(103417, 8, 52376, 2816, 2042, 25, 24, 8, 6.3, 3, 4.7, 40, 58, 1151, 1309, 87.9, 32, 11, 9, NULL, NULL, NULL, 9, 0, 7.27),
+	(123223, 8, 52376, 2816, 1592, 30, 16, 10, 9.4, 2, 1.3, 41, 14, 247, 377, 65.5, 7, 1, 16, NULL, NULL, NULL, 3, 0, 6.83),
+	(158241, 8, 52376, 2816, 2480, 28, 28, 1, 1.2, 0, 0.2, 14, 2, 1477, 1609, 91.8, 27, 30, 139, NULL, NULL, NULL, 7, 1, 6.91);
+
+INSERT INTO team_stat (team_id, unique_tournament_id, season_id, matches, goals_scored, goals_conceded, expected_goals, assists, shots, shots_on_target, big_chances, clean_sheets, average_ball_possession, total_passes, accurate_passes, accurate_passes_percentage, tackles, interceptions, saves, yellow_cards, red_cards, avg_rating) VALUES
+	(2816, 8, 52376, 38, 48, 45, 50.2, 33, 444, 151, 82, 10, 55.6, 18640, 15520, 83.3, 634, 312, 97, 106, 3, 6.79),
+	(2825, 8, 52376, 38, 61, 37, 57.9, 42, 500, 182, 101, 14, 50.3, 15406, 11930, 77.4, 667, 276, 84, 78, 2, 6.85),
+	(2829, 8, 52376, 38, 87, 26, 74.1, 63, 590, 246, 140, 18, 60.3, 21853, 19455, 89.0, 580, 289, 82, 74, 4, 7.09);
+
+INSERT INTO team_match_stat (match_id, team_id, ball_possession, expected_goals, big_chances, total_shots, goalkeeper_saves, corner_kicks, fouls, passes, tackles, yellow_cards, red_cards, shots_on_target) VALUES
+	(11369286, 2825, 44, 1.02, 2, 12, 2, 5, 14, 382, 17, 3, 0, 3),
+	(11369286, 2829, 56, 1.75, 3, 14, 3, 6, 11, 494, 12, 2, 0, 4),
+	(11368591, 2825, 52, 2.71, 5, 18, 3, 7, 10, 455, 19, 1, 0, 8),
+	(11368591, 2816, 48, 1.30, 2, 11, 4, 3, 13, 420, 14, 3, 0, 5),
+	(11368707, 2829, 61, 1.88, 3, 15, 5, 5, 9, 561, 13, 1, 0, 7),
+	(11368707, 2825, 39, 0.95, 1, 9, 5, 2, 12, 356, 21, 2, 0, 5);
+
+INSERT INTO player_match_stat (match_id, player_id, team_id, minutes_played, rating, goals, goal_assist, expected_goals, expected_assists, total_pass, accurate_pass, key_pass, touches, saves, total_tackle, fouls) VALUES
+	(11369286, 991011, 2829, 90, 8.4, 1, 0, 0.45, 0.10, 41, 37, 2, 58, NULL, 2, 1),
+	(11369286, 868812, 2829, 88, 7.1, 0, 0, 0.31, 0.22, 30, 24, 3, 51, NULL, 1, 0),
+	(11369286, 142622, 2829, 90, 7.3, 0, 0, 0.05, 0.00, 62, 58, 0, 71, NULL, 3, 1),
+	(11369286, 605672, 2825, 72, 6.2, 0, 0, 0.38, 0.04, 14, 9, 0, 22, NULL, 0, 2),
+	(11369286, 783374, 2825, 90, 6.6, 0, 0, 0.21, 0.12, 26, 19, 1, 40, NULL, 1, 1),
+	(11369286, 797291, 2825, 90, 6.5, 0, 0, NULL, NULL, 31, 22, 0, 38, 2, 0, 0),
+	(11368591, 605672, 2825, 85, 8.6, 2, 0, 1.12, 0.10, 17, 12, 1, 29, NULL, 0, 1),
+	(11368591, 783374, 2825, 90, 8.1, 1, 2, 0.64, 0.55, 29, 23, 4, 47, NULL, 2, 0),
+	(11368591, 797291, 2825, 90, 6.8, 0, 0, NULL, NULL, 28, 20, 0, 35, 3, 0, 0),
+	(11368591, 103417, 2816, 90, 7.5, 1, 1, 0.40, 0.36, 64, 58, 3, 81, NULL, 2, 2),
+	(11368591, 123223, 2816, 78, 6.9, 1, 0, 0.76, 0.05, 18, 12, 1, 27, NULL, 0, 1),
+	(11368591, 158241, 2816, 90, 5.9, 0, 0, 0.02, 0.00, 52, 47, 0, 63, NULL, 1, 2),
+	(11368707, 991011, 2829, 90, 7.8, 1, 0, 0.58, 0.21, 45, 40, 2, 61, NULL, 3, 1),
+	(11368707, 868812, 2829, 84, 7.6, 1, 1, 0.49, 0.30, 33, 27, 2, 55, NULL, 0, 1),
+	(11368707, 142622, 2829, 90, 7.2, 0, 0, 0.03, 0.00, 70, 66, 0, 80, NULL, 4, 1),
+	(11368707, 605672, 2825, 90, 6.1, 0, 0, 0.27, 0.02, 12, 8, 0, 19, NULL, 0, 1),
+	(11368707, 783374, 2825, 90, 6.4, 0, 0, 0.30, 0.08, 22, 17, 1, 36, NULL, 1, 2),
+	(11368707, 797291, 2825, 90, 7.0, 0, 0, NULL, NULL, 35, 25, 0, 41, 5, 0, 0);
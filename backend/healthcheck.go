@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/plinphon/StatsBanger/backend/config"
+)
+
+// runHealthcheck implements the "healthcheck" subcommand: it asks the
+// server configured by cfg whether it is ready and fails unless it is.
+// Container images use it instead of shipping an HTTP client, and it
+// finds the server on whatever port the configuration gives it.
+func runHealthcheck(cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("usage: statsbanger [flags] healthcheck")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+healthcheckAddr(cfg)+"/readyz", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("healthcheck: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("healthcheck: %s", resp.Status)
+	}
+	return nil
+}
+
+// healthcheckAddr returns the address at which the server listening on
+// cfg.Addr can be reached from the same host.
+func healthcheckAddr(cfg *config.Config) string {
+	host := cfg.Host
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(cfg.Port))
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/database"
+	"github.com/plinphon/StatsBanger/backend/importer"
+)
+
+var importUsage = `usage: statsbanger [flags] import -table <table> [-format csv|json] [-upsert] [-dry-run] <file>...
+
+Loads CSV or JSON stat dumps whose columns are the stat field names of the
+table. Each file is imported in one transaction: if any row is rejected,
+nothing from that file is written. Use "-" to read from standard input.
+
+tables: ` + strings.Join(importer.TableNames(), ", ") + `
+
+flags:`
+
+// runImport implements the "import" subcommand.
+func runImport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), importUsage)
+		fs.PrintDefaults()
+	}
+	tableName := fs.String("table", "", "table to import into")
+	format := fs.String("format", "", "file format, csv or json (default: from the file extension)")
+	upsert := fs.Bool("upsert", false, "overwrite rows whose keys already exist")
+	dryRun := fs.Bool("dry-run", false, "validate every row but write nothing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	table, ok := importer.LookupTable(*tableName)
+	if !ok {
+		return fmt.Errorf("unknown table %q (want one of %s)", *tableName, strings.Join(importer.TableNames(), ", "))
+	}
+	if fs.NArg() == 0 {
+		return errors.New("no files to import")
+	}
+	if cfg.DBReadOnly {
+		return errors.New("cannot import into a database opened read-only")
+	}
+
+	db, err := database.Open(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	opts := importer.Options{DryRun: *dryRun, Upsert: *upsert}
+	failed := 0
+	for _, path := range fs.Args() {
+		report, err := importFile(db, table, path, importer.Format(*format), opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", path, err)
+			failed++
+			continue
+		}
+
+		for _, rowErr := range report.Errors {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, rowErr)
+		}
+
+		outcome := "written"
+		switch {
+		case len(report.Errors) > 0:
+			outcome = "nothing written"
+			failed++
+		case *dryRun:
+			outcome = "dry run, nothing written"
+		}
+		fmt.Printf("%s: %d rows, %d valid, %d rejected into %s (%s)\n",
+			path, report.Rows, report.Valid, len(report.Errors), report.Table, outcome)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed to import", failed, fs.NArg())
+	}
+	return nil
+}
+
+func importFile(db *gorm.DB, table importer.Table, path string, format importer.Format, opts importer.Options) (*importer.Report, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	if format == "" {
+		if path == "-" {
+			return nil, errors.New("pass -format when reading from standard input")
+		}
+		var err error
+		if format, err = importer.FormatFromPath(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return importer.Import(db, table, format, r, opts)
+}
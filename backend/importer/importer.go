@@ -0,0 +1,218 @@
+// Package importer loads stat dumps into the StatsBanger database. Files are
+// CSV with a header row, a JSON array of objects, or newline-delimited JSON
+// objects; their column names are the table keys plus the fields of its
+// models.StatRegistry. Rows are streamed, so files of any size use
+// constant memory.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/database"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// Table describes a stat table that can be imported into.
+type Table struct {
+	Name  string
+	Keys  []string
+	Stats *models.StatRegistry
+}
+
+// Tables lists every importable table by its API name.
+var Tables = map[string]Table{
+	"player-match-stat":  table(models.PlayerMatchStats),
+	"player-season-stat": table(models.PlayerSeasonStats),
+	"team-match-stat":    table(models.TeamMatchStats),
+	"team-season-stat":   table(models.TeamSeasonStats),
+}
+
+func table(registry *models.StatRegistry) Table {
+	return Table{Name: registry.Table, Keys: registry.Keys, Stats: registry}
+}
+
+// LookupTable finds a table by its API name (player-match-stat) or its
+// database name (player_match_stat).
+func LookupTable(name string) (Table, bool) {
+	if t, ok := Tables[name]; ok {
+		return t, true
+	}
+	for _, t := range Tables {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Table{}, false
+}
+
+// TableNames returns the API names of every importable table, sorted.
+func TableNames() []string {
+	names := make([]string, 0, len(Tables))
+	for name := range Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// aliases maps the JSON names the API uses for key columns to the columns
+// themselves, so files saved from the API import unchanged.
+var aliases = map[string]string{
+	"matchId":            "match_id",
+	"playerId":           "player_id",
+	"teamId":             "team_id",
+	"uniqueTournamentId": "unique_tournament_id",
+	"seasonId":           "season_id",
+}
+
+// column maps a file column name to a column of t.
+func (t Table) column(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	if !t.Stats.Has(name) && !t.isKey(name) {
+		return "", fmt.Errorf("%w: %s", models.ErrInvalidStatField, name)
+	}
+	return name, nil
+}
+
+func (t Table) isKey(column string) bool {
+	for _, key := range t.Keys {
+		if key == column {
+			return true
+		}
+	}
+	return false
+}
+
+// Options control how rows are written.
+type Options struct {
+	// DryRun validates and writes every row inside a transaction that is
+	// always rolled back, so duplicate keys are caught without changing
+	// the database.
+	DryRun bool
+
+	// Upsert overwrites the columns carried by rows whose keys already
+	// exist instead of reporting them as duplicates.
+	Upsert bool
+}
+
+// RowError reports why one row of a file was rejected. Row counts data rows
+// from 1; the CSV header is not counted.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+// Report summarizes an import.
+type Report struct {
+	Table  string
+	Rows   int
+	Valid  int
+	Errors []RowError
+
+	// Committed is true when the rows were written. An import is all or
+	// nothing: any row error, or a dry run, rolls every row back.
+	Committed bool
+}
+
+// errRollback aborts the import transaction without failing the import.
+var errRollback = errors.New("rollback")
+
+// Import streams rows of the given format from r into table t. Rows that
+// fail validation or cannot be written are collected in the report; the
+// returned error is reserved for problems with the file as a whole, such as
+// an unknown CSV column or malformed JSON, and for database failures.
+func Import(db *gorm.DB, t Table, format Format, r io.Reader, opts Options) (*Report, error) {
+	records, err := newReader(format, t, r)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Table: t.Name}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for {
+			rec, err := records.next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			report.Rows++
+
+			if rec.err == nil {
+				rec.err = write(tx, t, rec.row, opts.Upsert)
+			}
+			if rec.err != nil {
+				report.Errors = append(report.Errors, RowError{Row: report.Rows, Err: rec.err})
+				continue
+			}
+			report.Valid++
+		}
+
+		if opts.DryRun || len(report.Errors) > 0 {
+			return errRollback
+		}
+		return nil
+	})
+	if errors.Is(err, errRollback) {
+		return report, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report.Committed = true
+	return report, nil
+}
+
+// write stores one row inside a savepoint, so a failed row can be undone
+// without aborting the rest of the transaction. The savepoint is released
+// either way, so they do not pile up over a large file.
+func write(tx *gorm.DB, t Table, row database.Row, upsert bool) error {
+	if err := tx.SavePoint("import_row").Error; err != nil {
+		return err
+	}
+
+	var err error
+	if upsert {
+		err = database.UpsertRow(tx, t.Name, t.Keys, row)
+	} else {
+		err = database.InsertRow(tx, t.Name, row)
+	}
+	if err == nil {
+		return releaseSavePoint(tx)
+	}
+
+	if rbErr := tx.RollbackTo("import_row").Error; rbErr != nil {
+		return rbErr
+	}
+	// Rolling back to a savepoint keeps it open.
+	if relErr := releaseSavePoint(tx); relErr != nil {
+		return relErr
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return errors.New("row already exists (use upsert to overwrite it)")
+	}
+	return err
+}
+
+func releaseSavePoint(tx *gorm.DB) error {
+	return tx.Exec("RELEASE SAVEPOINT import_row").Error
+}
@@ -0,0 +1,148 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/fixture"
+)
+
+func openFixture(t *testing.T) *gorm.DB {
+	t.Helper()
+	return fixture.Open(t)
+}
+
+func count(t *testing.T, db *gorm.DB, table string) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Table(table).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestImportCSV(t *testing.T) {
+	db := openFixture(t)
+	before := count(t, db, "player_match_stat")
+
+	file := "match_id,player_id,team_id,goals,rating\n" +
+		"11368620,991011,2829,1,8.1\n" +
+		"11368620,868812,2829,,7.4\n"
+	report, err := Import(db, Tables["player-match-stat"], CSV, strings.NewReader(file), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Committed || report.Rows != 2 || report.Valid != 2 || len(report.Errors) != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if got := count(t, db, "player_match_stat"); got != before+2 {
+		t.Errorf("got %d rows, want %d", got, before+2)
+	}
+
+	var goals *float64
+	db.Table("player_match_stat").Select("goals").
+		Where("match_id = ? AND player_id = ?", fixture.RealMadridBetisID, fixture.ViniciusID).Scan(&goals)
+	if goals != nil {
+		t.Errorf("empty cell stored as %v, want NULL", *goals)
+	}
+}
+
+func TestImportRejectsBadRows(t *testing.T) {
+	db := openFixture(t)
+	before := count(t, db, "player_match_stat")
+
+	file := "match_id,player_id,team_id,goals\n" +
+		"11368620,991011,2829,1\n" + // fine
+		"11368591,103417,2816,1\n" + // already in the fixture
+		"11368620,0,2829,1\n" + // bad key
+		"11368620,142622,2829,lots\n" + // not a number
+		"11368620,142622\n" // short row
+	report, err := Import(db, Tables["player-match-stat"], CSV, strings.NewReader(file), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Committed || report.Rows != 5 || report.Valid != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	var rows []int
+	for _, e := range report.Errors {
+		rows = append(rows, e.Row)
+	}
+	if len(rows) != 4 || rows[0] != 2 || rows[3] != 5 {
+		t.Errorf("errors on rows %v, want [2 3 4 5]", rows)
+	}
+	if got := count(t, db, "player_match_stat"); got != before {
+		t.Errorf("rejected import wrote rows: got %d, want %d", got, before)
+	}
+}
+
+func TestImportCSVHeader(t *testing.T) {
+	db := openFixture(t)
+
+	_, err := Import(db, Tables["team-season-stat"], CSV, strings.NewReader("team_id,season_id,goalz\n"), Options{})
+	if err == nil || !strings.Contains(err.Error(), "goalz") || !strings.Contains(err.Error(), "unique_tournament_id") {
+		t.Errorf("want unknown and missing column errors, got %v", err)
+	}
+}
+
+func TestImportJSONUpsert(t *testing.T) {
+	db := openFixture(t)
+
+	file := `[
+		{"teamId": 2829, "uniqueTournamentId": 8, "seasonId": 52376, "goals_scored": 90},
+		{"team_id": 2816, "unique_tournament_id": 8, "season_id": 52376, "goals_scored": 50}
+	]`
+	table := Tables["team-season-stat"]
+
+	report, err := Import(db, table, JSON, strings.NewReader(file), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Committed || len(report.Errors) != 2 {
+		t.Fatalf("insert over existing rows: %+v", report)
+	}
+
+	report, err = Import(db, table, JSON, strings.NewReader(file), Options{Upsert: true, DryRun: true})
+	if err != nil || report.Committed || report.Valid != 2 {
+		t.Fatalf("dry run: %+v, %v", report, err)
+	}
+
+	var goals float64
+	scored := func() float64 {
+		db.Table("team_stat").Select("goals_scored").Where("team_id = ?", fixture.RealMadridID).Scan(&goals)
+		return goals
+	}
+	if scored() != 87 {
+		t.Fatalf("dry run changed the database: goals_scored = %v", goals)
+	}
+
+	report, err = Import(db, table, JSON, strings.NewReader(file), Options{Upsert: true})
+	if err != nil || !report.Committed {
+		t.Fatalf("upsert: %+v, %v", report, err)
+	}
+	if scored() != 90 {
+		t.Errorf("goals_scored = %v, want 90", goals)
+	}
+}
+
+func TestImportNDJSON(t *testing.T) {
+	db := openFixture(t)
+
+	file := `{"match_id": 11368620, "team_id": 2829, "ball_possession": 58}
+{"match_id": 11368620, "team_id": 2816, "ball_possession": "42", "corners": 3}
+["not", "an", "object"]
+`
+	report, err := Import(db, Tables["team-match-stat"], JSON, strings.NewReader(file), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Rows != 3 || report.Valid != 1 || len(report.Errors) != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if !strings.Contains(report.Errors[0].Error(), "row 2") || !strings.Contains(report.Errors[0].Error(), "corners") {
+		t.Errorf("unexpected error: %v", report.Errors[0])
+	}
+}
@@ -0,0 +1,255 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/plinphon/StatsBanger/backend/database"
+)
+
+// Format is the encoding of an import file.
+type Format string
+
+const (
+	CSV  Format = "csv"
+	JSON Format = "json"
+)
+
+// FormatFromPath guesses the format of a file from its extension.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return CSV, nil
+	case ".json", ".ndjson", ".jsonl":
+		return JSON, nil
+	}
+	return "", fmt.Errorf("cannot tell the format of %s; pass -format csv or -format json", path)
+}
+
+// record is one decoded row, or the reason it could not be decoded.
+type record struct {
+	row database.Row
+	err error
+}
+
+type recordReader interface {
+	// next returns the following record, or io.EOF after the last one.
+	next() (record, error)
+}
+
+func newReader(format Format, t Table, r io.Reader) (recordReader, error) {
+	switch format {
+	case CSV:
+		return newCSVReader(t, r)
+	case JSON:
+		return newJSONReader(t, r)
+	}
+	return nil, fmt.Errorf("unknown format %q (want csv or json)", format)
+}
+
+type csvReader struct {
+	table   Table
+	r       *csv.Reader
+	columns []string
+}
+
+// newCSVReader reads the header row and maps it onto t. Unknown or missing
+// key columns make the whole file unusable, so they are reported up front.
+func newCSVReader(t Table, r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty CSV file")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+
+	var errs []error
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheet exports often start with a byte order mark.
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		column, err := t.column(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if seen[column] {
+			errs = append(errs, fmt.Errorf("duplicate column %s", column))
+		}
+		seen[column] = true
+		columns[i] = column
+	}
+	for _, key := range t.Keys {
+		if !seen[key] {
+			errs = append(errs, fmt.Errorf("missing key column %s", key))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("CSV header: %w", errors.Join(errs...))
+	}
+
+	return &csvReader{table: t, r: cr, columns: columns}, nil
+}
+
+func (c *csvReader) next() (record, error) {
+	fields, err := c.r.Read()
+	if err == io.EOF {
+		return record{}, io.EOF
+	}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+			return record{err: fmt.Errorf("got %d fields, want %d", len(fields), len(c.columns))}, nil
+		}
+		return record{}, err
+	}
+
+	values := make(map[string]interface{}, len(fields))
+	for i, field := range fields {
+		values[c.columns[i]] = field
+	}
+	return c.table.record(values), nil
+}
+
+type jsonReader struct {
+	table Table
+	dec   *json.Decoder
+	array bool
+}
+
+// newJSONReader accepts either one JSON array of objects or a stream of
+// objects, one after another, as written by NDJSON tools.
+func newJSONReader(t Table, r io.Reader) (*jsonReader, error) {
+	br := bufio.NewReader(r)
+	var first byte
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			return nil, errors.New("empty JSON file")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			first = b
+			br.UnreadByte()
+			break
+		}
+	}
+
+	dec := json.NewDecoder(br)
+	dec.UseNumber()
+	if first == '[' {
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	}
+	return &jsonReader{table: t, dec: dec, array: first == '['}, nil
+}
+
+func (j *jsonReader) next() (record, error) {
+	if j.array && !j.dec.More() {
+		if _, err := j.dec.Token(); err != nil {
+			return record{}, fmt.Errorf("parse JSON: %w", err)
+		}
+		return record{}, io.EOF
+	}
+
+	var raw map[string]interface{}
+	err := j.dec.Decode(&raw)
+	if err == io.EOF && !j.array {
+		return record{}, io.EOF
+	}
+	if err != nil {
+		// A value of the wrong type has been consumed whole, so the stream
+		// can carry on; a syntax error cannot be recovered from.
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return record{err: fmt.Errorf("expected an object, got %s", typeErr.Value)}, nil
+		}
+		return record{}, fmt.Errorf("parse JSON: %w", err)
+	}
+
+	values := make(map[string]interface{}, len(raw))
+	for name, value := range raw {
+		column, err := j.table.column(name)
+		if err != nil {
+			return record{err: err}, nil
+		}
+		if _, dup := values[column]; dup {
+			return record{err: fmt.Errorf("duplicate column %s", column)}, nil
+		}
+		values[column] = value
+	}
+	for _, key := range j.table.Keys {
+		if _, ok := values[key]; !ok {
+			return record{err: fmt.Errorf("missing key column %s", key)}, nil
+		}
+	}
+	return j.table.record(values), nil
+}
+
+// record converts the raw values of one row, already mapped to columns,
+// into a database row.
+func (t Table) record(values map[string]interface{}) record {
+	row := make(database.Row, len(values))
+	var errs []error
+	for column, value := range values {
+		n, err := number(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", column, err))
+			continue
+		}
+
+		if t.isKey(column) {
+			if n == nil || *n <= 0 || *n != math.Trunc(*n) {
+				errs = append(errs, fmt.Errorf("%s: must be a positive integer", column))
+				continue
+			}
+			row[column] = int(*n)
+			continue
+		}
+		row[column] = n
+	}
+	return record{row: row, err: errors.Join(errs...)}
+}
+
+// number parses a CSV cell or JSON value. Empty cells and null become a
+// NULL column.
+func number(value interface{}) (*float64, error) {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		s = v.String()
+	case string:
+		s = strings.TrimSpace(v)
+		if s == "" || strings.EqualFold(s, "null") {
+			return nil, nil
+		}
+	default:
+		return nil, fmt.Errorf("expected a number, got %v", v)
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return nil, fmt.Errorf("expected a number, got %q", s)
+	}
+	return &n, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/database"
+	"github.com/plinphon/StatsBanger/backend/ingest"
+)
+
+const ingestUsage = `usage: statsbanger [flags] ingest <dir>...
+
+Loads matches saved from Sofascore. Each dir is either a match directory
+holding event.json and optionally lineups.json and statistics.json, or a
+directory of such match directories. Re-ingesting a match is safe.`
+
+// runIngest implements the "ingest" subcommand.
+func runIngest(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(ingestUsage)
+	}
+	if cfg.DBReadOnly {
+		return errors.New("cannot ingest into a database opened read-only")
+	}
+
+	var dirs []string
+	for _, arg := range args {
+		found, err := ingest.FindMatchDirs(arg)
+		if err != nil {
+			return err
+		}
+		dirs = append(dirs, found...)
+	}
+
+	db, err := database.Open(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	failed := 0
+	for _, dir := range dirs {
+		d, err := ingest.IngestDir(db, dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", dir, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s: match %d, %d team stat rows, %d player stat rows\n",
+			dir, d.Match.Id, len(d.TeamStats), len(d.PlayerStats))
+		if len(d.Skipped) > 0 {
+			fmt.Printf("%s: skipped unknown stats: %s\n", dir, strings.Join(d.Skipped, ", "))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d matches failed to ingest", failed, len(dirs))
+	}
+	return nil
+}
@@ -0,0 +1,403 @@
+// Package ingest loads matches saved from Sofascore into the StatsBanger
+// database. Each match lives in its own directory holding the event,
+// lineups and statistics responses:
+//
+//	11368620/event.json
+//	11368620/lineups.json
+//	11368620/statistics.json
+//
+// Only event.json is required. Everything is written with upserts in one
+// transaction per match, so ingesting the same files again is a no-op and
+// ingesting a newer download of a match refreshes it.
+package ingest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/api/matches"
+	playermatch "github.com/plinphon/StatsBanger/backend/api/player/match"
+	teammatch "github.com/plinphon/StatsBanger/backend/api/team/match"
+	"github.com/plinphon/StatsBanger/backend/database"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// Names of the files read from a match directory.
+const (
+	EventFile      = "event.json"
+	LineupsFile    = "lineups.json"
+	StatisticsFile = "statistics.json"
+)
+
+// Files holds the responses saved for one match. Lineups and Statistics are
+// nil when their file is missing.
+type Files struct {
+	Event      EventResponse
+	Lineups    *LineupsResponse
+	Statistics *StatisticsResponse
+}
+
+// ReadDir reads the saved responses of one match from dir.
+func ReadDir(dir string) (*Files, error) {
+	var f Files
+	if err := readJSON(filepath.Join(dir, EventFile), &f.Event); err != nil {
+		return nil, err
+	}
+
+	var lineups LineupsResponse
+	if err := readJSON(filepath.Join(dir, LineupsFile), &lineups); err == nil {
+		f.Lineups = &lineups
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	var statistics StatisticsResponse
+	if err := readJSON(filepath.Join(dir, StatisticsFile), &statistics); err == nil {
+		f.Statistics = &statistics
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// FindMatchDirs returns root if it is a match directory, or else every
+// immediate subdirectory of root that is one, sorted by name.
+func FindMatchDirs(root string) ([]string, error) {
+	if _, err := os.Stat(filepath.Join(root, EventFile)); err == nil {
+		return []string{root}, nil
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, entry.Name())
+		if _, err := os.Stat(filepath.Join(dir, EventFile)); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no %s found in %s or its subdirectories", EventFile, root)
+	}
+	return dirs, nil
+}
+
+// MatchData is one match mapped onto StatsBanger rows.
+type MatchData struct {
+	Event       Event
+	Teams       []models.Team
+	Players     []models.Player
+	Match       models.Match
+	TeamStats   []models.TeamMatchStat
+	PlayerStats []models.PlayerMatchStat
+
+	// Skipped lists the stats found in the payloads that have no column,
+	// so new Sofascore stats are noticed rather than silently dropped.
+	Skipped []string
+}
+
+// Map converts saved responses into rows.
+func Map(f *Files) (*MatchData, error) {
+	e := f.Event.Event
+	if e.ID <= 0 {
+		return nil, errors.New("event has no id")
+	}
+	if e.Tournament.UniqueTournament.ID <= 0 || e.Season.ID <= 0 {
+		return nil, fmt.Errorf("event %d: missing unique tournament or season", e.ID)
+	}
+	if e.HomeTeam.ID <= 0 || e.AwayTeam.ID <= 0 || e.HomeTeam.ID == e.AwayTeam.ID {
+		return nil, fmt.Errorf("event %d: invalid home and away teams", e.ID)
+	}
+
+	d := &MatchData{
+		Event: e,
+		Teams: []models.Team{
+			{TeamId: e.HomeTeam.ID, TeamName: e.HomeTeam.Name},
+			{TeamId: e.AwayTeam.ID, TeamName: e.AwayTeam.Name},
+		},
+	}
+	if e.Venue != nil {
+		d.Teams[0].HomeStadium = e.Venue.Stadium.Name
+	}
+
+	start := e.Time.CurrentPeriodStartTimestamp
+	if start == 0 {
+		start = e.StartTimestamp
+	}
+	d.Match = models.Match{
+		Id:                          e.ID,
+		UniqueTournamentId:          e.Tournament.UniqueTournament.ID,
+		SeasonId:                    e.Season.ID,
+		Matchday:                    e.RoundInfo.Round,
+		HomeTeamId:                  e.HomeTeam.ID,
+		AwayTeamId:                  e.AwayTeam.ID,
+		InjuryTime1:                 e.Time.InjuryTime1,
+		InjuryTime2:                 e.Time.InjuryTime2,
+		CurrentPeriodStartTimestamp: time.Unix(start, 0).UTC(),
+	}
+	if e.Finished() && e.HomeScore.Current != nil && e.AwayScore.Current != nil {
+		homeWin := 0
+		if *e.HomeScore.Current > *e.AwayScore.Current {
+			homeWin = 1
+		}
+		d.Match.HomeScore = e.HomeScore.Current
+		d.Match.AwayScore = e.AwayScore.Current
+		d.Match.HomeWin = &homeWin
+	}
+
+	skipped := make(map[string]bool)
+	if f.Statistics != nil {
+		d.TeamStats = teamStats(e, f.Statistics, skipped)
+	}
+	if f.Lineups != nil {
+		d.Players, d.PlayerStats = playerStats(e, f.Lineups, skipped)
+	}
+	for name := range skipped {
+		d.Skipped = append(d.Skipped, name)
+	}
+	sort.Strings(d.Skipped)
+
+	return d, nil
+}
+
+// teamStats maps the whole-match period of a statistics response. Sofascore
+// display names are the team_match_stat columns in snake case.
+func teamStats(e Event, r *StatisticsResponse, skipped map[string]bool) []models.TeamMatchStat {
+	home := models.TeamMatchStat{MatchId: e.ID, TeamId: e.HomeTeam.ID, Stats: map[string]*float64{}}
+	away := models.TeamMatchStat{MatchId: e.ID, TeamId: e.AwayTeam.ID, Stats: map[string]*float64{}}
+
+	for _, period := range r.Statistics {
+		if period.Period != "ALL" {
+			continue
+		}
+		for _, group := range period.Groups {
+			for _, item := range group.StatisticsItems {
+				column := snakeCase(item.Name)
+				if !models.TeamMatchStats.Has(column) {
+					skipped["team: "+item.Name] = true
+					continue
+				}
+				home.Stats[column] = item.HomeValue
+				away.Stats[column] = item.AwayValue
+			}
+		}
+	}
+
+	if len(home.Stats) == 0 {
+		return nil
+	}
+	return []models.TeamMatchStat{home, away}
+}
+
+// playerStats maps a lineups response. Sofascore stat keys are the
+// player_match_stat columns in camel case. Players who did not get on the
+// pitch have no statistics and get no stat row.
+func playerStats(e Event, r *LineupsResponse, skipped map[string]bool) ([]models.Player, []models.PlayerMatchStat) {
+	var players []models.Player
+	var stats []models.PlayerMatchStat
+
+	sides := []struct {
+		lineup Lineup
+		teamID int
+	}{{r.Home, e.HomeTeam.ID}, {r.Away, e.AwayTeam.ID}}
+
+	for _, side := range sides {
+		for _, lp := range side.lineup.Players {
+			if lp.Player.ID <= 0 {
+				continue
+			}
+
+			player := models.Player{
+				PlayerId:      lp.Player.ID,
+				PlayerName:    lp.Player.Name,
+				Position:      lp.Player.Position,
+				Height:        lp.Player.Height,
+				PreferredFoot: lp.Player.PreferredFoot,
+				Nationality:   lp.Player.Country.Name,
+			}
+			if player.Position == "" {
+				player.Position = lp.Position
+			}
+			if lp.Player.DateOfBirthTimestamp != nil {
+				player.Birthday = time.Unix(*lp.Player.DateOfBirthTimestamp, 0).UTC()
+			}
+			players = append(players, player)
+
+			values := make(map[string]*float64)
+			for key, raw := range lp.Statistics {
+				var value float64
+				if err := json.Unmarshal(raw, &value); err != nil {
+					// Nested objects such as ratingVersions are not stats.
+					continue
+				}
+				column := snakeCase(key)
+				if !models.PlayerMatchStats.Has(column) {
+					skipped["player: "+key] = true
+					continue
+				}
+				values[column] = &value
+			}
+			if len(values) == 0 {
+				continue
+			}
+
+			teamID := lp.TeamID
+			if teamID == 0 {
+				teamID = side.teamID
+			}
+			stats = append(stats, models.PlayerMatchStat{
+				MatchId:  e.ID,
+				PlayerId: lp.Player.ID,
+				TeamId:   teamID,
+				Stats:    values,
+			})
+		}
+	}
+
+	return players, stats
+}
+
+// snakeCase turns both Sofascore stat keys ("onTargetScoringAttempt") and
+// display names ("Throw-ins") into column names.
+func snakeCase(s string) string {
+	var b strings.Builder
+	underscore := false
+	for i, r := range s {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 {
+				underscore = true
+			}
+			r = unicode.ToLower(r)
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			underscore = b.Len() > 0
+			continue
+		}
+		if underscore {
+			b.WriteByte('_')
+			underscore = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Save writes d in a single transaction. Every write is an upsert, so
+// saving the same data twice leaves the database unchanged.
+func Save(db *gorm.DB, d *MatchData) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		ut := d.Event.Tournament.UniqueTournament
+		if err := upsert(tx, "unique_tournament_info", "unique_tournament_id", ut.ID, database.Row{
+			"tournament_name": ut.Name,
+			"country":         ut.Category.Name,
+		}); err != nil {
+			return err
+		}
+		if err := upsert(tx, "season_info", "season_id", d.Event.Season.ID, database.Row{
+			"season_name": d.Event.Season.Name,
+			"year":        d.Event.Season.Year,
+		}); err != nil {
+			return err
+		}
+
+		for _, t := range d.Teams {
+			if err := upsert(tx, "team_info", "team_id", t.TeamId, database.Row{
+				"team_name":    t.TeamName,
+				"home_stadium": t.HomeStadium,
+			}); err != nil {
+				return err
+			}
+		}
+
+		for _, p := range d.Players {
+			row := database.Row{
+				"player_name":    p.PlayerName,
+				"position":       p.Position,
+				"preferred_foot": p.PreferredFoot,
+				"nationality":    p.Nationality,
+			}
+			if p.Height > 0 {
+				row["height"] = p.Height
+			}
+			if !p.Birthday.IsZero() {
+				row["birthday_timestamp"] = p.Birthday.Unix()
+			}
+			if err := upsert(tx, "player_info", "player_id", p.PlayerId, row); err != nil {
+				return err
+			}
+		}
+
+		if err := matches.NewMatchRepository(tx).Upsert(tx.Statement.Context, d.Match); err != nil {
+			return fmt.Errorf("save match %d: %w", d.Match.Id, err)
+		}
+		if len(d.TeamStats) > 0 {
+			if err := teammatch.NewTeamMatchStatRepository(tx).Upsert(tx.Statement.Context, d.TeamStats); err != nil {
+				return fmt.Errorf("save team stats of match %d: %w", d.Match.Id, err)
+			}
+		}
+		if len(d.PlayerStats) > 0 {
+			if err := playermatch.NewPlayerMatchStatRepository(tx).Upsert(tx.Statement.Context, d.PlayerStats); err != nil {
+				return fmt.Errorf("save player stats of match %d: %w", d.Match.Id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// upsert writes the non-empty values of row under the given key. Values the
+// payload does not carry never overwrite what is already stored.
+func upsert(tx *gorm.DB, table, key string, id int, row database.Row) error {
+	for column, value := range row {
+		if value == "" {
+			delete(row, column)
+		}
+	}
+	row[key] = id
+	if err := database.UpsertRow(tx, table, []string{key}, row); err != nil {
+		return fmt.Errorf("save %s %d: %w", table, id, err)
+	}
+	return nil
+}
+
+// IngestDir reads, maps and saves the match stored in dir.
+func IngestDir(db *gorm.DB, dir string) (*MatchData, error) {
+	files, err := ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	d, err := Map(files)
+	if err != nil {
+		return nil, err
+	}
+	if err := Save(db, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
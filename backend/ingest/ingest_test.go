@@ -0,0 +1,117 @@
+package ingest
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/fixture"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+var matchDir = filepath.Join("testdata", "11368620")
+
+func count(t *testing.T, db *gorm.DB, table string) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Table(table).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestMap(t *testing.T) {
+	files, err := ReadDir(matchDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := Map(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := d.Match
+	if m.Id != fixture.RealMadridBetisID || m.Matchday != 38 || m.HomeTeamId != fixture.RealMadridID || m.AwayTeamId != fixture.BetisID {
+		t.Errorf("unexpected match: %+v", m)
+	}
+	if m.HomeScore == nil || *m.HomeScore != 0 || m.HomeWin == nil || *m.HomeWin != 0 || *m.InjuryTime2 != 4 {
+		t.Errorf("unexpected result: %+v", m)
+	}
+	if m.CurrentPeriodStartTimestamp.Unix() != 1716667530 {
+		t.Errorf("current period start = %v", m.CurrentPeriodStartTimestamp)
+	}
+
+	if len(d.TeamStats) != 2 {
+		t.Fatalf("got %d team stat rows, want 2", len(d.TeamStats))
+	}
+	home := d.TeamStats[0].Stats
+	if *home["ball_possession"] != 68 || *home["throw_ins"] != 17 || *home["touches_in_penalty_area"] != 41 {
+		t.Errorf("home stats not taken from the ALL period: possession=%v throw_ins=%v", *home["ball_possession"], *home["throw_ins"])
+	}
+
+	// Unused substitutes are players but have no stat row.
+	if len(d.Players) != 9 || len(d.PlayerStats) != 7 {
+		t.Errorf("got %d players and %d stat rows, want 9 and 7", len(d.Players), len(d.PlayerStats))
+	}
+	bellingham := d.PlayerStats[0]
+	if bellingham.PlayerId != fixture.BellinghamID || *bellingham.Stats["on_target_scoring_attempt"] != 2 || *bellingham.Stats["possession_lost_ctrl"] != 12 {
+		t.Errorf("unexpected Bellingham stats: %+v", bellingham)
+	}
+
+	want := []string{"player: totalBallCarriesDistance", "team: Counter attacks"}
+	if !reflect.DeepEqual(d.Skipped, want) {
+		t.Errorf("skipped = %v, want %v", d.Skipped, want)
+	}
+}
+
+func TestIngestDirIsIdempotent(t *testing.T) {
+	db := fixture.Open(t)
+
+	tables := []string{"team_info", "player_info", "match_info", "team_match_stat", "player_match_stat"}
+	before := make(map[string]int64)
+	for _, table := range tables {
+		before[table] = count(t, db, table)
+	}
+
+	if _, err := IngestDir(db, matchDir); err != nil {
+		t.Fatal(err)
+	}
+	after := make(map[string]int64)
+	for _, table := range tables {
+		after[table] = count(t, db, table)
+	}
+
+	// Kroos, Lunin and Rui Silva are new; the match row already existed.
+	wantAdded := map[string]int64{"team_info": 0, "player_info": 3, "match_info": 0, "team_match_stat": 2, "player_match_stat": 7}
+	for table, added := range wantAdded {
+		if after[table]-before[table] != added {
+			t.Errorf("%s: added %d rows, want %d", table, after[table]-before[table], added)
+		}
+	}
+
+	if _, err := IngestDir(db, matchDir); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range tables {
+		if n := count(t, db, table); n != after[table] {
+			t.Errorf("%s: second run changed row count from %d to %d", table, after[table], n)
+		}
+	}
+
+	var match models.Match
+	if err := db.Preload("HomeTeam").First(&match, fixture.RealMadridBetisID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if match.HomeScore == nil || *match.HomeScore != 0 || match.HomeTeam.HomeStadium != "Estadio Santiago Bernabéu" {
+		t.Errorf("unexpected stored match: %+v", match)
+	}
+
+	var rating float64
+	db.Table("player_match_stat").Select("rating").
+		Where("match_id = ? AND player_id = ?", fixture.RealMadridBetisID, fixture.BellinghamID).Scan(&rating)
+	if rating != 7.4 {
+		t.Errorf("Bellingham rating = %v, want 7.4", rating)
+	}
+}
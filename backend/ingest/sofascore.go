@@ -0,0 +1,131 @@
+package ingest
+
+import "encoding/json"
+
+// The types below mirror the parts of the Sofascore event, lineups and
+// statistics responses that StatsBanger stores. Everything else in the
+// payloads is ignored.
+
+// EventResponse is the body of /api/v1/event/{id}.
+type EventResponse struct {
+	Event Event `json:"event"`
+}
+
+type Event struct {
+	ID         int        `json:"id"`
+	Tournament Tournament `json:"tournament"`
+	Season     Season     `json:"season"`
+	RoundInfo  struct {
+		Round int `json:"round"`
+	} `json:"roundInfo"`
+	Status struct {
+		Code int    `json:"code"`
+		Type string `json:"type"`
+	} `json:"status"`
+	HomeTeam       EventTeam `json:"homeTeam"`
+	AwayTeam       EventTeam `json:"awayTeam"`
+	HomeScore      Score     `json:"homeScore"`
+	AwayScore      Score     `json:"awayScore"`
+	Time           EventTime `json:"time"`
+	Venue          *Venue    `json:"venue"`
+	StartTimestamp int64     `json:"startTimestamp"`
+}
+
+// Finished reports whether the event's score is final.
+func (e Event) Finished() bool {
+	return e.Status.Type == "finished"
+}
+
+type Tournament struct {
+	Name             string `json:"name"`
+	UniqueTournament struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name"`
+		Category struct {
+			Name string `json:"name"`
+		} `json:"category"`
+	} `json:"uniqueTournament"`
+}
+
+type Season struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Year string `json:"year"`
+}
+
+type EventTeam struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Score struct {
+	Current *int `json:"current"`
+}
+
+type EventTime struct {
+	InjuryTime1                 *int  `json:"injuryTime1"`
+	InjuryTime2                 *int  `json:"injuryTime2"`
+	CurrentPeriodStartTimestamp int64 `json:"currentPeriodStartTimestamp"`
+}
+
+type Venue struct {
+	Stadium struct {
+		Name string `json:"name"`
+	} `json:"stadium"`
+}
+
+// LineupsResponse is the body of /api/v1/event/{id}/lineups.
+type LineupsResponse struct {
+	Confirmed bool   `json:"confirmed"`
+	Home      Lineup `json:"home"`
+	Away      Lineup `json:"away"`
+}
+
+type Lineup struct {
+	Players []LineupPlayer `json:"players"`
+}
+
+type LineupPlayer struct {
+	Player     Player `json:"player"`
+	TeamID     int    `json:"teamId"`
+	Position   string `json:"position"`
+	Substitute bool   `json:"substitute"`
+
+	// Statistics holds numbers keyed by camelCase stat name, plus the odd
+	// nested object, so values are decoded lazily.
+	Statistics map[string]json.RawMessage `json:"statistics"`
+}
+
+type Player struct {
+	ID                   int     `json:"id"`
+	Name                 string  `json:"name"`
+	Position             string  `json:"position"`
+	Height               float64 `json:"height"`
+	PreferredFoot        string  `json:"preferredFoot"`
+	DateOfBirthTimestamp *int64  `json:"dateOfBirthTimestamp"`
+	Country              struct {
+		Name string `json:"name"`
+	} `json:"country"`
+}
+
+// StatisticsResponse is the body of /api/v1/event/{id}/statistics.
+type StatisticsResponse struct {
+	Statistics []PeriodStatistics `json:"statistics"`
+}
+
+type PeriodStatistics struct {
+	Period string           `json:"period"`
+	Groups []StatisticGroup `json:"groups"`
+}
+
+type StatisticGroup struct {
+	GroupName       string          `json:"groupName"`
+	StatisticsItems []StatisticItem `json:"statisticsItems"`
+}
+
+type StatisticItem struct {
+	Name      string   `json:"name"`
+	Key       string   `json:"key"`
+	HomeValue *float64 `json:"homeValue"`
+	AwayValue *float64 `json:"awayValue"`
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/plinphon/StatsBanger/backend/auth"
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/database"
+)
+
+const keysUsage = `usage: statsbanger [flags] keys <command>
+
+commands:
+  issue -name NAME [-scopes read,write] [-rate-limit N] [-daily-quota N]
+                     create an API key and print it; it cannot be shown again
+  revoke ID          revoke an API key; servers stop accepting it within 30s
+  list               list API keys without their secrets`
+
+// runKeys implements the "keys" subcommand.
+func runKeys(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(keysUsage)
+	}
+	if cfg.DBReadOnly && args[0] != "list" {
+		return errors.New("cannot change API keys in a database opened read-only")
+	}
+
+	db, err := database.Open(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	ctx := context.Background()
+	keys := auth.NewKeyRepository(db)
+
+	switch args[0] {
+	case "issue":
+		fs := flag.NewFlagSet("keys issue", flag.ContinueOnError)
+		name := fs.String("name", "", "who or what the key is for")
+		scopes := fs.String("scopes", auth.ScopeRead, "comma-separated scopes: read, write, admin")
+		rateLimit := fs.Int("rate-limit", 600, "requests per minute (0 = unlimited)")
+		dailyQuota := fs.Int("daily-quota", 0, "requests per day (0 = unlimited)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *name == "" {
+			return errors.New("-name is required")
+		}
+		if *rateLimit < 0 || *dailyQuota < 0 {
+			return errors.New("-rate-limit and -daily-quota must not be negative")
+		}
+		canonical, err := auth.ParseScopes(*scopes)
+		if err != nil {
+			return err
+		}
+
+		id, token, err := auth.NewToken()
+		if err != nil {
+			return err
+		}
+		key := &auth.Key{ID: id, Name: *name, Scopes: canonical, RateLimit: *rateLimit, DailyQuota: *dailyQuota}
+		if err := keys.Create(ctx, key, token); err != nil {
+			return fmt.Errorf("issue key: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "issued key %s (%s) for %s; store it now, it cannot be shown again:\n", id, canonical, *name)
+		fmt.Println(token)
+		return nil
+
+	case "revoke":
+		if len(args) != 2 {
+			return errors.New("usage: statsbanger keys revoke ID")
+		}
+		if err := keys.Revoke(ctx, args[1]); err != nil {
+			return fmt.Errorf("revoke key %s: %w", args[1], err)
+		}
+		fmt.Printf("revoked key %s\n", args[1])
+		return nil
+
+	case "list":
+		list, err := keys.List(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tSCOPES\tRATE LIMIT\tDAILY QUOTA\tCREATED AT\tSTATUS")
+		for _, k := range list {
+			status := "active"
+			if k.Revoked() {
+				status = "revoked " + k.RevokedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", k.ID, k.Name, k.Scopes,
+				limitString(k.RateLimit, "/min"), limitString(k.DailyQuota, "/day"),
+				k.CreatedAt.Format("2006-01-02 15:04:05"), status)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown keys command %q\n\n%s", args[0], keysUsage)
+	}
+}
+
+func limitString(limit int, unit string) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+	return strconv.Itoa(limit) + unit
+}
@@ -1,24 +1,183 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+	"github.com/plinphon/StatsBanger/backend/auth"
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/container"
+	"github.com/plinphon/StatsBanger/backend/database"
+	"github.com/plinphon/StatsBanger/backend/logging"
+	"github.com/plinphon/StatsBanger/backend/middleware"
 	"github.com/plinphon/StatsBanger/backend/routes"
 )
 
 func main() {
-	app := fiber.New()
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("❌ Invalid configuration: %v", err)
+	}
+
+	if err := logging.Setup(cfg, os.Stderr); err != nil {
+		log.Fatalf("❌ Invalid configuration: %v", err)
+	}
+
+	command := "serve"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		err = serve(cfg, args)
+	case "migrate":
+		err = runMigrate(cfg, args)
+	case "import":
+		err = runImport(cfg, args)
+	case "ingest":
+		err = runIngest(cfg, args)
+	case "keys":
+		err = runKeys(cfg, args)
+	case "healthcheck":
+		err = runHealthcheck(cfg, args)
+	default:
+		err = fmt.Errorf("unknown command %q (want serve, migrate, import, ingest, keys or healthcheck)", command)
+	}
+
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+const serveUsage = `usage: statsbanger [flags] serve
+
+Runs the HTTP API. serve takes no arguments: flags such as -port and -db
+are global and go before the command, as in "statsbanger -port 9000 serve".
+Run "statsbanger -h" to list them.`
+
+// serve runs the HTTP API until it fails or the process receives SIGINT or
+// SIGTERM. On a signal it stops accepting connections, waits up to
+// cfg.ShutdownTimeout for in-flight requests and closes the database.
+func serve(cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		return errors.New(serveUsage)
+	}
+	cfg.Log()
 
+	if err := checkDBFile(cfg); err != nil {
+		return err
+	}
+	c, err := container.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			slog.Error("close database", "error", err)
+		}
+	}()
+
+	if err := checkSchema(cfg, c); err != nil {
+		return err
+	}
+	if !c.DB.Migrator().HasTable(auth.Key{}.TableName()) {
+		slog.Warn("api_key table is missing: requests with an API key fail until the migrate up command runs")
+	}
+
+	app := fiber.New(routes.AppConfig(cfg))
+
+	app.Use(middleware.RequestID(), middleware.AccessLog(slog.Default()))
 	app.Use(cors.New(cors.Config{
-        AllowOrigins: "https://www.statsbanger.com/",  //frontend URL
-        AllowMethods: "GET,POST,HEAD,PUT,DELETE,OPTIONS",
-        AllowHeaders: "Origin, Content-Type, Accept",
-    }))
+		AllowOrigins:  strings.Join(cfg.CORSOrigins, ","), //frontend URLs
+		AllowMethods:  "GET,POST,HEAD,PUT,DELETE,OPTIONS",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, X-API-Key, " + middleware.HeaderRequestID,
+		ExposeHeaders: middleware.HeaderRequestID + ", Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, " + tabular.HeaderTotalCount,
+	}))
+	app.Use(middleware.Metrics(c.Metrics))
 
-	routes.SetupRoutes(app)
+	routes.SetupRoutes(app, c)
 
-	err := app.Listen(":3000")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listenErr := make(chan error, 1)
+	go func() { listenErr <- app.Listen(cfg.Addr()) }()
+
+	select {
+	case err := <-listenErr:
+		return err
+	case <-ctx.Done():
+	}
+	// A second signal kills the process the usual way.
+	stop()
+
+	slog.Info("shutting down", "timeout", cfg.ShutdownTimeout.String())
+	if cfg.ShutdownTimeout > 0 {
+		err = app.ShutdownWithTimeout(time.Duration(cfg.ShutdownTimeout))
+	} else {
+		err = app.Shutdown()
+	}
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-listenErr; err != nil {
+		return err
+	}
+	slog.Info("server stopped")
+	return nil
+}
+
+// checkDBFile refuses to serve a SQLite database that does not exist;
+// opening it would create an empty file instead.
+func checkDBFile(cfg *config.Config) error {
+	if cfg.DBDriver != config.DriverSQLite || strings.Contains(cfg.DBPath, ":memory:") {
+		return nil
+	}
+	if _, err := os.Stat(cfg.DBPath); err != nil {
+		return fmt.Errorf("database %s: %w (create it with the migrate command)", cfg.DBPath, err)
+	}
+	return nil
+}
+
+// checkSchema compares the stat registries with the database columns and
+// either logs or rejects any mismatch, depending on cfg.SchemaCheck.
+func checkSchema(cfg *config.Config, c *container.Container) error {
+	if cfg.SchemaCheck == config.SchemaCheckOff {
+		return nil
+	}
+
+	report, err := database.CheckSchema(c.DB)
+	if err != nil {
+		return fmt.Errorf("schema check: %w", err)
+	}
+	if report.OK {
+		slog.Info("schema check: stat registries match the database")
+		return nil
+	}
+
+	problems := report.Problems()
+	for _, problem := range problems {
+		slog.Warn("schema check: " + problem)
+	}
+	if cfg.SchemaCheck == config.SchemaCheckStrict {
+		return fmt.Errorf("schema check failed with %d mismatches (use -schema-check=warn to start anyway)", len(problems))
 	}
-}
\ No newline at end of file
+	return nil
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+	"github.com/plinphon/StatsBanger/backend/auth"
+)
+
+// apiKeyLocal is the c.Locals key of the authenticated *auth.Key.
+const apiKeyLocal = "apiKey"
+
+// Authenticate identifies the client of every request and enforces its
+// limits. Requests presenting a key, either as "Authorization: Bearer
+// <key>" or in the X-API-Key header, are limited by the rate limit and
+// daily quota of that key; an invalid key is rejected even where no key is
+// required. Requests without a key share anonymousLimit requests per
+// minute per IP address.
+func Authenticate(authn *auth.Authenticator, anonymousLimit int) fiber.Handler {
+	perMinute := auth.NewLimiter(time.Minute)
+	perDay := auth.NewLimiter(24 * time.Hour)
+
+	return func(c *fiber.Ctx) error {
+		now := time.Now()
+
+		token := presentedKey(c)
+		if token == "" {
+			usage, ok := perMinute.Allow("ip:"+c.IP(), anonymousLimit, now)
+			if err := limit(c, usage, ok, "Rate limit exceeded"); err != nil {
+				return err
+			}
+			return c.Next()
+		}
+
+		key, err := authn.Authenticate(c.UserContext(), token)
+		if errors.Is(err, auth.ErrInvalidKey) {
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid or revoked API key")
+		}
+		if err != nil {
+			return apperr.Wrap(err, "Failed to check API key")
+		}
+		c.Locals(apiKeyLocal, key)
+
+		usage, ok := perMinute.Allow("key:"+key.ID, key.RateLimit, now)
+		if err := limit(c, usage, ok, "Rate limit exceeded"); err != nil {
+			return err
+		}
+		quota, ok := perDay.Allow("key:"+key.ID, key.DailyQuota, now)
+		if !ok {
+			// A request over the quota does not use up the minute, so the
+			// key gets its full rate back when the quota resets.
+			if usage.Limit > 0 {
+				perMinute.Refund("key:"+key.ID, now)
+				c.Set("X-RateLimit-Remaining", strconv.Itoa(usage.Remaining+1))
+			}
+			c.Set(fiber.HeaderRetryAfter, retryAfter(quota, now))
+			return fiber.NewError(fiber.StatusTooManyRequests, "Daily quota exceeded")
+		}
+		return c.Next()
+	}
+}
+
+// limit sets the rate limit headers of usage and rejects the request when
+// it is over the limit.
+func limit(c *fiber.Ctx, usage auth.Usage, ok bool, message string) error {
+	if usage.Limit == 0 {
+		return nil
+	}
+	now := time.Now()
+	c.Set("X-RateLimit-Limit", strconv.Itoa(usage.Limit))
+	c.Set("X-RateLimit-Remaining", strconv.Itoa(usage.Remaining))
+	c.Set("X-RateLimit-Reset", retryAfter(usage, now))
+	if !ok {
+		c.Set(fiber.HeaderRetryAfter, retryAfter(usage, now))
+		return fiber.NewError(fiber.StatusTooManyRequests, message)
+	}
+	return nil
+}
+
+// retryAfter returns the whole seconds until the window of usage resets.
+func retryAfter(usage auth.Usage, now time.Time) string {
+	seconds := int(usage.Reset.Sub(now).Round(time.Second) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.Itoa(seconds)
+}
+
+func presentedKey(c *fiber.Ctx) string {
+	if auth := c.Get(fiber.HeaderAuthorization); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return c.Get("X-API-Key")
+}
+
+// APIKey returns the key the request was authenticated with, or nil for
+// anonymous requests.
+func APIKey(c *fiber.Ctx) *auth.Key {
+	key, _ := c.Locals(apiKeyLocal).(*auth.Key)
+	return key
+}
+
+// RequireScope only lets requests through whose key grants scope. It must
+// run after Authenticate.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := APIKey(c)
+		if key == nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid or missing API key")
+		}
+		if !key.Has(scope) {
+			return fiber.NewError(fiber.StatusForbidden, "API key lacks the "+scope+" scope")
+		}
+		return c.Next()
+	}
+}
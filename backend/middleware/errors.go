@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+)
+
+// Problem is an RFC 7807 problem details body.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// ErrorHandler is the fiber.Config ErrorHandler of the API. It answers
+// every error with an application/problem+json body whose status follows
+// the apperr kind of the error; unclassified errors are logged and become
+// a 500 that does not leak their text.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	status, detail := fiber.StatusInternalServerError, "Internal server error"
+
+	var fiberErr *fiber.Error
+	var appErr *apperr.Error
+	switch kind := apperr.Kind(err); {
+	case kind == apperr.ErrNotFound:
+		status, detail = fiber.StatusNotFound, err.Error()
+	case kind == apperr.ErrInvalidArgument:
+		status, detail = fiber.StatusBadRequest, err.Error()
+	case kind == apperr.ErrConflict:
+		status, detail = fiber.StatusConflict, err.Error()
+	case errors.As(err, &fiberErr):
+		status, detail = fiberErr.Code, fiberErr.Message
+	case errors.As(err, &appErr):
+		detail = appErr.Message
+	}
+	if status >= fiber.StatusInternalServerError {
+		slog.ErrorContext(c.UserContext(), "request failed",
+			"method", c.Method(), "path", c.Path(), "error", err)
+	}
+
+	return c.Status(status).JSON(Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.OriginalURL(),
+	}, "application/problem+json")
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/plinphon/StatsBanger/backend/logging"
+)
+
+// HeaderRequestID carries the ID of a request in both directions.
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength bounds the request IDs accepted from clients, which
+// end up in every log record of the request.
+const maxRequestIDLength = 128
+
+// RequestID gives every request an ID: the X-Request-ID header sent by the
+// client or a proxy, or a new UUID. The ID is echoed in the response and
+// stored in the user context, where loggers pick it up.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(HeaderRequestID)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = utils.UUIDv4()
+		} else {
+			// The header value lives in a buffer fiber reuses.
+			id = utils.CopyString(id)
+		}
+		c.Set(HeaderRequestID, id)
+		c.SetUserContext(logging.WithRequestID(c.UserContext(), id))
+		return c.Next()
+	}
+}
+
+// AccessLog logs one record per request with its status and latency. It
+// runs the error handler itself so the status of failed requests is the
+// one the client receives.
+func AccessLog(logger *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		handleError(c, c.Next())
+
+		status := c.Response().StatusCode()
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logger.LogAttrs(c.UserContext(), level, "request",
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.Int("bytes", len(c.Response().Body())),
+			slog.String("ip", c.IP()),
+		)
+		return nil
+	}
+}
+
+// handleError answers err through the error handler of the app right away,
+// for middleware that needs the final status of the response.
+func handleError(c *fiber.Ctx, err error) {
+	if err == nil {
+		return
+	}
+	if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+		c.Status(fiber.StatusInternalServerError)
+	}
+}
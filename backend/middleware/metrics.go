@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/plinphon/StatsBanger/backend/metrics"
+)
+
+// UnmatchedRoute is the route label of requests no route handles.
+const UnmatchedRoute = "unmatched"
+
+// Metrics records the route, status and latency of every request in m.
+func Metrics(m *metrics.Metrics) fiber.Handler {
+	// The routes are collected on the first request, once all of them are
+	// registered.
+	var once sync.Once
+	var routes map[string][]string
+
+	return func(c *fiber.Ctx) error {
+		once.Do(func() {
+			routes = make(map[string][]string)
+			for _, r := range c.App().GetRoutes(true) {
+				routes[r.Method] = append(routes[r.Method], r.Path)
+			}
+		})
+
+		start := time.Now()
+		handleError(c, c.Next())
+
+		m.ObserveRequest(routeOf(c, routes[c.Method()]), c.Method(), c.Response().StatusCode(), time.Since(start))
+		return nil
+	}
+}
+
+// routeOf returns the path of the route among routes that handled c. A
+// request ended by a middleware, such as one the API guard rejects, has a
+// middleware's path, which would be a misleading label; it gets the path of
+// the first route that matches it instead, or UnmatchedRoute.
+func routeOf(c *fiber.Ctx, routes []string) string {
+	for _, path := range routes {
+		if path == c.Route().Path && c.Route().Method == c.Method() {
+			return path
+		}
+	}
+	for _, path := range routes {
+		if fiber.RoutePatternMatch(c.Path(), path, c.App().Config()) {
+			return path
+		}
+	}
+	return UnmatchedRoute
+}
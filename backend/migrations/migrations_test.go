@@ -0,0 +1,150 @@
+package migrations
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openMemory(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func TestAllOrdered(t *testing.T) {
+	all, err := All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Version <= all[i-1].Version {
+			t.Errorf("migration %d listed after %d", all[i].Version, all[i-1].Version)
+		}
+	}
+}
+
+// Status only reads, so it works on a database that was never migrated,
+// even one opened read-only.
+func TestStatusWithoutTable(t *testing.T) {
+	db := openMemory(t)
+
+	m, err := New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != len(m.migrations) {
+		t.Fatalf("%d statuses, want %d", len(statuses), len(m.migrations))
+	}
+	for _, s := range statuses {
+		if s.Applied {
+			t.Errorf("migration %d reported as applied", s.Version)
+		}
+	}
+	if db.Migrator().HasTable("schema_migrations") {
+		t.Error("status created schema_migrations")
+	}
+}
+
+func TestUpDownStatus(t *testing.T) {
+	db := openMemory(t)
+
+	m, err := New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := m.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(m.migrations) {
+		t.Fatalf("applied %d migrations, want %d", len(applied), len(m.migrations))
+	}
+	for _, table := range []string{"match_info", "player_stat", "team_stat", "player_match_stat", "team_match_stat"} {
+		if !db.Migrator().HasTable(table) {
+			t.Errorf("table %s missing after up", table)
+		}
+	}
+
+	again, err := m.Up()
+	if err != nil || len(again) != 0 {
+		t.Fatalf("second up applied %d migrations (err %v), want none", len(again), err)
+	}
+
+	statuses, err := m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if !s.Applied || s.AppliedAt == nil {
+			t.Errorf("migration %d not reported as applied", s.Version)
+		}
+	}
+
+	// The initial schema may hold data it did not create.
+	rolledBack, err := m.Down(len(m.migrations), false)
+	if !errors.Is(err, ErrIrreversible) || len(rolledBack) != len(m.migrations)-1 {
+		t.Fatalf("down without force rolled back %d migrations (err %v), want all but the first", len(rolledBack), err)
+	}
+	if !db.Migrator().HasTable("player_stat") {
+		t.Fatal("player_stat dropped without force")
+	}
+
+	rolledBack, err = m.Down(1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rolledBack) != 1 || rolledBack[0].Version != 1 {
+		t.Fatalf("forced down rolled back %v, want the initial schema", rolledBack)
+	}
+	if db.Migrator().HasTable("player_stat") {
+		t.Error("player_stat still exists after rolling everything back")
+	}
+}
+
+// An existing database built before migrations existed is baselined by the
+// first migration instead of failing on the tables it already has.
+func TestUpBaselinesExistingSchema(t *testing.T) {
+	db := openMemory(t)
+
+	if err := db.Exec("CREATE TABLE team_info (team_id INTEGER NOT NULL PRIMARY KEY, team_name VARCHAR, home_stadium VARCHAR)").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("INSERT INTO team_info VALUES (2825, 'Athletic Bilbao', 'San Mamés')").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	var count int64
+	db.Table("team_info").Count(&count)
+	if count != 1 {
+		t.Errorf("team_info has %d rows after up, want the existing 1", count)
+	}
+}
@@ -0,0 +1,12 @@
+-- +irreversible
+-- The up migration baselines existing databases, so these tables may hold
+-- data that no migration created. Rolling back drops them only with -force.
+DROP TABLE IF EXISTS player_match_stat;
+DROP TABLE IF EXISTS team_match_stat;
+DROP TABLE IF EXISTS team_stat;
+DROP TABLE IF EXISTS player_stat;
+DROP TABLE IF EXISTS match_info;
+DROP TABLE IF EXISTS team_info;
+DROP TABLE IF EXISTS player_info;
+DROP TABLE IF EXISTS season_info;
+DROP TABLE IF EXISTS unique_tournament_info;
@@ -0,0 +1,394 @@
+-- Initial StatsBanger schema, matching the prebuilt laligaDB.db. Every table
+-- is created only if missing so existing databases can be baselined. The
+-- SQL is shared by SQLite and PostgreSQL; timestamps hold Unix seconds.
+
+CREATE TABLE IF NOT EXISTS unique_tournament_info (
+	unique_tournament_id INTEGER NOT NULL,
+	tournament_name VARCHAR,
+	country VARCHAR,
+	PRIMARY KEY (unique_tournament_id)
+);
+
+CREATE TABLE IF NOT EXISTS season_info (
+	season_id INTEGER NOT NULL,
+	season_name VARCHAR,
+	year VARCHAR,
+	PRIMARY KEY (season_id)
+);
+
+CREATE TABLE IF NOT EXISTS player_info (
+	player_id INTEGER NOT NULL,
+	player_name VARCHAR,
+	birthday_timestamp BIGINT,
+	position VARCHAR,
+	height FLOAT,
+	preferred_foot VARCHAR,
+	nationality VARCHAR,
+	PRIMARY KEY (player_id)
+);
+
+CREATE TABLE IF NOT EXISTS team_info (
+	team_id INTEGER NOT NULL,
+	team_name VARCHAR,
+	home_stadium VARCHAR,
+	PRIMARY KEY (team_id)
+);
+
+CREATE TABLE IF NOT EXISTS match_info (
+	match_id INTEGER NOT NULL,
+	unique_tournament_id INTEGER,
+	season_id INTEGER,
+	matchday INTEGER,
+	home_team_id INTEGER,
+	away_team_id INTEGER,
+	home_win INTEGER,
+	home_score INTEGER,
+	away_score INTEGER,
+	injury_time1 INTEGER,
+	injury_time2 INTEGER,
+	current_period_start_timestamp BIGINT,
+	PRIMARY KEY (match_id),
+	FOREIGN KEY(unique_tournament_id) REFERENCES unique_tournament_info (unique_tournament_id),
+	FOREIGN KEY(season_id) REFERENCES season_info (season_id),
+	FOREIGN KEY(home_team_id) REFERENCES team_info (team_id),
+	FOREIGN KEY(away_team_id) REFERENCES team_info (team_id)
+);
+
+CREATE TABLE IF NOT EXISTS player_stat (
+	player_id INTEGER NOT NULL,
+	unique_tournament_id INTEGER NOT NULL,
+	season_id INTEGER NOT NULL,
+	team_id INTEGER NOT NULL,
+	"minutes_played" FLOAT,
+	appearances FLOAT,
+	"matches_started" FLOAT,
+	goals FLOAT,
+	"expected_goals" FLOAT,
+	"goals_assists_sum" FLOAT,
+	"big_chances_missed" FLOAT,
+	"successful_dribbles" FLOAT,
+	"successful_dribbles_percentage" FLOAT,
+	"total_shots" FLOAT,
+	"shots_on_target" FLOAT,
+	"shots_off_target" FLOAT,
+	"blocked_shots" FLOAT,
+	"goal_conversion_percentage" FLOAT,
+	"penalties_taken" FLOAT,
+	"penalty_goals" FLOAT,
+	"penalty_won" FLOAT,
+	"shot_from_set_piece" FLOAT,
+	"free_kick_goal" FLOAT,
+	"shots_from_inside_the_box" FLOAT,
+	"shots_from_outside_the_box" FLOAT,
+	"goals_from_inside_the_box" FLOAT,
+	"goals_from_outside_the_box" FLOAT,
+	"headed_goals" FLOAT,
+	"left_foot_goals" FLOAT,
+	"right_foot_goals" FLOAT,
+	"hit_woodwork" FLOAT,
+	offsides FLOAT,
+	"penalty_conversion" FLOAT,
+	"set_piece_conversion" FLOAT,
+	tackles FLOAT,
+	interceptions FLOAT,
+	"penaltyConceded" FLOAT,
+	clearances FLOAT,
+	"error_lead_to_goal" FLOAT,
+	"error_lead_to_shot" FLOAT,
+	"own_goals" FLOAT,
+	"dribbled_past" FLOAT,
+	"big_chances_created" FLOAT,
+	assists FLOAT,
+	"expected_assists" FLOAT,
+	"accurate_passes" FLOAT,
+	"inaccurate_passes" FLOAT,
+	"total_passes" FLOAT,
+	"accurate_passes_percentage" FLOAT,
+	"accurate_own_half_passes" FLOAT,
+	"accurate_opposition_half_passes" FLOAT,
+	"accurate_final_third_passes" FLOAT,
+	"key_passes" FLOAT,
+	"total_cross" FLOAT,
+	"accurate_crosses" FLOAT,
+	"accurate_crosses_percentage" FLOAT,
+	"total_long_balls" FLOAT,
+	"accurate_long_balls" FLOAT,
+	"accurate_long_balls_percentage" FLOAT,
+	"pass_to_assist" FLOAT,
+	saves FLOAT,
+	"goals_prevented" FLOAT,
+	"clean_sheet" FLOAT,
+	"penalty_faced" FLOAT,
+	"penalty_save" FLOAT,
+	"saved_shots_from_inside_the_box" FLOAT,
+	"saved_shots_from_outside_the_box" FLOAT,
+	"goals_conceded_inside_the_box" FLOAT,
+	"goals_conceded_outside_the_box" FLOAT,
+	"goals_conceded" FLOAT,
+	punches FLOAT,
+	"runs_out" FLOAT,
+	"successful_runs_out" FLOAT,
+	"high_claims" FLOAT,
+	"crosses_not_claimed" FLOAT,
+	"yellow_cards" FLOAT,
+	"red_cards" FLOAT,
+	"ground_duels_won" FLOAT,
+	"ground_duels_won_percentage" FLOAT,
+	"aerial_duels_won" FLOAT,
+	"aerial_duels_won_percentage" FLOAT,
+	"total_duels_won" FLOAT,
+	"total_duels_won_percentage" FLOAT,
+	"was_fouled" FLOAT,
+	fouls FLOAT,
+	dispossessed FLOAT,
+	"possession_lost" FLOAT,
+	rating FLOAT,
+	PRIMARY KEY (player_id, unique_tournament_id, season_id, team_id),
+	FOREIGN KEY(player_id) REFERENCES player_info (player_id),
+	FOREIGN KEY(unique_tournament_id) REFERENCES unique_tournament_info (unique_tournament_id),
+	FOREIGN KEY(season_id) REFERENCES season_info (season_id),
+	FOREIGN KEY(team_id) REFERENCES team_info (team_id)
+);
+
+CREATE TABLE IF NOT EXISTS team_stat (
+	team_id INTEGER NOT NULL,
+	unique_tournament_id INTEGER NOT NULL,
+	season_id INTEGER NOT NULL,
+	"goals_scored" FLOAT,
+	"goals_conceded" FLOAT,
+	"expected_goals" FLOAT,
+	"expected_goals_conceded" FLOAT,
+	"own_goals" FLOAT,
+	assists FLOAT,
+	shots FLOAT,
+	"penalty_goals" FLOAT,
+	"penalties_taken" FLOAT,
+	"free_kick_goals" FLOAT,
+	"free_kick_shots" FLOAT,
+	"goals_from_inside_the_box" FLOAT,
+	"goals_from_outside_the_box" FLOAT,
+	"shots_from_inside_the_box" FLOAT,
+	"shots_from_outside_the_box" FLOAT,
+	"headed_goals" FLOAT,
+	"left_foot_goals" FLOAT,
+	"right_foot_goals" FLOAT,
+	"big_chances" FLOAT,
+	"big_chances_created" FLOAT,
+	"big_chances_missed" FLOAT,
+	"shots_on_target" FLOAT,
+	"shots_off_target" FLOAT,
+	"blocked_scoring_attempt" FLOAT,
+	"successful_dribbles" FLOAT,
+	"dribble_attempts" FLOAT,
+	corners FLOAT,
+	"hit_woodwork" FLOAT,
+	"fast_breaks" FLOAT,
+	"fast_break_goals" FLOAT,
+	"fast_break_shots" FLOAT,
+	"average_ball_possession" FLOAT,
+	"total_passes" FLOAT,
+	"accurate_passes" FLOAT,
+	"accurate_passes_percentage" FLOAT,
+	"total_own_half_passes" FLOAT,
+	"accurate_own_half_passes" FLOAT,
+	"accurate_own_half_passes_percentage" FLOAT,
+	"total_opposition_half_passes" FLOAT,
+	"accurate_opposition_half_passes" FLOAT,
+	"accurate_opposition_half_passes_percentage" FLOAT,
+	"total_long_balls" FLOAT,
+	"accurate_long_balls" FLOAT,
+	"accurate_long_balls_percentage" FLOAT,
+	"total_crosses" FLOAT,
+	"accurate_crosses" FLOAT,
+	"accurate_crosses_percentage" FLOAT,
+	"clean_sheets" FLOAT,
+	tackles FLOAT,
+	interceptions FLOAT,
+	saves FLOAT,
+	"errors_leading_to_goal" FLOAT,
+	"errors_leading_to_shot" FLOAT,
+	"penalties_committed" FLOAT,
+	"penalty_goals_conceded" FLOAT,
+	clearances FLOAT,
+	"clearances_off_line" FLOAT,
+	"last_man_tackles" FLOAT,
+	"total_duels" FLOAT,
+	"duels_won" FLOAT,
+	"duels_won_percentage" FLOAT,
+	"total_ground_duels" FLOAT,
+	"ground_duels_won" FLOAT,
+	"ground_duels_won_percentage" FLOAT,
+	"total_aerial_duels" FLOAT,
+	"aerial_duels_won" FLOAT,
+	"aerial_duels_won_percentage" FLOAT,
+	"possession_lost" FLOAT,
+	offsides FLOAT,
+	fouls FLOAT,
+	"yellow_cards" FLOAT,
+	"yellow_red_cards" FLOAT,
+	"red_cards" FLOAT,
+	"avg_rating" FLOAT,
+	"accurate_final_third_passes_against" FLOAT,
+	"accurate_opposition_half_passes_against" FLOAT,
+	"accurate_own_half_passes_against" FLOAT,
+	"accurate_passes_against" FLOAT,
+	"big_chances_against" FLOAT,
+	"big_chances_created_against" FLOAT,
+	"big_chances_missed_against" FLOAT,
+	"clearances_against" FLOAT,
+	"corners_against" FLOAT,
+	"crosses_successful_against" FLOAT,
+	"crosses_total_against" FLOAT,
+	"dribble_attempts_total_against" FLOAT,
+	"dribble_attempts_won_against" FLOAT,
+	"errors_leading_to_goal_against" FLOAT,
+	"errors_leading_to_shot_against" FLOAT,
+	"hit_woodwork_against" FLOAT,
+	"interceptions_against" FLOAT,
+	"key_passes_against" FLOAT,
+	"long_balls_successful_against" FLOAT,
+	"long_balls_total_against" FLOAT,
+	"offsides_against" FLOAT,
+	"red_cards_against" FLOAT,
+	"shots_against" FLOAT,
+	"shots_blocked_against" FLOAT,
+	"shots_from_inside_the_box_against" FLOAT,
+	"shots_from_outside_the_box_against" FLOAT,
+	"shots_off_target_against" FLOAT,
+	"shots_on_target_against" FLOAT,
+	"blocked_scoring_attempt_against" FLOAT,
+	"tackles_against" FLOAT,
+	"total_final_third_passes_against" FLOAT,
+	"opposition_half_passes_total_against" FLOAT,
+	"own_half_passes_total_against" FLOAT,
+	"total_passes_against" FLOAT,
+	"yellow_cards_against" FLOAT,
+	"throw_ins" FLOAT,
+	"goal_kicks" FLOAT,
+	"ball_recovery" FLOAT,
+	"free_kicks" FLOAT,
+	matches FLOAT,
+	PRIMARY KEY (team_id, unique_tournament_id, season_id),
+	FOREIGN KEY(team_id) REFERENCES team_info (team_id),
+	FOREIGN KEY(unique_tournament_id) REFERENCES unique_tournament_info (unique_tournament_id),
+	FOREIGN KEY(season_id) REFERENCES season_info (season_id)
+);
+
+CREATE TABLE IF NOT EXISTS team_match_stat (
+	"match_id" INTEGER NOT NULL,
+	"team_id" INTEGER NOT NULL,
+	"ball_possession" FLOAT,
+	"expected_goals" FLOAT,
+	"big_chances" FLOAT,
+	"total_shots" FLOAT,
+	"goalkeeper_saves" FLOAT,
+	"corner_kicks" FLOAT,
+	"fouls" FLOAT,
+	"passes" FLOAT,
+	"tackles" FLOAT,
+	"free_kicks" FLOAT,
+	"yellow_cards" FLOAT,
+	"red_cards" FLOAT,
+	"shots_on_target" FLOAT,
+	"hit_woodwork" FLOAT,
+	"shots_off_target" FLOAT,
+	"blocked_shots" FLOAT,
+	"shots_inside_box" FLOAT,
+	"shots_outside_box" FLOAT,
+	"big_chances_scored" FLOAT,
+	"big_chances_missed" FLOAT,
+	"through_balls" FLOAT,
+	"touches_in_penalty_area" FLOAT,
+	"fouled_in_final_third" FLOAT,
+	"offsides" FLOAT,
+	"accurate_passes" FLOAT,
+	"throw_ins" FLOAT,
+	"final_third_entries" FLOAT,
+	"final_third_phase" FLOAT,
+	"long_balls" FLOAT,
+	"crosses" FLOAT,
+	"duels" FLOAT,
+	"dispossessed" FLOAT,
+	"ground_duels" FLOAT,
+	"aerial_duels" FLOAT,
+	"dribbles" FLOAT,
+	"tackles_won" FLOAT,
+	"total_tackles" FLOAT,
+	"interceptions" FLOAT,
+	"recoveries" FLOAT,
+	"clearances" FLOAT,
+	"total_saves" FLOAT,
+	"goals_prevented" FLOAT,
+	"goal_kicks" FLOAT,
+	"big_saves" FLOAT,
+	"high_claims" FLOAT,
+	"punches" FLOAT,
+	"errors_lead_to_a_shot" FLOAT,
+	"errors_lead_to_a_goal" FLOAT,
+	"penalty_saves" FLOAT,
+	PRIMARY KEY ("match_id", "team_id"),
+	FOREIGN KEY("match_id") REFERENCES match_info (match_id),
+	FOREIGN KEY("team_id") REFERENCES team_info (team_id)
+);
+
+CREATE TABLE IF NOT EXISTS player_match_stat (
+	"match_id" INTEGER NOT NULL,
+	"player_id" INTEGER NOT NULL,
+	"team_id" INTEGER NOT NULL,
+	"total_pass" FLOAT,
+	"accurate_pass" FLOAT,
+	"total_long_balls" FLOAT,
+	"accurate_long_balls" FLOAT,
+	"goal_assist" FLOAT,
+	"saved_shots_from_inside_the_box" FLOAT,
+	"saves" FLOAT,
+	"minutes_played" FLOAT,
+	"touches" FLOAT,
+	"rating" FLOAT,
+	"possession_lost_ctrl" FLOAT,
+	"key_pass" FLOAT,
+	"goals_prevented" FLOAT,
+	"aerial_won" FLOAT,
+	"duel_lost" FLOAT,
+	"duel_won" FLOAT,
+	"on_target_scoring_attempt" FLOAT,
+	"goals" FLOAT,
+	"total_clearance" FLOAT,
+	"interception_won" FLOAT,
+	"total_tackle" FLOAT,
+	"was_fouled" FLOAT,
+	"fouls" FLOAT,
+	"expected_goals" FLOAT,
+	"expected_assists" FLOAT,
+	"aerial_lost" FLOAT,
+	"challenge_lost" FLOAT,
+	"total_cross" FLOAT,
+	"total_contest" FLOAT,
+	"won_contest" FLOAT,
+	"outfielder_block" FLOAT,
+	"big_chance_created" FLOAT,
+	"dispossessed" FLOAT,
+	"shot_off_target" FLOAT,
+	"accurate_cross" FLOAT,
+	"total_offside" FLOAT,
+	"blocked_scoring_attempt" FLOAT,
+	"penalty_won" FLOAT,
+	"penalty_conceded" FLOAT,
+	"big_chance_missed" FLOAT,
+	"total_keeper_sweeper" FLOAT,
+	"accurate_keeper_sweeper" FLOAT,
+	"good_high_claim" FLOAT,
+	"punches" FLOAT,
+	"clearance_off_line" FLOAT,
+	"hit_woodwork" FLOAT,
+	"error_lead_to_a_shot" FLOAT,
+	"own_goals" FLOAT,
+	"last_man_tackle" FLOAT,
+	"error_lead_to_a_goal" FLOAT,
+	"penalty_save" FLOAT,
+	"penalty_miss" FLOAT,
+	PRIMARY KEY ("match_id", "player_id", "team_id"),
+	FOREIGN KEY("match_id") REFERENCES match_info (match_id),
+	FOREIGN KEY("player_id") REFERENCES player_info (player_id),
+	FOREIGN KEY("team_id") REFERENCES team_info (team_id)
+);
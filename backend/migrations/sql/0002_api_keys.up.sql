@@ -0,0 +1,14 @@
+-- API keys. Only the SHA-256 hash of a key is stored; key_id is the public
+-- part of the key that locates its row. Limits of 0 mean unlimited.
+
+CREATE TABLE IF NOT EXISTS api_key (
+	key_id VARCHAR NOT NULL,
+	name VARCHAR NOT NULL,
+	key_hash VARCHAR NOT NULL,
+	scopes VARCHAR NOT NULL,
+	rate_limit INTEGER NOT NULL DEFAULT 0,
+	daily_quota INTEGER NOT NULL DEFAULT 0,
+	created_at BIGINT NOT NULL,
+	revoked_at BIGINT,
+	PRIMARY KEY (key_id)
+);
@@ -17,9 +17,9 @@ type Match struct {
 	HomeWin                *int           `gorm:"column:home_win" json:"homeWin,omitempty"`
 	HomeScore              *int           `gorm:"column:home_score" json:"homeScore,omitempty"`
 	AwayScore              *int           `gorm:"column:away_score" json:"awayScore,omitempty"`
-	InjuryTime1            *int           `gorm:"column:injury_time_1" json:"injuryTime1,omitempty"`
-	InjuryTime2            *int           `gorm:"column:injury_time_2" json:"injuryTime2,omitempty"`
-	CurrentPeriodStartTimestamp time.Time `gorm:"column:current_period_start_timestamp" json:"currentPeriodStartTimestamp"`
+	InjuryTime1            *int           `gorm:"column:injury_time1" json:"injuryTime1,omitempty"`
+	InjuryTime2            *int           `gorm:"column:injury_time2" json:"injuryTime2,omitempty"`
+	CurrentPeriodStartTimestamp time.Time `gorm:"column:current_period_start_timestamp;serializer:unixseconds" json:"currentPeriodStartTimestamp"`
 }
 
 func (Match) TableName() string {
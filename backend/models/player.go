@@ -9,7 +9,7 @@ type Player struct {
 	PlayerId      int       `json:"id" gorm:"primaryKey;column:player_id"`
 	PlayerName    string    `json:"name" gorm:"column:player_name"`
 	PlayerSeasonStat *PlayerSeasonStat `gorm:"foreignKey:PlayerId;references:PlayerId" json:"playerSeasonStat,omitempty"`
-	Birthday      time.Time `json:"birthdayTimestamp" gorm:"column:birthday_timestamp"`
+	Birthday      time.Time `json:"birthdayTimestamp" gorm:"column:birthday_timestamp;serializer:unixseconds"`
 	Age           int       `json:"age" gorm:"-"`
 	Position      string    `json:"position" gorm:"column:position"`
 	Height        float64   `json:"height" gorm:"column:height"`
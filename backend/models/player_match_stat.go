@@ -15,60 +15,58 @@ func (PlayerMatchStat) TableName() string {
     return "player_match_stat"
 }
 
-var ValidPlayerMatchFields = map[string]bool{
-	"match_id":                       true,
-	"player_id":                      true,
-	"team_id":                        true,
-	"total_pass":                     true,
-	"accurate_pass":                  true,
-	"total_long_balls":              true,
-	"accurate_long_balls":          true,
-	"goal_assist":                    true,
-	"saved_shots_from_inside_the_box": true,
-	"saves":                          true,
-	"minutes_played":                 true,
-	"touches":                        true,
-	"rating":                         true,
-	"possession_lost_ctrl":          true,
-	"key_pass":                       true,
-	"goals_prevented":                true,
-	"aerial_won":                     true,
-	"duel_lost":                      true,
-	"duel_won":                       true,
-	"on_target_scoring_attempt":     true,
-	"goals":                          true,
-	"total_clearance":               true,
-	"interception_won":              true,
-	"total_tackle":                  true,
-	"was_fouled":                     true,
-	"fouls":                          true,
-	"expected_goals":                true,
-	"expected_assists":              true,
-	"aerial_lost":                    true,
-	"challenge_lost":                true,
-	"total_cross":                   true,
-	"total_contest":                 true,
-	"won_contest":                   true,
-	"outfielder_block":              true,
-	"big_chance_created":           true,
-	"dispossessed":                   true,
-	"shot_off_target":              true,
-	"accurate_cross":               true,
-	"total_offside":                true,
-	"blocked_scoring_attempt":      true,
-	"penalty_won":                   true,
-	"penalty_conceded":             true,
-	"big_chance_missed":            true,
-	"total_keeper_sweeper":         true,
-	"accurate_keeper_sweeper":     true,
-	"good_high_claim":              true,
-	"punches":                        true,
-	"clearance_off_line":           true,
-	"hit_woodwork":                  true,
-	"error_lead_to_a_shot":         true,
-	"own_goals":                     true,
-	"last_man_tackle":              true,
-	"error_lead_to_a_goal":         true,
-	"penalty_save":                  true,
-	"penalty_miss":                  true,
-}
+// PlayerMatchStats describes every stat column of player_match_stat.
+var PlayerMatchStats = NewStatRegistry("player-match", "player_match_stat", []string{"match_id", "player_id", "team_id"}, []Stat{
+	{Field: "total_pass", Label: "Passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_pass", Label: "Accurate passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "total_long_balls", Label: "Total long balls", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_long_balls", Label: "Accurate long balls", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goal_assist", Label: "Assists", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "saved_shots_from_inside_the_box", Label: "Saved shots from inside the box", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "saves", Label: "Saves", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "minutes_played", Label: "Minutes played", Category: General, Unit: Count, Per90: false, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "touches", Label: "Touches", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "rating", Label: "Rating", Category: General, Unit: Rating, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "possession_lost_ctrl", Label: "Possession lost", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "key_pass", Label: "Key passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goals_prevented", Label: "Goals prevented", Category: Goalkeeping, Unit: XG, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "aerial_won", Label: "Aerial duels won", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "duel_lost", Label: "Duels lost", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "duel_won", Label: "Duels won", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "on_target_scoring_attempt", Label: "Shots on target", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goals", Label: "Goals", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "total_clearance", Label: "Clearances", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "interception_won", Label: "Interceptions", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "total_tackle", Label: "Tackles", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "was_fouled", Label: "Fouled", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "fouls", Label: "Fouls", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "expected_goals", Label: "Expected goals (xG)", Category: Attacking, Unit: XG, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "expected_assists", Label: "Expected assists (xA)", Category: Passing, Unit: XG, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "aerial_lost", Label: "Aerial duels lost", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "challenge_lost", Label: "Dribbled past", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "total_cross", Label: "Crosses", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "total_contest", Label: "Dribble attempts", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "won_contest", Label: "Successful dribbles", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "outfielder_block", Label: "Blocks", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "big_chance_created", Label: "Big chances created", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "dispossessed", Label: "Dispossessed", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "shot_off_target", Label: "Shots off target", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_cross", Label: "Accurate crosses", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "total_offside", Label: "Offsides", Category: Attacking, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "blocked_scoring_attempt", Label: "Blocked shots", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "penalty_won", Label: "Penalties won", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "penalty_conceded", Label: "Penalties conceded", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "big_chance_missed", Label: "Big chances missed", Category: Attacking, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "total_keeper_sweeper", Label: "Sweeper actions", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_keeper_sweeper", Label: "Successful sweeper actions", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "good_high_claim", Label: "High claims", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "punches", Label: "Punches", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "clearance_off_line", Label: "Clearances off the line", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "hit_woodwork", Label: "Hit woodwork", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "error_lead_to_a_shot", Label: "Errors leading to a shot", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "own_goals", Label: "Own goals", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "last_man_tackle", Label: "Last man tackle", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "error_lead_to_a_goal", Label: "Errors leading to a goal", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "penalty_save", Label: "Penalties saved", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "penalty_miss", Label: "Penalties missed", Category: Attacking, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+})
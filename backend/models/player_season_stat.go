@@ -14,93 +14,90 @@ func (PlayerSeasonStat) TableName() string {
     return "player_stat"
 }
 
-var ValidPlayerSeasonFields = map[string]bool{
-	"player_id":                         true,
-	"unique_tournament_id":              true,
-	"season_id":                        true,
-	"team_id":                         true,
-	"minutes_played":                   true,
-	"appearances":                     true,
-	"matches_started":                 true,
-	"goals":                          true,
-	"expected_goals":                 true,
-	"goals_assists_sum":              true,
-	"big_chances_missed":             true,
-	"successful_dribbles":            true,
-	"successful_dribbles_percentage": true,
-	"total_shots":                   true,
-	"shots_on_target":               true,
-	"shots_off_target":              true,
-	"blocked_shots":                 true,
-	"goal_conversion_percentage":    true,
-	"penalties_taken":               true,
-	"penalty_goals":                true,
-	"penalty_won":                  true,
-	"shot_from_set_piece":           true,
-	"free_kick_goal":               true,
-	"shots_from_inside_the_box":    true,
-	"shots_from_outside_the_box":   true,
-	"goals_from_inside_the_box":    true,
-	"goals_from_outside_the_box":   true,
-	"headed_goals":                 true,
-	"left_foot_goals":              true,
-	"right_foot_goals":             true,
-	"hit_woodwork":                 true,
-	"offsides":                    true,
-	"penalty_conversion":           true,
-	"set_piece_conversion":         true,
-	"tackles":                     true,
-	"interceptions":               true,
-	"penaltyConceded":             true,
-	"clearances":                  true,
-	"error_lead_to_goal":          true,
-	"error_lead_to_shot":          true,
-	"own_goals":                   true,
-	"dribbled_past":               true,
-	"big_chances_created":         true,
-	"assists":                    true,
-	"expected_assists":            true,
-	"accurate_passes":             true,
-	"inaccurate_passes":           true,
-	"total_passes":                true,
-	"accurate_passes_percentage":  true,
-	"accurate_own_half_passes":    true,
-	"accurate_opposition_half_passes": true,
-	"accurate_final_third_passes": true,
-	"key_passes":                 true,
-	"total_cross":                true,
-	"accurate_crosses":           true,
-	"accurate_crosses_percentage": true,
-	"total_long_balls":           true,
-	"accurate_long_balls":        true,
-	"accurate_long_balls_percentage": true,
-	"pass_to_assist":             true,
-	"saves":                     true,
-	"goals_prevented":            true,
-	"clean_sheet":                true,
-	"penalty_faced":              true,
-	"penalty_save":               true,
-	"saved_shots_from_inside_the_box": true,
-	"saved_shots_from_outside_the_box": true,
-	"goals_conceded_inside_the_box": true,
-	"goals_conceded_outside_the_box": true,
-	"goals_conceded":             true,
-	"punches":                   true,
-	"runs_out":                  true,
-	"successful_runs_out":        true,
-	"high_claims":               true,
-	"crosses_not_claimed":        true,
-	"yellow_cards":              true,
-	"red_cards":                 true,
-	"ground_duels_won":          true,
-	"ground_duels_won_percentage": true,
-	"aerial_duels_won":           true,
-	"aerial_duels_won_percentage": true,
-	"total_duels_won":            true,
-	"total_duels_won_percentage": true,
-	"was_fouled":                true,
-	"fouls":                     true,
-	"dispossessed":              true,
-	"possession_lost":           true,
-	"rating":                    true,
-}
+// PlayerSeasonStats describes every stat column of player_stat.
+var PlayerSeasonStats = NewStatRegistry("player-season", "player_stat", []string{"player_id", "unique_tournament_id", "season_id", "team_id"}, []Stat{
+	{Field: "minutes_played", Label: "Minutes played", Category: General, Unit: Count, Per90: false, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "appearances", Label: "Appearances", Category: General, Unit: Count, Per90: false, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "matches_started", Label: "Matches started", Category: General, Unit: Count, Per90: false, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goals", Label: "Goals", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "expected_goals", Label: "Expected goals (xG)", Category: Attacking, Unit: XG, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goals_assists_sum", Label: "Goals + assists", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "big_chances_missed", Label: "Big chances missed", Category: Attacking, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "successful_dribbles", Label: "Successful dribbles", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "successful_dribbles_percentage", Label: "Successful dribbles %", Category: Attacking, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "total_shots", Label: "Total shots", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shots_on_target", Label: "Shots on target", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shots_off_target", Label: "Shots off target", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "blocked_shots", Label: "Blocked shots", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goal_conversion_percentage", Label: "Goal conversion %", Category: Attacking, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "penalties_taken", Label: "Penalties taken", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "penalty_goals", Label: "Penalty goals", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "penalty_won", Label: "Penalties won", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shot_from_set_piece", Label: "Shots from set pieces", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "free_kick_goal", Label: "Free-kick goals", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shots_from_inside_the_box", Label: "Shots from inside the box", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shots_from_outside_the_box", Label: "Shots from outside the box", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goals_from_inside_the_box", Label: "Goals from inside the box", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goals_from_outside_the_box", Label: "Goals from outside the box", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "headed_goals", Label: "Headed goals", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "left_foot_goals", Label: "Left-foot goals", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "right_foot_goals", Label: "Right-foot goals", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "hit_woodwork", Label: "Hit woodwork", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "offsides", Label: "Offsides", Category: Attacking, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "penalty_conversion", Label: "Penalty conversion %", Category: Attacking, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "set_piece_conversion", Label: "Set-piece conversion %", Category: Attacking, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "tackles", Label: "Tackles", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "interceptions", Label: "Interceptions", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "penaltyConceded", Label: "Penalties conceded", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "clearances", Label: "Clearances", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "error_lead_to_goal", Label: "Errors leading to a goal", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "error_lead_to_shot", Label: "Errors leading to a shot", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "own_goals", Label: "Own goals", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "dribbled_past", Label: "Dribbled past", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "big_chances_created", Label: "Big chances created", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "assists", Label: "Assists", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "expected_assists", Label: "Expected assists (xA)", Category: Passing, Unit: XG, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_passes", Label: "Accurate passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "inaccurate_passes", Label: "Inaccurate passes", Category: Passing, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "total_passes", Label: "Total passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_passes_percentage", Label: "Accurate passes %", Category: Passing, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "accurate_own_half_passes", Label: "Accurate own half passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_opposition_half_passes", Label: "Accurate opposition half passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_final_third_passes", Label: "Accurate final third passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "key_passes", Label: "Key passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "total_cross", Label: "Crosses", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_crosses", Label: "Accurate crosses", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_crosses_percentage", Label: "Accurate crosses %", Category: Passing, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "total_long_balls", Label: "Total long balls", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_long_balls", Label: "Accurate long balls", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_long_balls_percentage", Label: "Accurate long balls %", Category: Passing, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "pass_to_assist", Label: "Passes to assist", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "saves", Label: "Saves", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goals_prevented", Label: "Goals prevented", Category: Goalkeeping, Unit: XG, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "clean_sheet", Label: "Clean sheets", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "penalty_faced", Label: "Penalties faced", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "penalty_save", Label: "Penalties saved", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "saved_shots_from_inside_the_box", Label: "Saved shots from inside the box", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "saved_shots_from_outside_the_box", Label: "Saved shots from outside the box", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goals_conceded_inside_the_box", Label: "Goals conceded inside the box", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "goals_conceded_outside_the_box", Label: "Goals conceded outside the box", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "goals_conceded", Label: "Goals conceded", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "punches", Label: "Punches", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "runs_out", Label: "Runs out", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "successful_runs_out", Label: "Successful runs out", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "high_claims", Label: "High claims", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "crosses_not_claimed", Label: "Crosses not claimed", Category: Goalkeeping, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "yellow_cards", Label: "Yellow cards", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "red_cards", Label: "Red cards", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "ground_duels_won", Label: "Ground duels won", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "ground_duels_won_percentage", Label: "Ground duels won %", Category: General, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "aerial_duels_won", Label: "Aerial duels won", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "aerial_duels_won_percentage", Label: "Aerial duels won %", Category: General, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "total_duels_won", Label: "Total duels won", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "total_duels_won_percentage", Label: "Total duels won %", Category: General, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "was_fouled", Label: "Fouled", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "fouls", Label: "Fouls", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "dispossessed", Label: "Dispossessed", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "possession_lost", Label: "Possession lost", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "rating", Label: "Rating", Category: General, Unit: Rating, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+})
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// StatCategory groups stats the way the frontend lays them out.
+type StatCategory string
+
+const (
+	Attacking   StatCategory = "attacking"
+	Passing     StatCategory = "passing"
+	Defending   StatCategory = "defending"
+	Goalkeeping StatCategory = "goalkeeping"
+	General     StatCategory = "general"
+)
+
+// StatUnit says how a stat value is measured.
+type StatUnit string
+
+const (
+	Count      StatUnit = "count"
+	Percentage StatUnit = "percentage"
+	Rating     StatUnit = "rating"
+	XG         StatUnit = "xG"
+)
+
+// SortDirection says which end of a ranking is best.
+type SortDirection string
+
+const (
+	// HigherIsBetter ranks the largest values first.
+	HigherIsBetter SortDirection = "desc"
+	// LowerIsBetter ranks the smallest values first.
+	LowerIsBetter SortDirection = "asc"
+)
+
+// Aggregation says how values from several rows, such as the matches of a
+// season, combine into one.
+type Aggregation string
+
+const (
+	Sum Aggregation = "sum"
+	Avg Aggregation = "avg"
+)
+
+// Stat describes one stat column.
+type Stat struct {
+	Field       string        `json:"field"`
+	Label       string        `json:"label"`
+	Category    StatCategory  `json:"category"`
+	Unit        StatUnit      `json:"unit"`
+	Per90       bool          `json:"per90"`
+	Direction   SortDirection `json:"direction"`
+	Aggregation Aggregation   `json:"aggregation"`
+}
+
+// Bounds returns the fixed range of the stat's unit, if it has one.
+func (s Stat) Bounds() (min, max float64, ok bool) {
+	switch s.Unit {
+	case Percentage:
+		return 0, 100, true
+	case Rating:
+		return 0, 10, true
+	}
+	return 0, 0, false
+}
+
+// StatRegistry lists every stat column of one entity's table, in display
+// order. Key columns such as player_id are not stats; they are listed
+// separately in Keys.
+type StatRegistry struct {
+	Entity string
+	Table  string
+	Keys   []string
+	stats  []Stat
+	index  map[string]int
+}
+
+// NewStatRegistry builds a registry, panicking on duplicate fields since
+// registries are package-level literals.
+func NewStatRegistry(entity, table string, keys []string, stats []Stat) *StatRegistry {
+	r := &StatRegistry{Entity: entity, Table: table, Keys: keys, stats: stats, index: make(map[string]int, len(stats))}
+	for i, s := range stats {
+		if _, dup := r.index[s.Field]; dup {
+			panic(fmt.Sprintf("stat registry %s: duplicate field %s", entity, s.Field))
+		}
+		r.index[s.Field] = i
+	}
+	return r
+}
+
+// Has reports whether field is a stat of the registry.
+func (r *StatRegistry) Has(field string) bool {
+	_, ok := r.index[field]
+	return ok
+}
+
+// Lookup returns the metadata of field.
+func (r *StatRegistry) Lookup(field string) (Stat, bool) {
+	i, ok := r.index[field]
+	if !ok {
+		return Stat{}, false
+	}
+	return r.stats[i], true
+}
+
+// Stats returns the metadata of every stat in display order.
+func (r *StatRegistry) Stats() []Stat {
+	return append([]Stat(nil), r.stats...)
+}
+
+// Fields returns every stat name in display order.
+func (r *StatRegistry) Fields() []string {
+	fields := make([]string, len(r.stats))
+	for i, s := range r.stats {
+		fields[i] = s.Field
+	}
+	return fields
+}
+
+// StatRegistries holds the registry of every entity by name.
+var StatRegistries = map[string]*StatRegistry{
+	PlayerSeasonStats.Entity: PlayerSeasonStats,
+	PlayerMatchStats.Entity:  PlayerMatchStats,
+	TeamSeasonStats.Entity:   TeamSeasonStats,
+	TeamMatchStats.Entity:    TeamMatchStats,
+}
+
+// StatEntities returns the names of every registry, sorted.
+func StatEntities() []string {
+	names := make([]string, 0, len(StatRegistries))
+	for name := range StatRegistries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
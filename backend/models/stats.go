@@ -0,0 +1,22 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/plinphon/StatsBanger/backend/apperr"
+)
+
+// ErrInvalidStatField is returned when a stat name is not in the registry
+// of the table it is read from or written to.
+var ErrInvalidStatField = apperr.New(apperr.ErrInvalidArgument, "invalid stat field")
+
+// ValidateStats checks that every key of stats is a stat of the given
+// registry. Key columns are not stats, so they are rejected too.
+func ValidateStats(stats map[string]*float64, registry *StatRegistry) error {
+	for field := range stats {
+		if !registry.Has(field) {
+			return fmt.Errorf("%w: %s", ErrInvalidStatField, field)
+		}
+	}
+	return nil
+}
@@ -14,56 +14,55 @@ func (TeamMatchStat) TableName() string {
     return "team_match_stat"
 }
 
-var ValidTeamMatchFields = map[string]bool{
-	"match_id":               true,
-	"team_id":                true,
-	"ball_possession":        true,
-	"expected_goals":         true,
-	"big_chances":            true,
-	"total_shots":            true,
-	"goalkeeper_saves":       true,
-	"corner_kicks":           true,
-	"fouls":                  true,
-	"passes":                 true,
-	"tackles":                true,
-	"free_kicks":             true,
-	"yellow_cards":           true,
-	"red_cards":              true,
-	"shots_on_target":        true,
-	"hit_woodwork":           true,
-	"shots_off_target":       true,
-	"blocked_shots":          true,
-	"shots_inside_box":       true,
-	"shots_outside_box":      true,
-	"big_chances_scored":     true,
-	"big_chances_missed":     true,
-	"through_balls":          true,
-	"touches_in_penalty_area": true,
-	"fouled_in_final_third":  true,
-	"offsides":               true,
-	"accurate_passes":        true,
-	"throw_ins":              true,
-	"final_third_entries":    true,
-	"final_third_phase":      true,
-	"long_balls":             true,
-	"crosses":                true,
-	"duels":                  true,
-	"dispossessed":           true,
-	"ground_duels":           true,
-	"aerial_duels":           true,
-	"dribbles":               true,
-	"tackles_won":            true,
-	"total_tackles":          true,
-	"interceptions":          true,
-	"recoveries":             true,
-	"clearances":             true,
-	"total_saves":            true,
-	"goals_prevented":        true,
-	"goal_kicks":             true,
-	"big_saves":              true,
-	"high_claims":            true,
-	"punches":                true,
-	"errors_lead_to_a_shot":  true,
-	"errors_lead_to_a_goal":  true,
-	"penalty_saves":          true,
-}
+// TeamMatchStats describes every stat column of team_match_stat.
+var TeamMatchStats = NewStatRegistry("team-match", "team_match_stat", []string{"match_id", "team_id"}, []Stat{
+	{Field: "ball_possession", Label: "Ball possession", Category: General, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "expected_goals", Label: "Expected goals (xG)", Category: Attacking, Unit: XG, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "big_chances", Label: "Big chances", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "total_shots", Label: "Total shots", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goalkeeper_saves", Label: "Goalkeeper saves", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "corner_kicks", Label: "Corner kicks", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "fouls", Label: "Fouls", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "passes", Label: "Passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "tackles", Label: "Tackles", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "free_kicks", Label: "Free kicks", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "yellow_cards", Label: "Yellow cards", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "red_cards", Label: "Red cards", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "shots_on_target", Label: "Shots on target", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "hit_woodwork", Label: "Hit woodwork", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shots_off_target", Label: "Shots off target", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "blocked_shots", Label: "Blocked shots", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shots_inside_box", Label: "Shots inside the box", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shots_outside_box", Label: "Shots outside the box", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "big_chances_scored", Label: "Big chances scored", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "big_chances_missed", Label: "Big chances missed", Category: Attacking, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "through_balls", Label: "Through balls", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "touches_in_penalty_area", Label: "Touches in penalty area", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "fouled_in_final_third", Label: "Fouled in final third", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "offsides", Label: "Offsides", Category: Attacking, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "accurate_passes", Label: "Accurate passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "throw_ins", Label: "Throw-ins", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "final_third_entries", Label: "Final third entries", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "final_third_phase", Label: "Final third phase", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "long_balls", Label: "Long balls", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "crosses", Label: "Crosses", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "duels", Label: "Duels", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "dispossessed", Label: "Dispossessed", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "ground_duels", Label: "Ground duels", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "aerial_duels", Label: "Aerial duels", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "dribbles", Label: "Dribbles", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "tackles_won", Label: "Tackles won", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "total_tackles", Label: "Total tackles", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "interceptions", Label: "Interceptions", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "recoveries", Label: "Recoveries", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "clearances", Label: "Clearances", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "total_saves", Label: "Total saves", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goals_prevented", Label: "Goals prevented", Category: Goalkeeping, Unit: XG, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goal_kicks", Label: "Goal kicks", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "big_saves", Label: "Big saves", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "high_claims", Label: "High claims", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "punches", Label: "Punches", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "errors_lead_to_a_shot", Label: "Errors leading to a shot", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "errors_lead_to_a_goal", Label: "Errors leading to a goal", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "penalty_saves", Label: "Penalties saved", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+})
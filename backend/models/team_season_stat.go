@@ -15,122 +15,120 @@ func (TeamSeasonStat) TableName() string {
 }
 
 
-var ValidTeamSeasonFields = map[string]bool{
-    "team_id":                           true,
-    "unique_tournament_id":              true,
-    "season_id":                        true,
-    "goals_scored":                    true,
-    "goals_conceded":                  true,
-    "expected_goals":                  true,
-    "expected_goals_conceded":         true,
-    "own_goals":                      true,
-    "assists":                        true,
-    "shots":                          true,
-    "penalty_goals":                  true,
-    "penalties_taken":                true,
-    "free_kick_goals":                true,
-    "free_kick_shots":                true,
-    "goals_from_inside_the_box":       true,
-    "goals_from_outside_the_box":      true,
-    "shots_from_inside_the_box":       true,
-    "shots_from_outside_the_box":      true,
-    "headed_goals":                   true,
-    "left_foot_goals":                true,
-    "right_foot_goals":               true,
-    "big_chances":                   true,
-    "big_chances_created":           true,
-    "big_chances_missed":            true,
-    "shots_on_target":               true,
-    "shots_off_target":              true,
-    "blocked_scoring_attempt":       true,
-    "successful_dribbles":           true,
-    "dribble_attempts":              true,
-    "corners":                      true,
-    "hit_woodwork":                 true,
-    "fast_breaks":                  true,
-    "fast_break_goals":             true,
-    "fast_break_shots":             true,
-    "average_ball_possession":      true,
-    "total_passes":                 true,
-    "accurate_passes":              true,
-    "accurate_passes_percentage":   true,
-    "total_own_half_passes":        true,
-    "accurate_own_half_passes":     true,
-    "accurate_own_half_passes_percentage": true,
-    "total_opposition_half_passes": true,
-    "accurate_opposition_half_passes": true,
-    "accurate_opposition_half_passes_percentage": true,
-    "total_long_balls":             true,
-    "accurate_long_balls":          true,
-    "accurate_long_balls_percentage": true,
-    "total_crosses":               true,
-    "accurate_crosses":            true,
-    "accurate_crosses_percentage": true,
-    "clean_sheets":                true,
-    "tackles":                    true,
-    "interceptions":              true,
-    "saves":                      true,
-    "errors_leading_to_goal":      true,
-    "errors_leading_to_shot":      true,
-    "penalties_committed":         true,
-    "penalty_goals_conceded":      true,
-    "clearances":                  true,
-    "clearances_off_line":          true,
-    "last_man_tackles":            true,
-    "total_duels":                 true,
-    "duels_won":                  true,
-    "duels_won_percentage":        true,
-    "total_ground_duels":           true,
-    "ground_duels_won":             true,
-    "ground_duels_won_percentage":  true,
-    "total_aerial_duels":           true,
-    "aerial_duels_won":             true,
-    "aerial_duels_won_percentage":  true,
-    "possession_lost":             true,
-    "offsides":                   true,
-    "fouls":                      true,
-    "yellow_cards":                true,
-    "yellow_red_cards":            true,
-    "red_cards":                  true,
-    "avg_rating":                 true,
-    "accurate_final_third_passes_against": true,
-    "accurate_opposition_half_passes_against": true,
-    "accurate_own_half_passes_against": true,
-    "accurate_passes_against":     true,
-    "big_chances_against":          true,
-    "big_chances_created_against":  true,
-    "big_chances_missed_against":   true,
-    "clearances_against":           true,
-    "corners_against":             true,
-    "crosses_successful_against":   true,
-    "crosses_total_against":        true,
-    "dribble_attempts_total_against": true,
-    "dribble_attempts_won_against": true,
-    "errors_leading_to_goal_against": true,
-    "errors_leading_to_shot_against": true,
-    "hit_woodwork_against":         true,
-    "interceptions_against":       true,
-    "key_passes_against":           true,
-    "long_balls_successful_against": true,
-    "long_balls_total_against":     true,
-    "offsides_against":            true,
-    "red_cards_against":           true,
-    "shots_against":               true,
-    "shots_blocked_against":        true,
-    "shots_from_inside_the_box_against": true,
-    "shots_from_outside_the_box_against": true,
-    "shots_off_target_against":     true,
-    "shots_on_target_against":      true,
-    "blocked_scoring_attempt_against": true,
-    "tackles_against":             true,
-    "total_final_third_passes_against": true,
-    "opposition_half_passes_total_against": true,
-    "own_half_passes_total_against": true,
-    "total_passes_against":        true,
-    "yellow_cards_against":        true,
-    "throw_ins":                  true,
-    "goal_kicks":                 true,
-    "ball_recovery":              true,
-    "free_kicks":                 true,
-    "matches":                    true,
-}
+// TeamSeasonStats describes every stat column of team_stat.
+var TeamSeasonStats = NewStatRegistry("team-season", "team_stat", []string{"team_id", "unique_tournament_id", "season_id"}, []Stat{
+	{Field: "goals_scored", Label: "Goals scored", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goals_conceded", Label: "Goals conceded", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "expected_goals", Label: "Expected goals (xG)", Category: Attacking, Unit: XG, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "expected_goals_conceded", Label: "Expected goals conceded (xGA)", Category: Defending, Unit: XG, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "own_goals", Label: "Own goals", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "assists", Label: "Assists", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shots", Label: "Shots", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "penalty_goals", Label: "Penalty goals", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "penalties_taken", Label: "Penalties taken", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "free_kick_goals", Label: "Free-kick goals", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "free_kick_shots", Label: "Free-kick shots", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goals_from_inside_the_box", Label: "Goals from inside the box", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goals_from_outside_the_box", Label: "Goals from outside the box", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shots_from_inside_the_box", Label: "Shots from inside the box", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shots_from_outside_the_box", Label: "Shots from outside the box", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "headed_goals", Label: "Headed goals", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "left_foot_goals", Label: "Left-foot goals", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "right_foot_goals", Label: "Right-foot goals", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "big_chances", Label: "Big chances", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "big_chances_created", Label: "Big chances created", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "big_chances_missed", Label: "Big chances missed", Category: Attacking, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "shots_on_target", Label: "Shots on target", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shots_off_target", Label: "Shots off target", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "blocked_scoring_attempt", Label: "Blocked shots", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "successful_dribbles", Label: "Successful dribbles", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "dribble_attempts", Label: "Dribble attempts", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "corners", Label: "Corners", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "hit_woodwork", Label: "Hit woodwork", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "fast_breaks", Label: "Fast breaks", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "fast_break_goals", Label: "Fast-break goals", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "fast_break_shots", Label: "Fast-break shots", Category: Attacking, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "average_ball_possession", Label: "Average ball possession", Category: General, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "total_passes", Label: "Total passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_passes", Label: "Accurate passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_passes_percentage", Label: "Accurate passes %", Category: Passing, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "total_own_half_passes", Label: "Total own half passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_own_half_passes", Label: "Accurate own half passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_own_half_passes_percentage", Label: "Accurate own half passes %", Category: Passing, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "total_opposition_half_passes", Label: "Total opposition half passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_opposition_half_passes", Label: "Accurate opposition half passes", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_opposition_half_passes_percentage", Label: "Accurate opposition half passes %", Category: Passing, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "total_long_balls", Label: "Total long balls", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_long_balls", Label: "Accurate long balls", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_long_balls_percentage", Label: "Accurate long balls %", Category: Passing, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "total_crosses", Label: "Total crosses", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_crosses", Label: "Accurate crosses", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "accurate_crosses_percentage", Label: "Accurate crosses %", Category: Passing, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "clean_sheets", Label: "Clean sheets", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "tackles", Label: "Tackles", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "interceptions", Label: "Interceptions", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "saves", Label: "Saves", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "errors_leading_to_goal", Label: "Errors leading to a goal", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "errors_leading_to_shot", Label: "Errors leading to a shot", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "penalties_committed", Label: "Penalties committed", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "penalty_goals_conceded", Label: "Penalty goals conceded", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "clearances", Label: "Clearances", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "clearances_off_line", Label: "Clearances off line", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "last_man_tackles", Label: "Last man tackles", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "total_duels", Label: "Total duels", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "duels_won", Label: "Duels won", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "duels_won_percentage", Label: "Duels won %", Category: General, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "total_ground_duels", Label: "Total ground duels", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "ground_duels_won", Label: "Ground duels won", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "ground_duels_won_percentage", Label: "Ground duels won %", Category: General, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "total_aerial_duels", Label: "Total aerial duels", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "aerial_duels_won", Label: "Aerial duels won", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "aerial_duels_won_percentage", Label: "Aerial duels won %", Category: General, Unit: Percentage, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "possession_lost", Label: "Possession lost", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "offsides", Label: "Offsides", Category: Attacking, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "fouls", Label: "Fouls", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "yellow_cards", Label: "Yellow cards", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "yellow_red_cards", Label: "Second yellow cards", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "red_cards", Label: "Red cards", Category: General, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "avg_rating", Label: "Average rating", Category: General, Unit: Rating, Per90: false, Direction: HigherIsBetter, Aggregation: Avg},
+	{Field: "accurate_final_third_passes_against", Label: "Opponent accurate final third passes", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "accurate_opposition_half_passes_against", Label: "Opponent accurate opposition half passes", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "accurate_own_half_passes_against", Label: "Opponent accurate own half passes", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "accurate_passes_against", Label: "Opponent accurate passes", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "big_chances_against", Label: "Opponent big chances", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "big_chances_created_against", Label: "Opponent big chances created", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "big_chances_missed_against", Label: "Opponent big chances missed", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "clearances_against", Label: "Opponent clearances", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "corners_against", Label: "Opponent corners", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "crosses_successful_against", Label: "Opponent crosses successful", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "crosses_total_against", Label: "Opponent crosses total", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "dribble_attempts_total_against", Label: "Opponent dribble attempts total", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "dribble_attempts_won_against", Label: "Opponent dribble attempts won", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "errors_leading_to_goal_against", Label: "Opponent errors leading to a goal", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "errors_leading_to_shot_against", Label: "Opponent errors leading to a shot", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "hit_woodwork_against", Label: "Opponent hit woodwork", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "interceptions_against", Label: "Opponent interceptions", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "key_passes_against", Label: "Opponent key passes", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "long_balls_successful_against", Label: "Opponent long balls successful", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "long_balls_total_against", Label: "Opponent long balls total", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "offsides_against", Label: "Opponent offsides", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "red_cards_against", Label: "Opponent red cards", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shots_against", Label: "Opponent shots", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "shots_blocked_against", Label: "Opponent shots blocked", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "shots_from_inside_the_box_against", Label: "Opponent shots from inside the box", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "shots_from_outside_the_box_against", Label: "Opponent shots from outside the box", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "shots_off_target_against", Label: "Opponent shots off target", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "shots_on_target_against", Label: "Opponent shots on target", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "blocked_scoring_attempt_against", Label: "Opponent blocked scoring attempt", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "tackles_against", Label: "Opponent tackles", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "total_final_third_passes_against", Label: "Opponent total final third passes", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "opposition_half_passes_total_against", Label: "Opponent opposition half passes total", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "own_half_passes_total_against", Label: "Opponent own half passes total", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "total_passes_against", Label: "Opponent total passes", Category: Defending, Unit: Count, Per90: true, Direction: LowerIsBetter, Aggregation: Sum},
+	{Field: "yellow_cards_against", Label: "Opponent yellow cards", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "throw_ins", Label: "Throw-ins", Category: Passing, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "goal_kicks", Label: "Goal kicks", Category: Goalkeeping, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "ball_recovery", Label: "Ball recoveries", Category: Defending, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "free_kicks", Label: "Free kicks", Category: General, Unit: Count, Per90: true, Direction: HigherIsBetter, Aggregation: Sum},
+	{Field: "matches", Label: "Matches", Category: General, Unit: Count, Per90: false, Direction: HigherIsBetter, Aggregation: Sum},
+})
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Tournament is a competition that the database has matches or stats of.
+type Tournament struct {
+	Id      int    `json:"id"`
+	Name    string `json:"name"`
+	Country string `json:"country"`
+	// StartYear and EndYear span its seasons.
+	StartYear int `json:"startYear"`
+	EndYear   int `json:"endYear"`
+	Seasons   int `json:"seasons"`
+	// CurrentSeasonId is its latest season, which clients can default to.
+	CurrentSeasonId int `json:"currentSeasonId"`
+	Matches         int `json:"matches"`
+}
+
+// Season is a season of a tournament with matches or stats, such as
+// LaLiga 23/24.
+type Season struct {
+	Id                 int    `json:"id"`
+	UniqueTournamentId int    `json:"uniqueTournamentId"`
+	Name               string `json:"name"`
+	// Year is the season's own label, such as "23/24" or "2024"; StartYear
+	// and EndYear are the calendar years it spans.
+	Year      string `json:"year"`
+	StartYear int    `json:"startYear"`
+	EndYear   int    `json:"endYear"`
+	// Current marks the latest season of the tournament.
+	Current bool `json:"current"`
+
+	Matches       int `json:"matches"`
+	PlayedMatches int `json:"playedMatches"`
+	// FirstMatch and LastMatch are the kickoffs of its earliest and
+	// latest matches, if it has any.
+	FirstMatch *time.Time `json:"firstMatch,omitempty"`
+	LastMatch  *time.Time `json:"lastMatch,omitempty"`
+
+	HasPlayerStats bool `json:"hasPlayerStats"`
+	HasTeamStats   bool `json:"hasTeamStats"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+// The timestamp columns of the StatsBanger database hold Unix seconds. The
+// "unixseconds" serializer keeps them that way when a time.Time field is
+// written, instead of the driver's default text format.
+func init() {
+	schema.RegisterSerializer("unixseconds", UnixSecondsSerializer{})
+}
+
+// UnixSecondsSerializer stores a time.Time field as Unix seconds.
+type UnixSecondsSerializer struct{}
+
+func (UnixSecondsSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var t time.Time
+	switch v := dbValue.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		// go-sqlite3 already converts TIMESTAMP columns.
+		t = v
+	case int64:
+		t = time.Unix(v, 0)
+	case string:
+		parsed, err := parseTimestamp(v)
+		if err != nil {
+			return err
+		}
+		t = parsed
+	case []byte:
+		parsed, err := parseTimestamp(string(v))
+		if err != nil {
+			return err
+		}
+		t = parsed
+	default:
+		return fmt.Errorf("cannot scan %T into %s", dbValue, field.Name)
+	}
+	return field.Set(ctx, dst, t.UTC())
+}
+
+func (UnixSecondsSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	switch v := fieldValue.(type) {
+	case time.Time:
+		return v.Unix(), nil
+	case *time.Time:
+		if v == nil {
+			return nil, nil
+		}
+		return v.Unix(), nil
+	}
+	return nil, fmt.Errorf("unixseconds serializer: unsupported type %T for %s", fieldValue, field.Name)
+}
+
+// parseTimestamp reads the text formats older rows may have been written in.
+func parseTimestamp(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+}
@@ -0,0 +1,132 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemas turns Go types into schemas, registering every named struct as a
+// component so that recursive models such as Player and PlayerSeasonStat
+// resolve to references.
+type schemas struct {
+	components map[string]*Schema
+	// stats maps a model to the registry of its Stats map, whose fields
+	// become the properties of the map's schema.
+	stats map[reflect.Type]*models.StatRegistry
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: make(map[string]*Schema),
+		stats: map[reflect.Type]*models.StatRegistry{
+			reflect.TypeOf(models.PlayerMatchStat{}):  models.PlayerMatchStats,
+			reflect.TypeOf(models.PlayerSeasonStat{}): models.PlayerSeasonStats,
+			reflect.TypeOf(models.TeamMatchStat{}):    models.TeamMatchStats,
+			reflect.TypeOf(models.TeamSeasonStat{}):   models.TeamSeasonStats,
+		},
+	}
+}
+
+// of returns the schema of values of v's type.
+func (s *schemas) of(v any) *Schema {
+	return s.schema(reflect.TypeOf(v))
+}
+
+func (s *schemas) schema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		return nullable(s.schema(t.Elem()))
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schema(t.Elem())}
+	case reflect.Struct:
+		return s.ref(t)
+	}
+	return &Schema{}
+}
+
+// ref registers the named struct t as a component and refers to it.
+func (s *schemas) ref(t reflect.Type) *Schema {
+	name := t.Name()
+	if _, ok := s.components[name]; !ok {
+		object := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		s.components[name] = object
+		s.fields(object, t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// fields adds the JSON fields of t to object the way encoding/json would
+// marshal them: embedded structs are flattened, untagged fields keep their
+// Go name and omitempty fields are optional.
+func (s *schemas) fields(object *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			s.fields(object, f.Type)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		schema := s.schema(f.Type)
+		if registry, ok := s.stats[t]; ok && f.Name == "Stats" {
+			schema = statsSchema(registry)
+		}
+		object.Properties[name] = schema
+		if !strings.Contains(opts, "omitempty") {
+			object.Required = append(object.Required, name)
+		}
+	}
+}
+
+// statsSchema describes a Stats map: every registry field may appear, and
+// is null when the row has no value for it.
+func statsSchema(registry *models.StatRegistry) *Schema {
+	schema := &Schema{
+		Type:        "object",
+		Description: "Stat values by field; see /api/meta/stats/" + registry.Entity + ". Only the requested statFields are present.",
+		Properties:  make(map[string]*Schema),
+	}
+	for _, stat := range registry.Stats() {
+		schema.Properties[stat.Field] = &Schema{Type: "number", Format: "double", Nullable: true, Description: stat.Label}
+	}
+	return schema
+}
+
+// nullable marks schema as accepting null. A reference cannot carry
+// siblings in OpenAPI 3.0, so it is wrapped in allOf first.
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+	}
+	schema.Nullable = true
+	return schema
+}
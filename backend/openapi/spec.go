@@ -0,0 +1,606 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/plinphon/StatsBanger/backend/api/export"
+	"github.com/plinphon/StatsBanger/backend/api/graph"
+	"github.com/plinphon/StatsBanger/backend/api/health"
+	"github.com/plinphon/StatsBanger/backend/api/matches"
+	"github.com/plinphon/StatsBanger/backend/api/meta"
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+	"github.com/plinphon/StatsBanger/backend/database"
+	"github.com/plinphon/StatsBanger/backend/middleware"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// Security scheme names. Both carry the same API key.
+const (
+	BearerAuth = "bearerAuth"
+	APIKeyAuth = "apiKeyHeader"
+)
+
+const (
+	mediaJSON    = "application/json"
+	mediaProblem = "application/problem+json"
+)
+
+var noExplode = new(bool)
+
+// docsAssets are the files of docs.Assets, which imports this package.
+var docsAssets = []string{"swagger-ui.css", "swagger-ui-bundle.js"}
+
+// unguarded are the /api paths mounted before the API's authentication
+// and rate limits.
+var unguarded = map[string]bool{
+	"/api/openapi.json": true,
+	"/api/docs":         true,
+	"/api/docs/{asset}": true,
+}
+
+// builder accumulates the operations of a document.
+type builder struct {
+	doc     *Document
+	schemas *schemas
+}
+
+// Spec describes every route that routes.SetupRoutes registers. A test
+// in package routes fails when the two disagree.
+func Spec() *Document {
+	b := &builder{
+		doc: &Document{
+			OpenAPI: Version,
+			Info: Info{
+				Title:   "StatsBanger API",
+				Version: "1.0.0",
+				Description: "Football match, team and player statistics.\n\n" +
+					"Reads are anonymous unless the server runs with -anonymous-reads=false, and are rate " +
+					"limited per IP address; requests presenting an API key are limited by that key instead. " +
+					"Writes need a key with the write scope. Errors are RFC 7807 problem documents.",
+			},
+			Tags: []Tag{
+				{Name: "tournaments", Description: "The tournaments and seasons that have data."},
+				{Name: "matches"},
+				{Name: "teams"},
+				{Name: "team stats", Description: "Per-match and per-season team stats."},
+				{Name: "players"},
+				{Name: "player stats", Description: "Per-match and per-season player stats."},
+				{Name: "export", Description: "Whole seasons of the stat tables as Parquet or Arrow files."},
+				{Name: "meta", Description: "Stat metadata and schema checks."},
+				{Name: "graphql", Description: "The same data as a GraphQL schema; introspect it for the types."},
+				{Name: "operations", Description: "Health, metrics and this document."},
+			},
+			Paths: make(map[string]*PathItem),
+			Components: Components{
+				SecuritySchemes: map[string]*SecurityScheme{
+					BearerAuth: {Type: "http", Scheme: "bearer", Description: "An API key as a bearer token."},
+					APIKeyAuth: {Type: "apiKey", In: "header", Name: "X-API-Key", Description: "An API key."},
+				},
+			},
+		},
+		schemas: newSchemas(),
+	}
+
+	b.operations()
+	b.tournaments()
+	b.matches()
+	b.teams()
+	b.teamMatchStats()
+	b.teamSeasonStats()
+	b.players()
+	b.playerMatchStats()
+	b.playerSeasonStats()
+	b.exports()
+	b.meta()
+	b.graphql()
+
+	b.doc.Components.Schemas = b.schemas.components
+	return b.doc
+}
+
+func (b *builder) operations() {
+	b.add(http.MethodGet, "/metrics", &Operation{
+		OperationID: "getMetrics", Tags: []string{"operations"},
+		Summary:  "Prometheus metrics",
+		Security: []map[string][]string{{BearerAuth: {}}, {APIKeyAuth: {}}},
+		Responses: map[string]*Response{
+			"200": {Description: "Metrics in the Prometheus text format.", Content: map[string]*MediaType{
+				"text/plain": {Schema: &Schema{Type: "string"}},
+			}},
+			"401": b.problem("Missing, invalid or revoked API key."),
+			"403": b.problem("The API key lacks the admin scope."),
+			"429": b.problem("Rate limit or daily quota exceeded."),
+		},
+	})
+	b.add(http.MethodGet, "/healthz", &Operation{
+		OperationID: "getLiveness", Tags: []string{"operations"},
+		Summary:   "Liveness probe",
+		Responses: b.responses(http.StatusOK, health.Status{}),
+	})
+	readiness := b.responses(http.StatusOK, health.Status{})
+	readiness["503"] = &Response{
+		Description: "The database is unreachable or a core table is missing.",
+		Content:     jsonContent(b.schemas.of(health.Status{})),
+	}
+	b.add(http.MethodGet, "/readyz", &Operation{
+		OperationID: "getReadiness", Tags: []string{"operations"},
+		Summary:   "Readiness probe",
+		Responses: readiness,
+	})
+	b.add(http.MethodGet, "/api/openapi.json", &Operation{
+		OperationID: "getOpenAPI", Tags: []string{"operations"},
+		Summary: "This document",
+		Responses: map[string]*Response{
+			"200": {Description: "The OpenAPI document.", Content: jsonContent(&Schema{Type: "object"})},
+		},
+	})
+	b.add(http.MethodGet, "/api/docs", &Operation{
+		OperationID: "getDocs", Tags: []string{"operations"},
+		Summary: "Interactive documentation of this document",
+		Responses: map[string]*Response{
+			"200": {Description: "An HTML page.", Content: map[string]*MediaType{
+				"text/html": {Schema: &Schema{Type: "string"}},
+			}},
+		},
+	})
+	b.add(http.MethodGet, "/api/docs/{asset}", &Operation{
+		OperationID: "getDocsAsset", Tags: []string{"operations"},
+		Summary: "A swagger-ui file loaded by the docs page",
+		Parameters: []*Parameter{
+			{Name: "asset", In: "path", Required: true, Schema: &Schema{Type: "string", Enum: docsAssets}},
+		},
+		Responses: map[string]*Response{
+			"200": {Description: "The file."},
+			"404": b.problem("No such asset."),
+		},
+	})
+}
+
+func (b *builder) tournaments() {
+	b.add(http.MethodGet, "/api/tournaments", b.list(&Operation{
+		OperationID: "listTournaments", Tags: []string{"tournaments"},
+		Summary: "List the tournaments",
+		Description: "Every tournament with matches or stats, by ID, with the years its seasons span " +
+			"and its current season, the latest one.",
+		Responses: b.responses(http.StatusOK, []models.Tournament{}),
+	}))
+	b.add(http.MethodGet, "/api/tournaments/{tournamentID}/seasons", b.list(&Operation{
+		OperationID: "listSeasons", Tags: []string{"tournaments"},
+		Summary: "List the seasons of a tournament",
+		Description: "Every season with matches or stats, latest first; the first is flagged current. " +
+			"Matches counts scheduled matches and playedMatches those with a score.",
+		Parameters: []*Parameter{pathID("tournamentID", "Tournament ID.")},
+		Responses:  b.responses(http.StatusOK, []models.Season{}, http.StatusBadRequest, http.StatusNotFound),
+	}))
+}
+
+func (b *builder) matches() {
+	zero, one, maxLimit := 0.0, 1.0, float64(matches.MaxLimit)
+	list := b.list(&Operation{
+		OperationID: "listMatches", Tags: []string{"matches"},
+		Summary: "List matches",
+		Description: "A page of the matches that pass every filter given, ordered by kick-off: " +
+			"played=true&order=desc lists results, played=false upcoming fixtures.",
+		Parameters: []*Parameter{
+			idQuery("uniqueTournamentID", "Tournament ID.", false),
+			idQuery("seasonID", "Season ID.", false),
+			query("matchday", "Matchday (round) of the season.", false, &Schema{Type: "integer", Minimum: &one}),
+			idQuery("teamID", "Only matches of this team.", false),
+			query("side", "Only the team's home or away matches; both if absent.", false,
+				&Schema{Type: "string", Enum: []string{matches.SideHome, matches.SideAway}}),
+			query("from", "Earliest kick-off, a date or an RFC 3339 time.", false, &Schema{Type: "string"}),
+			query("to", "Latest kick-off, a date (inclusive of the whole day) or an RFC 3339 time.", false, &Schema{Type: "string"}),
+			query("played", "Only matches with a score, or only those without.", false, &Schema{Type: "boolean"}),
+			query("order", "Kick-off order.", false, &Schema{Type: "string", Enum: []string{"asc", "desc"}}),
+			query("limit", "Page size; defaults to "+strconv.Itoa(matches.DefaultLimit)+".", false,
+				&Schema{Type: "integer", Minimum: &one, Maximum: &maxLimit}),
+			query("offset", "Number of matches to skip.", false, &Schema{Type: "integer", Minimum: &zero}),
+		},
+		Responses: b.responses(http.StatusOK, []models.Match{}),
+	})
+	list.Responses["200"].Headers = map[string]*Header{
+		tabular.HeaderTotalCount: {Description: "How many matches pass the filters, across every page.", Schema: &Schema{Type: "integer"}},
+	}
+	b.add(http.MethodGet, "/api/match", list)
+	b.add(http.MethodGet, "/api/match/{matchID}", &Operation{
+		OperationID: "getMatch", Tags: []string{"matches"},
+		Summary:    "Get a match",
+		Parameters: []*Parameter{pathID("matchID", "Match ID.")},
+		Responses:  b.responses(http.StatusOK, models.Match{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	b.add(http.MethodPost, "/api/match", &Operation{
+		OperationID: "createMatch", Tags: []string{"matches"},
+		Summary:     "Create a match",
+		RequestBody: jsonBody(b.schemas.of(models.Match{})),
+		Responses:   b.responses(http.StatusCreated, models.Match{}, http.StatusBadRequest, http.StatusConflict),
+	})
+	b.add(http.MethodPut, "/api/match/{matchID}", &Operation{
+		OperationID: "upsertMatch", Tags: []string{"matches"},
+		Summary:     "Create or replace a match",
+		Description: "The id in the body, if any, must match the path.",
+		Parameters:  []*Parameter{pathID("matchID", "Match ID.")},
+		RequestBody: jsonBody(b.schemas.of(models.Match{})),
+		Responses:   b.responses(http.StatusOK, models.Match{}, http.StatusBadRequest),
+	})
+}
+
+func (b *builder) teams() {
+	b.add(http.MethodGet, "/api/team/{teamID}", &Operation{
+		OperationID: "getTeam", Tags: []string{"teams"},
+		Summary:    "Get a team",
+		Parameters: []*Parameter{pathID("teamID", "Team ID.")},
+		Responses:  b.responses(http.StatusOK, models.Team{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	b.add(http.MethodGet, "/api/team", b.list(&Operation{
+		OperationID: "searchTeams", Tags: []string{"teams"},
+		Summary:    "Search teams by name",
+		Parameters: []*Parameter{query("name", "Case-insensitive substring of the team name.", false, &Schema{Type: "string"})},
+		Responses:  b.responses(http.StatusOK, []models.Team{}),
+	}))
+}
+
+func (b *builder) teamMatchStats() {
+	registry := models.TeamMatchStats
+	b.add(http.MethodGet, "/api/team-match-stat", &Operation{
+		OperationID: "getTeamMatchStat", Tags: []string{"team stats"},
+		Summary: "Get a team's stats in one match",
+		Parameters: []*Parameter{
+			idQuery("matchID", "Match ID.", true),
+			idQuery("teamID", "Team ID.", true),
+			statFields(registry, false),
+		},
+		Responses: b.responses(http.StatusOK, models.TeamMatchStat{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	b.add(http.MethodGet, "/api/team-match-stat/team/{teamID}", b.list(&Operation{
+		OperationID: "listTeamMatchStats", Tags: []string{"team stats"},
+		Summary:    "List a team's stats in every match",
+		Parameters: []*Parameter{pathID("teamID", "Team ID.")},
+		Responses:  b.responses(http.StatusOK, []models.TeamMatchStat{}, http.StatusBadRequest),
+	}))
+	b.writes("/api/team-match-stat", "TeamMatchStats", "team match stats", models.TeamMatchStat{})
+}
+
+func (b *builder) teamSeasonStats() {
+	registry := models.TeamSeasonStats
+	b.add(http.MethodGet, "/api/team-season-stat", b.list(&Operation{
+		OperationID: "listTeamSeasonStats", Tags: []string{"team stats"},
+		Summary: "List team stats of a season",
+		Parameters: []*Parameter{
+			idQuery("uniqueTournamentID", "Tournament ID.", true),
+			idQuery("seasonID", "Season ID.", true),
+			idList("teamID", "Team IDs, comma separated. Defaults to every team of the season."),
+			statFields(registry, false),
+		},
+		Responses: b.responses(http.StatusOK, []models.TeamSeasonStat{}, http.StatusBadRequest),
+	}))
+	b.add(http.MethodGet, "/api/team-season-stat/top-teams", b.list(&Operation{
+		OperationID: "getTopTeams", Tags: []string{"team stats"},
+		Summary: "Rank the teams of a season by one stat",
+		Parameters: []*Parameter{
+			statField(registry),
+			idQuery("uniqueTournamentID", "Tournament ID.", true),
+			idQuery("seasonID", "Season ID.", true),
+			limit(),
+		},
+		Responses: b.responses(http.StatusOK, []models.TopTeamStatResult{}, http.StatusBadRequest),
+	}))
+	b.writes("/api/team-season-stat", "TeamSeasonStats", "team season stats", models.TeamSeasonStat{})
+}
+
+func (b *builder) players() {
+	b.add(http.MethodGet, "/api/player/{playerID}", &Operation{
+		OperationID: "getPlayer", Tags: []string{"players"},
+		Summary:    "Get a player",
+		Parameters: []*Parameter{pathID("playerID", "Player ID.")},
+		Responses:  b.responses(http.StatusOK, models.Player{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	b.add(http.MethodGet, "/api/player", b.list(&Operation{
+		OperationID: "searchPlayers", Tags: []string{"players"},
+		Summary:    "Search players by name",
+		Parameters: []*Parameter{query("name", "Case-insensitive substring of the player name.", false, &Schema{Type: "string"})},
+		Responses:  b.responses(http.StatusOK, []models.Player{}),
+	}))
+}
+
+func (b *builder) playerMatchStats() {
+	registry := models.PlayerMatchStats
+	b.add(http.MethodGet, "/api/player-match-stat", b.list(&Operation{
+		OperationID: "listPlayerMatchStatsByMatch", Tags: []string{"player stats"},
+		Summary: "List every player's stats in one match",
+		Parameters: []*Parameter{
+			idQuery("matchID", "Match ID.", true),
+			statFields(registry, false),
+		},
+		Responses: b.responses(http.StatusOK, []models.PlayerMatchStat{}, http.StatusBadRequest),
+	}))
+	b.add(http.MethodGet, "/api/player-match-stat/player/{playerID}", b.list(&Operation{
+		OperationID: "listPlayerMatchStatsByPlayer", Tags: []string{"player stats"},
+		Summary:    "List a player's stats in every match",
+		Parameters: []*Parameter{pathID("playerID", "Player ID.")},
+		Responses:  b.responses(http.StatusOK, []models.PlayerMatchStat{}, http.StatusBadRequest),
+	}))
+	b.add(http.MethodGet, "/api/player-match-stat/player/{playerID}/match/{matchID}", &Operation{
+		OperationID: "getPlayerMatchStat", Tags: []string{"player stats"},
+		Summary:    "Get a player's stats in one match",
+		Parameters: []*Parameter{pathID("playerID", "Player ID."), pathID("matchID", "Match ID.")},
+		Responses:  b.responses(http.StatusOK, models.PlayerMatchStat{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	b.writes("/api/player-match-stat", "PlayerMatchStats", "player match stats", models.PlayerMatchStat{})
+}
+
+func (b *builder) playerSeasonStats() {
+	registry := models.PlayerSeasonStats
+	b.add(http.MethodGet, "/api/player-season-stat", b.list(&Operation{
+		OperationID: "listPlayerSeasonStats", Tags: []string{"player stats"},
+		Summary: "List player stats of a season",
+		Parameters: []*Parameter{
+			idQuery("uniqueTournamentID", "Tournament ID.", true),
+			idQuery("seasonID", "Season ID.", true),
+			idList("playerID", "Player IDs, comma separated. Defaults to every player of the season."),
+			statFields(registry, false),
+		},
+		Responses: b.responses(http.StatusOK, []models.PlayerSeasonStat{}, http.StatusBadRequest),
+	}))
+	b.add(http.MethodGet, "/api/player-season-stat/top-players", b.list(&Operation{
+		OperationID: "getTopPlayers", Tags: []string{"player stats"},
+		Summary: "Rank the players of a season by one stat",
+		Parameters: []*Parameter{
+			statField(registry),
+			idQuery("uniqueTournamentID", "Tournament ID.", true),
+			idQuery("seasonID", "Season ID.", true),
+			limit(),
+			query("position", "Only players of this position: defender, midfielder, forward or goalkeeper.", false,
+				&Schema{Type: "string", Enum: positions()}),
+		},
+		Responses: b.responses(http.StatusOK, []models.TopPlayerStatResult{}, http.StatusBadRequest),
+	}))
+	b.writes("/api/player-season-stat", "PlayerSeasonStats", "player season stats", models.PlayerSeasonStat{})
+}
+
+func (b *builder) exports() {
+	formats := make([]string, len(export.Formats))
+	ok := &Response{Description: "The table as a file download.", Content: map[string]*MediaType{}}
+	for i, f := range export.Formats {
+		formats[i] = f.Name
+		ok.Content[f.MIME] = &MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+	}
+	responses := b.responses(http.StatusOK, nil, http.StatusBadRequest, http.StatusNotFound)
+	responses["200"] = ok
+	b.add(http.MethodGet, "/api/export/{table}.{format}", &Operation{
+		OperationID: "exportTable", Tags: []string{"export"},
+		Summary: "Export a season of a stat table",
+		Description: "Every row of the table in one season, with a typed, nullable column per field and stat. " +
+			"Nested fields such as player.name and team.name are flattened as in the CSV format of the list endpoints. " +
+			"A season without rows is not found.",
+		Parameters: []*Parameter{
+			{Name: "table", In: "path", Required: true, Schema: &Schema{Type: "string", Enum: export.Tables}},
+			{Name: "format", In: "path", Required: true, Schema: &Schema{Type: "string", Enum: formats}},
+			idQuery("uniqueTournamentID", "Tournament ID.", true),
+			idQuery("seasonID", "Season ID.", true),
+		},
+		Responses: responses,
+	})
+}
+
+func (b *builder) meta() {
+	b.add(http.MethodGet, "/api/meta/stats", &Operation{
+		OperationID: "listStatEntities", Tags: []string{"meta"},
+		Summary:   "List the entities that have stats",
+		Responses: b.responses(http.StatusOK, []string{}),
+	})
+	b.add(http.MethodGet, "/api/meta/stats/{entity}", &Operation{
+		OperationID: "getStatEntity", Tags: []string{"meta"},
+		Summary: "Describe the stats of one entity",
+		Parameters: []*Parameter{{
+			Name: "entity", In: "path", Required: true,
+			Schema: &Schema{Type: "string", Enum: models.StatEntities()},
+		}},
+		Responses: b.responses(http.StatusOK, meta.EntityMeta{}, http.StatusNotFound),
+	})
+	b.add(http.MethodGet, "/api/meta/schema-check", &Operation{
+		OperationID: "getSchemaCheck", Tags: []string{"meta"},
+		Summary:   "Compare the stat registries with the database columns",
+		Responses: b.responses(http.StatusOK, database.SchemaReport{}),
+	})
+}
+
+func (b *builder) graphql() {
+	result := &Response{
+		Description: "The result of the query. Resolver errors are listed in errors, with a code extension.",
+		Content: jsonContent(&Schema{Type: "object", Properties: map[string]*Schema{
+			"data":   {Type: "object", Nullable: true},
+			"errors": {Type: "array", Items: &Schema{Type: "object"}},
+		}}),
+	}
+
+	get := b.responses(http.StatusOK, nil, http.StatusBadRequest)
+	get["200"] = result
+	b.add(http.MethodGet, "/graphql", &Operation{
+		OperationID: "getGraphQL", Tags: []string{"graphql"},
+		Summary: "Run a GraphQL query given in the URL",
+		Parameters: []*Parameter{
+			query("query", "The GraphQL document.", true, &Schema{Type: "string"}),
+			query("operationName", "The operation to run if the document has several.", false, &Schema{Type: "string"}),
+			query("variables", "The variables, as a JSON object.", false, &Schema{Type: "string"}),
+		},
+		Responses: get,
+	})
+
+	post := b.responses(http.StatusOK, nil, http.StatusBadRequest)
+	post["200"] = result
+	b.add(http.MethodPost, "/graphql", &Operation{
+		OperationID: "postGraphQL", Tags: []string{"graphql"},
+		Summary:     "Run a GraphQL query",
+		RequestBody: jsonBody(b.schemas.of(graph.Request{})),
+		Responses:   post,
+	})
+}
+
+// writes adds the POST and PUT operations of a stat resource, which both
+// take one row or an array of rows.
+func (b *builder) writes(path, name, noun string, row any) {
+	rows := b.schemas.of(row)
+	many := &Schema{Type: "array", Items: rows}
+	body := jsonBody(&Schema{OneOf: []*Schema{rows, many}})
+
+	created := b.responses(http.StatusCreated, nil, http.StatusBadRequest, http.StatusConflict)
+	created["201"].Content = jsonContent(many)
+	b.add(http.MethodPost, path, &Operation{
+		OperationID: "create" + name, Tags: []string{tagOf(path)},
+		Summary:     "Create " + noun,
+		Description: "Fails with 409 when any row already exists; no row is written then.",
+		RequestBody: body,
+		Responses:   created,
+	})
+
+	saved := b.responses(http.StatusOK, nil, http.StatusBadRequest)
+	saved["200"].Content = jsonContent(many)
+	b.add(http.MethodPut, path, &Operation{
+		OperationID: "upsert" + name, Tags: []string{tagOf(path)},
+		Summary:     "Create or update " + noun,
+		Description: "Rows whose keys exist are updated: the stats they carry are overwritten and every other stat is kept.",
+		RequestBody: body,
+		Responses:   saved,
+	})
+}
+
+// add registers op, adding the responses and security that every
+// operation under its path shares.
+func (b *builder) add(method, path string, op *Operation) {
+	if strings.HasPrefix(path, "/api/") && !unguarded[path] || path == "/graphql" {
+		op.Responses["429"] = b.problem("Rate limit or daily quota exceeded.")
+		op.Responses["500"] = b.problem("Internal error.")
+		if method == http.MethodGet || path == "/graphql" {
+			// Reads may be anonymous, in which case the empty requirement
+			// applies.
+			op.Security = []map[string][]string{{}, {BearerAuth: {}}, {APIKeyAuth: {}}}
+			op.Responses["401"] = b.problem("Invalid or revoked API key, or anonymous reads are disabled.")
+		} else {
+			op.Security = []map[string][]string{{BearerAuth: {}}, {APIKeyAuth: {}}}
+			op.Responses["401"] = b.problem("Missing, invalid or revoked API key.")
+			op.Responses["403"] = b.problem("The API key lacks the write scope.")
+		}
+	}
+
+	item, ok := b.doc.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		b.doc.Paths[path] = item
+	}
+	(*item)[strings.ToLower(method)] = op
+}
+
+// responses returns a success response of status with a body shaped like
+// v, if v is not nil, and a problem response for each error status.
+func (b *builder) responses(status int, v any, errors ...int) map[string]*Response {
+	success := &Response{Description: http.StatusText(status)}
+	if v != nil {
+		success.Content = jsonContent(b.schemas.of(v))
+	}
+	responses := map[string]*Response{strconv.Itoa(status): success}
+	for _, code := range errors {
+		responses[strconv.Itoa(code)] = b.problem(http.StatusText(code))
+	}
+	return responses
+}
+
+// list documents the formats that tabular.Send offers besides JSON on
+// op, a list endpoint.
+func (b *builder) list(op *Operation) *Operation {
+	names := make([]string, len(tabular.Formats))
+	for i, f := range tabular.Formats {
+		names[i] = f.Name
+	}
+	op.Parameters = append(op.Parameters, query("format",
+		"Response format, overriding the Accept header. CSV and XLSX have a column per field, nested fields such as team.name and stats included; the XLSX workbook describes the stats on a second sheet. NDJSON has one JSON row per line.",
+		false, &Schema{Type: "string", Enum: names}))
+
+	ok := op.Responses["200"]
+	rows := ok.Content[mediaJSON].Schema
+	ok.Content[tabular.MIMECSV] = &MediaType{Schema: &Schema{Type: "string"}}
+	ok.Content[tabular.MIMENDJSON] = &MediaType{Schema: rows.Items}
+	ok.Content[tabular.MIMEXLSX] = &MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+	if _, ok := op.Responses["400"]; !ok {
+		op.Responses["400"] = b.problem(http.StatusText(http.StatusBadRequest))
+	}
+	return op
+}
+
+func (b *builder) problem(description string) *Response {
+	return &Response{
+		Description: description,
+		Content:     map[string]*MediaType{mediaProblem: {Schema: b.schemas.of(middleware.Problem{})}},
+	}
+}
+
+func jsonContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{mediaJSON: {Schema: schema}}
+}
+
+func jsonBody(schema *Schema) *RequestBody {
+	return &RequestBody{Required: true, Content: jsonContent(schema)}
+}
+
+func tagOf(path string) string {
+	if strings.HasPrefix(path, "/api/team") {
+		return "team stats"
+	}
+	return "player stats"
+}
+
+func pathID(name, description string) *Parameter {
+	return &Parameter{Name: name, In: "path", Description: description, Required: true, Schema: id()}
+}
+
+func query(name, description string, required bool, schema *Schema) *Parameter {
+	return &Parameter{Name: name, In: "query", Description: description, Required: required, Schema: schema}
+}
+
+func idQuery(name, description string, required bool) *Parameter {
+	return query(name, description, required, id())
+}
+
+// idList is a comma separated list of IDs, such as teamID=2816,2829.
+func idList(name, description string) *Parameter {
+	p := query(name, description, false, &Schema{Type: "array", Items: id()})
+	p.Style, p.Explode = "form", noExplode
+	return p
+}
+
+// statFields selects stats of registry by name, comma separated.
+func statFields(registry *models.StatRegistry, required bool) *Parameter {
+	p := query("statFields",
+		"Stats to return, comma separated; see /api/meta/stats/"+registry.Entity+". Defaults to every stat.",
+		required, &Schema{Type: "array", Items: &Schema{Type: "string", Enum: registry.Fields()}})
+	p.Style, p.Explode = "form", noExplode
+	return p
+}
+
+// statField selects the one stat of registry that a ranking is by.
+func statField(registry *models.StatRegistry) *Parameter {
+	return query("statFields", "The stat to rank by; see /api/meta/stats/"+registry.Entity+".", true,
+		&Schema{Type: "string", Enum: registry.Fields()})
+}
+
+func limit() *Parameter {
+	zero := 0.0
+	return query("limit", "Maximum number of results; 0 or absent means no limit.", false,
+		&Schema{Type: "integer", Minimum: &zero})
+}
+
+func id() *Schema {
+	one := 1.0
+	return &Schema{Type: "integer", Format: "int64", Minimum: &one}
+}
+
+func positions() []string {
+	positions := make([]string, 0, len(models.ValidPositions))
+	for p := range models.ValidPositions {
+		positions = append(positions, p)
+	}
+	sort.Strings(positions)
+	return positions
+}
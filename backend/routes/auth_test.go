@@ -0,0 +1,179 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/plinphon/StatsBanger/backend/auth"
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/container"
+	"github.com/plinphon/StatsBanger/backend/fixture"
+)
+
+func newAuthTestApp(t *testing.T, cfg *config.Config) (*fiber.App, *container.Container) {
+	t.Helper()
+	c := container.NewWithDB(cfg, fixture.Open(t))
+	app := fiber.New(AppConfig(cfg))
+	SetupRoutes(app, c)
+	return app, c
+}
+
+// getWithKey performs a GET presenting key in the X-API-Key header.
+func getWithKey(t *testing.T, app *fiber.App, path, key string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	app, c := newAuthTestApp(t, config.Default())
+	const reader = "sb_reader_cmVhZA"
+	issueKey(t, c, &auth.Key{ID: "reader", Name: "reader", Scopes: auth.ScopeRead}, reader)
+
+	match := `{"id": 1, "uniqueTournamentId": 8, "seasonId": 52376, "homeTeamId": 2816, "awayTeamId": 2825}`
+	if status, _ := send(t, app, http.MethodPost, "/api/match", match, reader); status != http.StatusForbidden {
+		t.Errorf("write with read key: status %d, want 403", status)
+	}
+	if resp := getWithKey(t, app, "/api/match/11368591", reader); resp.StatusCode != http.StatusOK {
+		t.Errorf("read with read key: status %d, want 200", resp.StatusCode)
+	}
+	if resp := getWithKey(t, app, "/api/match/11368591", "sb_reader_d3Jvbmc"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("read with wrong secret: status %d, want 401", resp.StatusCode)
+	}
+
+	const revoked = "sb_revoked_cmV2b2tlZA"
+	issueKey(t, c, &auth.Key{ID: "revoked", Name: "revoked", Scopes: auth.ScopeWrite}, revoked)
+	if err := c.Keys.Revoke(context.Background(), "revoked"); err != nil {
+		t.Fatal(err)
+	}
+	if resp := getWithKey(t, app, "/api/match/11368591", revoked); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("revoked key: status %d, want 401", resp.StatusCode)
+	}
+	if err := c.Keys.Revoke(context.Background(), "nosuchkey"); err == nil {
+		t.Error("revoking an unknown key succeeded")
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	cfg := config.Default()
+	cfg.AnonymousRateLimit = 2
+	app, c := newAuthTestApp(t, cfg)
+
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		resp := getWithKey(t, app, "/api/team/2825", "")
+		if resp.StatusCode != want {
+			t.Fatalf("anonymous request %d: status %d, want %d", i+1, resp.StatusCode, want)
+		}
+		if want == http.StatusTooManyRequests && resp.Header.Get("Retry-After") == "" {
+			t.Error("429 without Retry-After")
+		}
+	}
+	// Health checks are never limited.
+	if resp := getWithKey(t, app, "/healthz", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("healthz after the anonymous limit: status %d", resp.StatusCode)
+	}
+
+	const limited = "sb_limited_bGltaXRlZA"
+	issueKey(t, c, &auth.Key{ID: "limited", Name: "limited", Scopes: auth.ScopeRead, RateLimit: 3}, limited)
+	for i := 1; i <= 4; i++ {
+		resp := getWithKey(t, app, "/api/team/2825", limited)
+		if i <= 3 && (resp.StatusCode != http.StatusOK || resp.Header.Get("X-RateLimit-Limit") != "3") {
+			t.Fatalf("keyed request %d: status %d, limit header %q", i, resp.StatusCode, resp.Header.Get("X-RateLimit-Limit"))
+		}
+		if i == 4 && resp.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("keyed request 4: status %d, want 429", resp.StatusCode)
+		}
+	}
+
+	const quota = "sb_quota_cXVvdGE"
+	issueKey(t, c, &auth.Key{ID: "quota", Name: "quota", Scopes: auth.ScopeRead, DailyQuota: 1}, quota)
+	if resp := getWithKey(t, app, "/api/team/2825", quota); resp.StatusCode != http.StatusOK {
+		t.Errorf("first request within quota: status %d", resp.StatusCode)
+	}
+	if resp := getWithKey(t, app, "/api/team/2825", quota); resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("request over quota: status %d, want 429", resp.StatusCode)
+	}
+
+	// Requests over the quota leave the per-minute limit alone.
+	const both = "sb_both_Ym90aA"
+	issueKey(t, c, &auth.Key{ID: "both", Name: "both", Scopes: auth.ScopeRead, RateLimit: 2, DailyQuota: 1}, both)
+	for i, want := range []string{"1", "1", "1"} {
+		resp := getWithKey(t, app, "/api/team/2825", both)
+		if got := resp.Header.Get("X-RateLimit-Remaining"); got != want {
+			t.Errorf("request %d with a quota of 1: %s requests left this minute, want %s", i+1, got, want)
+		}
+	}
+}
+
+func TestTrustedProxies(t *testing.T) {
+	get := func(app *fiber.App, client string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/team/2825", nil)
+		req.Header.Set("X-Forwarded-For", client)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	cfg := config.Default()
+	cfg.AnonymousRateLimit = 1
+	// app.Test requests come from 0.0.0.0.
+	cfg.TrustedProxies = []string{"0.0.0.0/8"}
+	app, _ := newAuthTestApp(t, cfg)
+	for i, want := range []struct {
+		client string
+		status int
+	}{
+		{"203.0.113.1", http.StatusOK},
+		{"203.0.113.1", http.StatusTooManyRequests},
+		{"203.0.113.2", http.StatusOK},
+	} {
+		if status := get(app, want.client); status != want.status {
+			t.Errorf("request %d from %s behind a trusted proxy: status %d, want %d", i+1, want.client, status, want.status)
+		}
+	}
+
+	// Without trusted proxies the header is ignored, so both clients share
+	// the limit of the proxy.
+	cfg = config.Default()
+	cfg.AnonymousRateLimit = 1
+	app, _ = newAuthTestApp(t, cfg)
+	get(app, "203.0.113.1")
+	if status := get(app, "203.0.113.2"); status != http.StatusTooManyRequests {
+		t.Errorf("second client without trusted proxies: status %d, want 429", status)
+	}
+}
+
+func TestAnonymousReadsDisabled(t *testing.T) {
+	cfg := config.Default()
+	cfg.AnonymousReads = false
+	app, c := newAuthTestApp(t, cfg)
+
+	if resp := getWithKey(t, app, "/api/team/2825", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("anonymous read: status %d, want 401", resp.StatusCode)
+	}
+	for _, path := range []string{"/api/openapi.json", "/api/docs", "/api/docs/swagger-ui.css"} {
+		if resp := getWithKey(t, app, path, ""); resp.StatusCode != http.StatusOK {
+			t.Errorf("anonymous %s: status %d, want 200", path, resp.StatusCode)
+		}
+	}
+	const writer = "sb_writer_d3JpdGVy"
+	issueKey(t, c, &auth.Key{ID: "writer", Name: "writer", Scopes: auth.ScopeWrite}, writer)
+	if resp := getWithKey(t, app, "/api/team/2825", writer); resp.StatusCode != http.StatusOK {
+		t.Errorf("read with write key: status %d, want 200", resp.StatusCode)
+	}
+}
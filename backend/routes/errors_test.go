@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/plinphon/StatsBanger/backend/middleware"
+)
+
+func TestErrorResponses(t *testing.T) {
+	app := newTestApp(t)
+
+	duplicate := `{"id": 11368591, "uniqueTournamentId": 8, "seasonId": 52376, "homeTeamId": 2825, "awayTeamId": 2816}`
+	if status, _ := send(t, app, http.MethodPost, "/api/match", duplicate, testAPIKey); status != http.StatusConflict {
+		t.Errorf("duplicate match: status %d, want 409", status)
+	}
+
+	tests := []struct {
+		path   string
+		status int
+		detail string
+	}{
+		{"/api/match/1", http.StatusNotFound, "match not found"},
+		{"/api/team/1", http.StatusNotFound, "team not found"},
+		{"/api/player/1", http.StatusNotFound, "player not found"},
+		{"/api/player-match-stat/player/991011/match/11368620", http.StatusNotFound, "player match stat not found"},
+		{"/api/team-match-stat?matchID=11368620&teamID=2829", http.StatusNotFound, "team match stat not found"},
+		{"/api/player-match-stat?matchID=11368591&statFields=rating,bogus", http.StatusBadRequest, "invalid stat field: bogus"},
+		{"/api/player-season-stat/top-players?statFields=bogus&uniqueTournamentID=8&seasonID=52376", http.StatusBadRequest, "invalid stat field: bogus"},
+		{"/api/player-season-stat/top-players?statFields=goals&uniqueTournamentID=8&seasonID=52376&position=X", http.StatusBadRequest, "invalid position filter: X"},
+		{"/api/match/abc", http.StatusBadRequest, "Invalid match ID"},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil), -1)
+		if err != nil {
+			t.Fatalf("GET %s: %v", tt.path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != tt.status {
+			t.Errorf("GET %s: status %d, want %d (%s)", tt.path, resp.StatusCode, tt.status, body)
+			continue
+		}
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/problem+json") {
+			t.Errorf("GET %s: content type %q", tt.path, ct)
+		}
+		var problem middleware.Problem
+		if err := json.Unmarshal(body, &problem); err != nil {
+			t.Fatalf("GET %s: decode %s: %v", tt.path, body, err)
+		}
+		if problem.Status != tt.status || problem.Title != http.StatusText(tt.status) ||
+			problem.Detail != tt.detail || problem.Instance != tt.path {
+			t.Errorf("GET %s: got %+v", tt.path, problem)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+)
+
+func TestExportRoutes(t *testing.T) {
+	app := newTestApp(t)
+
+	formats := []struct{ ext, mime, magic string }{
+		{"parquet", tabular.MIMEParquet, "PAR1"},
+		{"arrow", tabular.MIMEArrow, "ARROW1"},
+	}
+	for _, table := range []string{"player_stat", "team_stat", "player_match_stat", "team_match_stat"} {
+		for _, f := range formats {
+			path := "/api/export/" + table + "." + f.ext + "?uniqueTournamentID=8&seasonID=52376"
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var body bytes.Buffer
+			body.ReadFrom(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("GET %s: status %d (%s)", path, resp.StatusCode, body.Bytes())
+				continue
+			}
+			if got := resp.Header.Get("Content-Type"); got != f.mime {
+				t.Errorf("GET %s: content type %q", path, got)
+			}
+			if got, want := resp.Header.Get("Content-Disposition"), `attachment; filename="`+table+"."+f.ext+`"`; got != want {
+				t.Errorf("GET %s: content disposition %q, want %q", path, got, want)
+			}
+			data := body.Bytes()
+			if !bytes.HasPrefix(data, []byte(f.magic)) || !bytes.HasSuffix(data, []byte(f.magic)) {
+				t.Errorf("GET %s: not a %s file", path, f.ext)
+			}
+			// The names are joined in as columns of their own.
+			for _, name := range []string{"Real Madrid", "Athletic Bilbao"} {
+				if !bytes.Contains(data, []byte(name)) {
+					t.Errorf("GET %s: no %q", path, name)
+				}
+			}
+		}
+	}
+
+	for path, want := range map[string]int{
+		"/api/export/player_stat.parquet?uniqueTournamentID=8&seasonID=1":   http.StatusNotFound,
+		"/api/export/players.parquet?uniqueTournamentID=8&seasonID=52376":   http.StatusBadRequest,
+		"/api/export/player_stat.csv?uniqueTournamentID=8&seasonID=52376":   http.StatusBadRequest,
+		"/api/export/player_stat.arrow?uniqueTournamentID=8":                http.StatusBadRequest,
+		"/api/export/player_stat.arrow?uniqueTournamentID=0&seasonID=52376": http.StatusBadRequest,
+	} {
+		if status := get(t, app, path, nil); status != want {
+			t.Errorf("GET %s: status %d, want %d", path, status, want)
+		}
+	}
+}
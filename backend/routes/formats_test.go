@@ -0,0 +1,148 @@
+package routes
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// fetch performs a GET with the given Accept header, if any, and returns
+// a 200 response's content type and body.
+func fetch(t *testing.T, app *fiber.App, path, accept string) (string, []byte) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: status %d (%s)", path, resp.StatusCode, body)
+	}
+	return resp.Header.Get("Content-Type"), body
+}
+
+func TestCSVFormat(t *testing.T) {
+	app := newTestApp(t)
+
+	contentType, body := fetch(t, app, "/api/player-season-stat/top-players?statFields=goals&uniqueTournamentID=8&seasonID=52376&limit=2", tabular.MIMECSV)
+	if !strings.HasPrefix(contentType, tabular.MIMECSV) {
+		t.Errorf("content type %q", contentType)
+	}
+	want := "playerId,playerName,position,statValue\n991011,Jude Bellingham,M,19\n868812,Vinícius Júnior,F,15\n"
+	if string(body) != want {
+		t.Errorf("top players:\n%s\nwant\n%s", body, want)
+	}
+
+	// Stat columns follow the registry, whatever order they are asked in.
+	_, body = fetch(t, app, "/api/player-season-stat?uniqueTournamentID=8&seasonID=52376&playerID=991011&statFields=rating,goals&format=csv", "")
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want a header and one row", len(records))
+	}
+	header, row := records[0], records[1]
+	if n := len(header); header[n-2] != "goals" || header[n-1] != "rating" {
+		t.Errorf("stat columns %v, want goals then rating", header[n-2:])
+	}
+	column := make(map[string]string)
+	for i, name := range header {
+		column[name] = row[i]
+	}
+	if column["player.name"] != "Jude Bellingham" || column["team.name"] != "Real Madrid" || column["goals"] != "19" {
+		t.Errorf("row %v", column)
+	}
+
+	// ?format= wins over Accept, and JSON stays the default for browsers.
+	if contentType, _ := fetch(t, app, "/api/team?name=real&format=json", tabular.MIMECSV); contentType != fiber.MIMEApplicationJSON {
+		t.Errorf("format=json: content type %q", contentType)
+	}
+	if contentType, _ := fetch(t, app, "/api/team?name=real", "text/html,*/*;q=0.8"); contentType != fiber.MIMEApplicationJSON {
+		t.Errorf("browser Accept: content type %q", contentType)
+	}
+	if status := get(t, app, "/api/team?name=real&format=pdf", nil); status != http.StatusBadRequest {
+		t.Errorf("format=pdf: status %d, want 400", status)
+	}
+}
+
+func TestNDJSONFormat(t *testing.T) {
+	app := newTestApp(t)
+
+	contentType, body := fetch(t, app, "/api/player-match-stat?matchID=11369286&statFields=rating", tabular.MIMENDJSON)
+	if contentType != tabular.MIMENDJSON {
+		t.Errorf("content type %q", contentType)
+	}
+	var rows []models.PlayerMatchStat
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		var row models.PlayerMatchStat
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) != 6 {
+		t.Fatalf("got %d lines, want 6", len(rows))
+	}
+	if rows[0].MatchId != 11369286 || rows[0].Stats["rating"] == nil {
+		t.Errorf("first row %+v", rows[0])
+	}
+}
+
+func TestXLSXFormat(t *testing.T) {
+	app := newTestApp(t)
+
+	_, body := fetch(t, app, "/api/team-season-stat?uniqueTournamentID=8&seasonID=52376&statFields=avg_rating,goals_scored&format=xlsx", "")
+	f, err := excelize.OpenReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if sheets := f.GetSheetList(); !reflect.DeepEqual(sheets, []string{"team-season-stats", "stats"}) {
+		t.Fatalf("sheets %v", sheets)
+	}
+	rows, err := f.GetRows("team-season-stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("got %d rows, want a header and 3 teams", len(rows))
+	}
+	if header := rows[0]; header[len(header)-2] != "goals_scored" || header[len(header)-1] != "avg_rating" {
+		t.Errorf("header %v", header)
+	}
+
+	stats, err := f.GetRows("stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"field", "label", "category", "unit", "per90", "direction", "aggregation"},
+		{"goals_scored", "Goals scored", "attacking", "count", "TRUE", "desc", "sum"},
+		{"avg_rating", "Average rating", "general", "rating", "FALSE", "desc", "avg"},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("stats sheet %v, want %v", stats, want)
+	}
+}
@@ -0,0 +1,304 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/auth"
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/container"
+	"github.com/plinphon/StatsBanger/backend/fixture"
+	"github.com/plinphon/StatsBanger/backend/middleware"
+)
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// graphQL posts query to app and decodes the data of the response into
+// out, failing on any error.
+func graphQL(t *testing.T, app *fiber.App, query string, out any) graphQLResponse {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d: %s", resp.StatusCode, raw)
+	}
+
+	var result graphQLResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("decode %s: %v", raw, err)
+	}
+	if out != nil {
+		if len(result.Errors) > 0 {
+			t.Fatalf("errors: %s", raw)
+		}
+		if err := json.Unmarshal(result.Data, out); err != nil {
+			t.Fatalf("decode data %s: %v", result.Data, err)
+		}
+	}
+	return result
+}
+
+func TestGraphQLMatch(t *testing.T) {
+	app := newTestApp(t)
+
+	var data struct {
+		Match struct {
+			ID        int
+			HomeScore *int
+			HomeTeam  struct{ Name string }
+			AwayTeam  struct{ Name string }
+			TeamStats []struct {
+				Team       struct{ ID int }
+				Possession *float64
+			}
+			PlayerStats []struct {
+				Player struct{ Name string }
+				Stats  []struct {
+					Field string
+					Value *float64
+				}
+			}
+		}
+		Missing *struct{ ID int }
+	}
+	graphQL(t, app, fmt.Sprintf(`{
+		match(id: %d) {
+			id homeScore
+			homeTeam { name }
+			awayTeam { name }
+			teamStats { team { id } possession: stat(field: "ball_possession") }
+			playerStats { player { name } stats(fields: ["rating", "saves"]) { field value } }
+		}
+		missing: match(id: 1) { id }
+	}`, fixture.AthleticRealMadridID), &data)
+
+	m := data.Match
+	if m.ID != fixture.AthleticRealMadridID || m.HomeTeam.Name != "Athletic Bilbao" || m.AwayTeam.Name != "Real Madrid" {
+		t.Errorf("match = %+v", m)
+	}
+	if len(m.TeamStats) != 2 || m.TeamStats[0].Possession == nil {
+		t.Errorf("team stats = %+v", m.TeamStats)
+	}
+	if len(m.PlayerStats) != 6 {
+		t.Fatalf("%d player stats, want 6", len(m.PlayerStats))
+	}
+	for _, s := range m.PlayerStats {
+		if len(s.Stats) != 2 || s.Stats[0].Field != "rating" || s.Stats[0].Value == nil || s.Stats[1].Field != "saves" {
+			t.Errorf("%s: stats = %+v", s.Player.Name, s.Stats)
+		}
+	}
+	if data.Missing != nil {
+		t.Errorf("match(id: 1) = %+v, want null", data.Missing)
+	}
+}
+
+func TestGraphQLBatching(t *testing.T) {
+	c := container.NewWithDB(config.Default(), fixture.Open(t))
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	SetupRoutes(app, c)
+
+	var data struct {
+		Team struct {
+			MatchStats []struct {
+				Match struct {
+					TeamStats   []struct{ Team struct{ Name string } }
+					PlayerStats []struct{ Rating *float64 }
+				}
+			}
+		}
+	}
+	graphQL(t, app, fmt.Sprintf(`{
+		team(id: %d) {
+			matchStats {
+				match {
+					teamStats { team { name } }
+					playerStats { rating: stat(field: "rating") }
+				}
+			}
+		}
+	}`, fixture.AthleticID), &data)
+	if n := len(data.Team.MatchStats); n != 3 {
+		t.Fatalf("%d matches, want 3", n)
+	}
+
+	const admin = "sb_admin_YWRtaW4"
+	issueKey(t, c, &auth.Key{ID: "admin", Name: "prometheus", Scopes: auth.ScopeAdmin}, admin)
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("X-API-Key", admin)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	// One query for the team's match stats and one per nested list,
+	// however many matches there are.
+	for _, series := range []string{
+		`statsbanger_db_query_duration_seconds_count{operation="row",repository="team_match_stats"} 2`,
+		`statsbanger_db_query_duration_seconds_count{operation="row",repository="player_match_stats"} 1`,
+	} {
+		if !strings.Contains(string(body), series) {
+			t.Errorf("missing %s", series)
+		}
+	}
+}
+
+// The nested stat lists select only the stats the query asks for, and
+// aliased stat fields asking for others still resolve.
+func TestGraphQLStatColumns(t *testing.T) {
+	db := fixture.Open(t)
+	var queries []string
+	db.Callback().Row().After("gorm:row").Register("test:sql", func(tx *gorm.DB) {
+		queries = append(queries, tx.Statement.SQL.String())
+	})
+	c := container.NewWithDB(config.Default(), db)
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	SetupRoutes(app, c)
+
+	var data struct {
+		Match struct {
+			PlayerStats []struct {
+				Player  struct{ ID int }
+				Rating  *float64
+				Goals   *float64
+				Minutes []struct{ Value *float64 }
+			}
+		}
+	}
+	graphQL(t, app, fmt.Sprintf(`{
+		match(id: %d) {
+			playerStats {
+				player { id }
+				rating: stat(field: "rating")
+				goals: stat(field: "goals")
+				minutes: stats(fields: ["minutes_played"]) { value }
+			}
+		}
+	}`, fixture.AthleticRealMadridID), &data)
+
+	stats := data.Match.PlayerStats
+	if len(stats) != 6 {
+		t.Fatalf("%d player stats, want 6", len(stats))
+	}
+	for _, s := range stats {
+		if s.Rating == nil || s.Goals == nil || len(s.Minutes) != 1 || s.Minutes[0].Value == nil {
+			t.Errorf("player %d: %+v", s.Player.ID, s)
+		}
+	}
+	for _, s := range stats {
+		if s.Player.ID == fixture.BellinghamID && (*s.Goals != 1 || *s.Rating != 8.4) {
+			t.Errorf("Bellingham: goals %v, rating %v", *s.Goals, *s.Rating)
+		}
+	}
+
+	selected := 0
+	for _, q := range queries {
+		if !strings.Contains(q, "FROM `player_match_stat`") {
+			continue
+		}
+		selected++
+		if strings.Contains(q, "saves") {
+			t.Errorf("query selects unrequested stats: %s", q)
+		}
+	}
+	if selected == 0 {
+		t.Error("no player_match_stat queries")
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	app := newTestApp(t)
+
+	result := graphQL(t, app, fmt.Sprintf(`{
+		match(id: %d) { teamStats { stat(field: "bogus") } }
+	}`, fixture.AthleticBetisID), nil)
+	// One error for the field, not one per stat row.
+	if len(result.Errors) != 1 {
+		t.Fatalf("%d errors, want 1: %+v", len(result.Errors), result.Errors)
+	}
+	if e := result.Errors[0]; e.Message != "invalid stat field: bogus" || e.Extensions["code"] != "BAD_USER_INPUT" {
+		t.Errorf("error = %+v", e)
+	}
+
+	result = graphQL(t, app, `{ nosuchfield }`, nil)
+	if len(result.Errors) != 1 || result.Data != nil {
+		t.Errorf("invalid query: %+v", result)
+	}
+
+	if status, _ := send(t, app, http.MethodPost, "/graphql", `{"query": ""}`, ""); status != http.StatusBadRequest {
+		t.Errorf("empty query: status %d, want 400", status)
+	}
+}
+
+func TestGraphQLRowBudget(t *testing.T) {
+	// Every level of nesting multiplies the stat rows, within the depth
+	// limit.
+	query := fmt.Sprintf(`{
+		team(id: %d) { matchStats { match { playerStats { player { matchStats { match { id } } } } } } }
+	}`, fixture.RealMadridID)
+
+	app, _ := newAuthTestApp(t, config.Default())
+	var data struct {
+		Team struct {
+			MatchStats []struct {
+				Match struct {
+					PlayerStats []struct {
+						Player struct {
+							MatchStats []struct{ Match struct{ ID int } }
+						}
+					}
+				}
+			}
+		}
+	}
+	graphQL(t, app, query, &data)
+	rows := 0
+	for _, teamStat := range data.Team.MatchStats {
+		rows++
+		for _, playerStat := range teamStat.Match.PlayerStats {
+			rows += 1 + len(playerStat.Player.MatchStats)
+		}
+	}
+	if rows < 10 {
+		t.Fatalf("the query resolves %d stat rows, too few to test the budget", rows)
+	}
+
+	cfg := config.Default()
+	cfg.GraphQLMaxRows = rows - 1
+	app, _ = newAuthTestApp(t, cfg)
+	result := graphQL(t, app, query, nil)
+	if len(result.Errors) != 1 || result.Data != nil {
+		t.Fatalf("query over the budget: %d errors, data %s", len(result.Errors), result.Data)
+	}
+	if e := result.Errors[0]; e.Extensions["code"] != "BAD_USER_INPUT" || !strings.Contains(e.Message, "more than") {
+		t.Errorf("error = %+v", e)
+	}
+
+	cfg.GraphQLMaxRows = rows
+	app, _ = newAuthTestApp(t, cfg)
+	graphQL(t, app, query, &data)
+}
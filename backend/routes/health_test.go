@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/plinphon/StatsBanger/backend/api/health"
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/container"
+	"github.com/plinphon/StatsBanger/backend/fixture"
+	"github.com/plinphon/StatsBanger/backend/middleware"
+)
+
+func TestHealthRoutes(t *testing.T) {
+	db := fixture.Open(t)
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	SetupRoutes(app, container.NewWithDB(config.Default(), db))
+
+	var status health.Status
+	mustGet(t, app, "/healthz", &status)
+	if status.Status != "ok" {
+		t.Errorf("healthz: got %+v", status)
+	}
+	mustGet(t, app, "/readyz", &status)
+	if status.Status != "ready" {
+		t.Errorf("readyz: got %+v", status)
+	}
+
+	if err := db.Exec("DROP TABLE team_stat").Error; err != nil {
+		t.Fatal(err)
+	}
+	if code := get(t, app, "/readyz", nil); code != http.StatusServiceUnavailable {
+		t.Errorf("readyz without team_stat: status %d, want 503", code)
+	}
+	if code := get(t, app, "/healthz", nil); code != http.StatusOK {
+		t.Errorf("healthz without team_stat: status %d, want 200", code)
+	}
+}
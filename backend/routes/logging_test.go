@@ -0,0 +1,91 @@
+package routes
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/container"
+	"github.com/plinphon/StatsBanger/backend/fixture"
+	"github.com/plinphon/StatsBanger/backend/logging"
+	"github.com/plinphon/StatsBanger/backend/middleware"
+)
+
+func TestRequestLogging(t *testing.T) {
+	cfg := config.Default()
+	cfg.LogFormat = config.LogFormatJSON
+	var out bytes.Buffer
+	logger, err := logging.New(cfg, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every query counts as slow.
+	db := fixture.Open(t).Session(&gorm.Session{Logger: logging.NewGormLogger(logger, time.Nanosecond)})
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(middleware.RequestID(), middleware.AccessLog(logger))
+	SetupRoutes(app, container.NewWithDB(cfg, db))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/match/1", nil)
+	req.Header.Set(middleware.HeaderRequestID, "req-42")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get(middleware.HeaderRequestID); got != "req-42" {
+		t.Errorf("echoed request ID = %q, want req-42", got)
+	}
+
+	var access, slow map[string]any
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("decode %s: %v", scanner.Bytes(), err)
+		}
+		switch record["msg"] {
+		case "request":
+			access = record
+		case "slow query":
+			slow = record
+		}
+	}
+	if access == nil || access["request_id"] != "req-42" || access["status"] != float64(http.StatusNotFound) ||
+		access["path"] != "/api/match/1" || access["latency"] == nil {
+		t.Errorf("access log: got %v", access)
+	}
+	if slow == nil || slow["request_id"] != "req-42" || slow["sql"] == nil {
+		t.Errorf("slow query log: got %v", slow)
+	}
+
+	// Without the header a new ID is generated.
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/api/match/11368591", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get(middleware.HeaderRequestID) == "" {
+		t.Error("no request ID generated")
+	}
+
+	// Only warnings and errors pass the warn level.
+	cfg.LogLevel = "warn"
+	quiet, err := logging.New(cfg, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quiet.Enabled(context.Background(), slog.LevelInfo) || !quiet.Enabled(context.Background(), slog.LevelWarn) {
+		t.Error("warn level lets info records through")
+	}
+}
@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/plinphon/StatsBanger/backend/api/tabular"
+	"github.com/plinphon/StatsBanger/backend/fixture"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+func TestListMatches(t *testing.T) {
+	app := newTestApp(t)
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"", []int{fixture.AthleticRealMadridID, fixture.AthleticBetisID, fixture.RealMadridAthleticID, fixture.RealMadridBetisID}},
+		{"?order=desc&limit=2", []int{fixture.RealMadridBetisID, fixture.RealMadridAthleticID}},
+		{"?limit=2&offset=3", []int{fixture.RealMadridBetisID}},
+		{"?uniqueTournamentID=8&seasonID=52376&matchday=30", []int{fixture.RealMadridAthleticID}},
+		{"?teamID=2816", []int{fixture.AthleticBetisID, fixture.RealMadridBetisID}},
+		{"?teamID=2829&side=home", []int{fixture.RealMadridAthleticID, fixture.RealMadridBetisID}},
+		{"?teamID=2829&side=away", []int{fixture.AthleticRealMadridID}},
+		{"?played=true&order=desc", []int{fixture.RealMadridAthleticID, fixture.AthleticBetisID, fixture.AthleticRealMadridID}},
+		{"?played=false", []int{fixture.RealMadridBetisID}},
+		{"?from=2023-08-28&to=2024-03-31", []int{fixture.RealMadridAthleticID}},
+		{"?from=2023-08-27T21:30:09Z&to=2023-08-27T21:30:09Z", []int{fixture.AthleticBetisID}},
+		{"?seasonID=1", []int{}},
+	}
+	for _, tt := range tests {
+		path := "/api/match" + tt.query
+		var matches []models.Match
+		mustGet(t, app, path, &matches)
+		ids := []int{}
+		for _, m := range matches {
+			ids = append(ids, m.Id)
+			if m.HomeTeam.TeamName == "" || m.AwayTeam.TeamName == "" {
+				t.Errorf("GET %s: match %d without its teams", path, m.Id)
+			}
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Errorf("GET %s: matches %v, want %v", path, ids, tt.want)
+		}
+	}
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/match?limit=1", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get(tabular.HeaderTotalCount); got != "4" {
+		t.Errorf("GET /api/match?limit=1: %s %q, want 4", tabular.HeaderTotalCount, got)
+	}
+
+	for _, query := range []string{
+		"?teamID=abc",
+		"?matchday=-1",
+		"?side=home",
+		"?teamID=2829&side=both",
+		"?from=yesterday",
+		"?from=2024-01-02&to=2024-01-01",
+		"?played=maybe",
+		"?order=up",
+		"?limit=501",
+		"?offset=-1",
+	} {
+		if status := get(t, app, "/api/match"+query, nil); status != http.StatusBadRequest {
+			t.Errorf("GET /api/match%s: status %d, want 400", query, status)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/plinphon/StatsBanger/backend/auth"
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/container"
+	"github.com/plinphon/StatsBanger/backend/fixture"
+	"github.com/plinphon/StatsBanger/backend/middleware"
+)
+
+func TestMetricsRoute(t *testing.T) {
+	c := container.NewWithDB(config.Default(), fixture.Open(t))
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(middleware.RequestID(), middleware.Metrics(c.Metrics))
+	SetupRoutes(app, c)
+
+	const admin, reader = "sb_admin_YWRtaW4", "sb_reader_cmVhZA"
+	issueKey(t, c, &auth.Key{ID: "admin", Name: "prometheus", Scopes: auth.ScopeAdmin}, admin)
+	issueKey(t, c, &auth.Key{ID: "reader", Name: "reader", Scopes: auth.ScopeRead + "," + auth.ScopeWrite}, reader)
+	for key, want := range map[string]int{"": http.StatusUnauthorized, reader: http.StatusForbidden} {
+		if resp := getWithKey(t, app, "/metrics", key); resp.StatusCode != want {
+			t.Errorf("metrics with key %q: status %d, want %d", key, resp.StatusCode, want)
+		}
+	}
+	// The guard rejects this before its route runs.
+	getWithKey(t, app, "/api/match/11368591", "sb_reader_d3Jvbmc")
+
+	for _, path := range []string{
+		"/api/match/11368591",
+		"/api/match/11368707",
+		"/api/match/1",
+		"/api/player-season-stat?uniqueTournamentID=8&seasonID=52376",
+		"/no/such/route",
+	} {
+		get(t, app, path, nil)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("X-API-Key", admin)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d: %s", resp.StatusCode, body)
+	}
+
+	for _, series := range []string{
+		`statsbanger_http_requests_total{method="GET",route="/api/match/:matchID",status="200"} 2`,
+		`statsbanger_http_requests_total{method="GET",route="/api/match/:matchID",status="404"} 1`,
+		`statsbanger_http_requests_total{method="GET",route="/api/match/:matchID",status="401"} 1`,
+		`statsbanger_http_requests_total{method="GET",route="/metrics",status="403"} 1`,
+		`statsbanger_http_request_errors_total{class="4xx",method="GET",route="/api/match/:matchID"} 2`,
+		`statsbanger_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`statsbanger_http_request_duration_seconds_count{method="GET",route="/api/player-season-stat/"} 1`,
+		`statsbanger_db_query_duration_seconds_count{operation="query",repository="matches"} 7`,
+		`statsbanger_db_query_duration_seconds_count{operation="row",repository="player_season_stats"} 1`,
+		`go_sql_max_open_connections{db_name="sqlite"}`,
+	} {
+		if !strings.Contains(string(body), series) {
+			t.Errorf("missing %s", series)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package routes
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"html"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/plinphon/StatsBanger/backend/api/docs"
+	"github.com/plinphon/StatsBanger/backend/openapi"
+)
+
+var fiberParam = regexp.MustCompile(`:(\w+)`)
+
+// specPath converts a fiber route pattern to an OpenAPI path template.
+func specPath(route string) string {
+	path := fiberParam.ReplaceAllString(route, "{$1}")
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	app := newTestApp(t)
+	spec := openapi.Spec()
+
+	registered := make(map[string]bool)
+	for _, r := range app.GetRoutes(true) {
+		// fiber registers a HEAD route alongside every GET.
+		if r.Method == http.MethodHead {
+			continue
+		}
+		path := specPath(r.Path)
+		registered[r.Method+" "+path] = true
+		if spec.Operation(r.Method, path) == nil {
+			t.Errorf("%s %s is not described by the OpenAPI document", r.Method, path)
+		}
+	}
+
+	for path, item := range spec.Paths {
+		for method, op := range *item {
+			if !registered[strings.ToUpper(method)+" "+path] {
+				t.Errorf("the OpenAPI document describes %s %s, which is not routed", strings.ToUpper(method), path)
+			}
+			for _, p := range op.Parameters {
+				if p.In == "path" && !strings.Contains(path, "{"+p.Name+"}") {
+					t.Errorf("%s %s: path parameter %s is not in the path", method, path, p.Name)
+				}
+			}
+		}
+	}
+}
+
+func TestOpenAPIRoute(t *testing.T) {
+	app := newTestApp(t)
+
+	var doc openapi.Document
+	mustGet(t, app, "/api/openapi.json", &doc)
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("openapi = %q", doc.OpenAPI)
+	}
+
+	op := doc.Operation(http.MethodGet, "/api/player-season-stat/top-players")
+	if op == nil {
+		t.Fatal("top-players is not described")
+	}
+	params := make(map[string]*openapi.Parameter)
+	for _, p := range op.Parameters {
+		params[p.Name] = p
+	}
+	if p := params["statFields"]; p == nil || !p.Required || !slices.Contains(p.Schema.Enum, "goals") {
+		t.Errorf("statFields = %+v", p)
+	}
+	if p := params["position"]; p == nil || !slices.Equal(p.Schema.Enum, []string{"D", "F", "G", "M"}) {
+		t.Errorf("position = %+v", p)
+	}
+	if p := params["uniqueTournamentID"]; p == nil || !p.Required || p.In != "query" {
+		t.Errorf("uniqueTournamentID = %+v", p)
+	}
+
+	stats := doc.Components.Schemas["PlayerSeasonStat"].Properties["stats"]
+	if stats == nil || stats.Properties["expected_goals"] == nil {
+		t.Errorf("PlayerSeasonStat.stats does not enumerate the registry: %+v", stats)
+	}
+
+	contentType, page := fetch(t, app, "/api/docs", "")
+	if !strings.HasPrefix(contentType, "text/html") {
+		t.Errorf("GET /api/docs: %s", contentType)
+	}
+
+	// The page loads every asset from the API, pinned by its hash.
+	assets := docsAsset.FindAllStringSubmatch(string(page), -1)
+	if len(assets) != len(docs.Assets) {
+		t.Fatalf("the docs page loads %d assets, want %d", len(assets), len(docs.Assets))
+	}
+	for _, m := range assets {
+		_, body := fetch(t, app, m[1], "")
+		sum := sha512.Sum384(body)
+		if want := "sha384-" + base64.StdEncoding.EncodeToString(sum[:]); html.UnescapeString(m[2]) != want {
+			t.Errorf("%s: integrity %s, want %s", m[1], m[2], want)
+		}
+	}
+	if op := doc.Operation(http.MethodGet, "/api/docs/{asset}"); op == nil || !slices.Equal(op.Parameters[0].Schema.Enum, docs.Assets) {
+		t.Errorf("the OpenAPI document does not list the docs assets %v", docs.Assets)
+	}
+}
+
+var docsAsset = regexp.MustCompile(`(?:href|src)="(/api/docs/[^"]+)" integrity="([^"]+)"`)
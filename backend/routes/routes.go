@@ -3,7 +3,18 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/plinphon/StatsBanger/backend/auth"
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/container"
+	"github.com/plinphon/StatsBanger/backend/middleware"
+
+	docs "github.com/plinphon/StatsBanger/backend/api/docs"
+	export "github.com/plinphon/StatsBanger/backend/api/export"
+	graph "github.com/plinphon/StatsBanger/backend/api/graph"
+	health "github.com/plinphon/StatsBanger/backend/api/health"
 	matches "github.com/plinphon/StatsBanger/backend/api/matches"
+	meta "github.com/plinphon/StatsBanger/backend/api/meta"
+	tournament "github.com/plinphon/StatsBanger/backend/api/tournament"
 
 	team "github.com/plinphon/StatsBanger/backend/api/team/info"
 	teamMatchStat "github.com/plinphon/StatsBanger/backend/api/team/match"
@@ -14,103 +25,133 @@ import (
 	playerSeasonStat "github.com/plinphon/StatsBanger/backend/api/player/season"
 )
 
-func SetupRoutes(app fiber.Router) {
-	api := app.Group("/api")
+// AppConfig returns the fiber configuration for serving the routes under
+// cfg. Requests take their client IP, and so their anonymous rate limit,
+// from the proxy header only when they come from a trusted proxy.
+func AppConfig(cfg *config.Config) fiber.Config {
+	return fiber.Config{
+		ErrorHandler:            middleware.ErrorHandler,
+		ProxyHeader:             cfg.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.TrustedProxies,
+		EnableIPValidation:      true,
+	}
+}
+
+func SetupRoutes(app fiber.Router, c *container.Container) {
+	RegisterHealthRoutes(app, c)
+	// The docs come before the guard of the /api group, so they need no
+	// key even when anonymous reads are disabled.
+	RegisterDocsRoutes(app.Group("/api"))
+
+	// /api, /graphql and /metrics share one set of rate limits.
+	authenticate := middleware.Authenticate(auth.NewAuthenticator(c.Keys), c.Config.AnonymousRateLimit)
+	guard := []fiber.Handler{authenticate}
+	if !c.Config.AnonymousReads {
+		guard = append(guard, middleware.RequireScope(auth.ScopeRead))
+	}
+	RegisterGraphQLRoutes(app, c, guard...)
+	// The metrics name the routes and load of the server, so only admin
+	// keys may read them.
+	app.Get("/metrics", authenticate, middleware.RequireScope(auth.ScopeAdmin), c.Metrics.Handler())
+
+	api := app.Group("/api", guard...)
 
-	RegisterMatchRoutes(api)
+	RegisterTournamentRoutes(api, c)
+	RegisterMatchRoutes(api, c)
 
-	RegisterTeamRoutes(api)
-	RegisterTeamMatchStatRoutes(api)
-	RegisterTeamSeasonStatRoutes(api)
+	RegisterTeamRoutes(api, c)
+	RegisterTeamMatchStatRoutes(api, c)
+	RegisterTeamSeasonStatRoutes(api, c)
 
-	RegisterPlayerRoutes(api)
-	RegisterPlayerMatchStatRoutes(api)
-	RegisterPlayerSeasonStatRoutes(api)
+	RegisterPlayerRoutes(api, c)
+	RegisterPlayerMatchStatRoutes(api, c)
+	RegisterPlayerSeasonStatRoutes(api, c)
 
+	RegisterExportRoutes(api, c)
+	RegisterMetaRoutes(api, c)
 }
 
-func RegisterMatchRoutes(router fiber.Router) {
+func RegisterTournamentRoutes(router fiber.Router, c *container.Container) {
+	service := tournament.NewTournamentService(c.Tournaments)
+	controller := tournament.NewTournamentController(service)
 
-	repo, err := matches.NewMatchRepository("laligaDB.db")
-	if err != nil {
-		panic(err)
-	}
+	tournaments := router.Group("/tournaments")
+	tournaments.Get("/", controller.GetTournaments)
+	tournaments.Get("/:tournamentID/seasons", controller.GetSeasons)
+}
 
-	service := matches.NewMatchService(repo)
+func RegisterMatchRoutes(router fiber.Router, c *container.Container) {
+	service := matches.NewMatchService(c.Matches)
 	controller := matches.NewMatchController(service)
 
 	match := router.Group("/match")
 
+	match.Get("/", controller.ListMatches)
 	match.Get("/:matchID", controller.GetMatchByID)
-}
 
-func RegisterTeamMatchStatRoutes(router fiber.Router) {
-	repo, err := teamMatchStat.NewTeamMatchStatRepository("laligaDB.db")
-	if err != nil {
-		panic(err)
-	}
+	write := middleware.RequireScope(auth.ScopeWrite)
+	match.Post("/", write, controller.CreateMatch)
+	match.Put("/:matchID", write, controller.UpsertMatch)
+}
 
-	service := teamMatchStat.NewTeamMatchStatService(repo)
+func RegisterTeamMatchStatRoutes(router fiber.Router, c *container.Container) {
+	service := teamMatchStat.NewTeamMatchStatService(c.TeamMatchStats)
 	controller := teamMatchStat.NewTeamMatchStatController(service)
 
 	stat := router.Group("/team-match-stat")
 	stat.Get("/", controller.GetStatByTeamAndMatchID)
 	stat.Get("/team/:teamID", controller.GetAllMatchesByTeamID)
-}
 
+	write := middleware.RequireScope(auth.ScopeWrite)
+	stat.Post("/", write, controller.CreateStats)
+	stat.Put("/", write, controller.UpsertStats)
+}
 
-func RegisterTeamSeasonStatRoutes(router fiber.Router) {
-	repo, err := teamSeasonStat.NewTeamSeasonStatRepository("laligaDB.db")
-	if err != nil {
-		panic(err)
-	}
 
-	service := teamSeasonStat.NewTeamSeasonStatService(repo)
+func RegisterTeamSeasonStatRoutes(router fiber.Router, c *container.Container) {
+	service := teamSeasonStat.NewTeamSeasonStatService(c.TeamSeasonStats)
 	controller := teamSeasonStat.NewTeamSeasonStatController(service)
 
 	stat := router.Group("/team-season-stat")
 	stat.Get("/", controller.GetTeamStatsWithMeta)
 	stat.Get("/top-teams", controller.GetTopTeamsByStat)
-}
 
+	write := middleware.RequireScope(auth.ScopeWrite)
+	stat.Post("/", write, controller.CreateStats)
+	stat.Put("/", write, controller.UpsertStats)
+}
 
-func RegisterPlayerMatchStatRoutes(router fiber.Router) {
-	repo, err := playerMatchStat.NewPlayerMatchStatRepository("laligaDB.db")
-	if err != nil {
-		panic(err)
-	}
 
-	service := playerMatchStat.NewPlayerMatchStatService(repo)
+func RegisterPlayerMatchStatRoutes(router fiber.Router, c *container.Container) {
+	service := playerMatchStat.NewPlayerMatchStatService(c.PlayerMatchStats)
 	controller := playerMatchStat.NewPlayerMatchStatController(service)
 
 	stat := router.Group("/player-match-stat")
 	stat.Get("/", controller.GetStatsByMatchID)
 	stat.Get("/player/:playerID", controller.GetAllMatchesStatsByPlayerID)
 	stat.Get("/player/:playerID/match/:matchID", controller.GetStatByPlayerAndMatchID)
-}
 
-func RegisterPlayerSeasonStatRoutes(router fiber.Router) {
-	repo, err := playerSeasonStat.NewPlayerSeasonStatRepository("laligaDB.db")
-	if err != nil {
-		panic(err)
-	}
+	write := middleware.RequireScope(auth.ScopeWrite)
+	stat.Post("/", write, controller.CreateStats)
+	stat.Put("/", write, controller.UpsertStats)
+}
 
-	service := playerSeasonStat.NewPlayerSeasonStatService(repo)
+func RegisterPlayerSeasonStatRoutes(router fiber.Router, c *container.Container) {
+	service := playerSeasonStat.NewPlayerSeasonStatService(c.PlayerSeasonStats)
 	controller := playerSeasonStat.NewPlayerSeasonStatController(service)
 
 	stat := router.Group("/player-season-stat")
 	stat.Get("/", controller.GetPlayerStatsWithMeta)
 	stat.Get("/top-players", controller.GetTopPlayersByStat)
-	
-}
 
-func RegisterPlayerRoutes(router fiber.Router) {
-	repo, err := player.NewPlayerRepository("laligaDB.db")
-	if err != nil {
-		panic(err)
-	}
+	write := middleware.RequireScope(auth.ScopeWrite)
+	stat.Post("/", write, controller.CreateStats)
+	stat.Put("/", write, controller.UpsertStats)
+}
 
-	service := player.NewPlayerService(repo)
+func RegisterPlayerRoutes(router fiber.Router, c *container.Container) {
+	service := player.NewPlayerService(c.Players)
 	controller := player.NewPlayerController(service)
 
 	stat := router.Group("/player")
@@ -119,13 +160,8 @@ func RegisterPlayerRoutes(router fiber.Router) {
 	stat.Get("/", controller.SearchPlayersByName)
 }
 
-func RegisterTeamRoutes(router fiber.Router) {
-	repo, err := team.NewTeamRepository("laligaDB.db")
-	if err != nil {
-		panic(err)
-	}
-
-	service := team.NewTeamService(repo)
+func RegisterTeamRoutes(router fiber.Router, c *container.Container) {
+	service := team.NewTeamService(c.Teams)
 	controller := team.NewTeamController(service)
 
 	teamGroup := router.Group("/team")
@@ -133,3 +169,60 @@ func RegisterTeamRoutes(router fiber.Router) {
 	teamGroup.Get("/:teamID", controller.GetTeamByID)
 	teamGroup.Get("/", controller.SearchTeamsByName)
 }
+
+// RegisterExportRoutes serves whole seasons of the stat tables as
+// /export/{table}.parquet and /export/{table}.arrow files.
+func RegisterExportRoutes(router fiber.Router, c *container.Container) {
+	service := export.NewExportService(c.Exports)
+	controller := export.NewExportController(service)
+
+	router.Get("/export/:table.:format", controller.GetTable)
+}
+
+func RegisterMetaRoutes(router fiber.Router, c *container.Container) {
+	controller := meta.NewMetaController(c.DB)
+
+	metaGroup := router.Group("/meta")
+
+	metaGroup.Get("/stats", controller.GetStatEntities)
+	metaGroup.Get("/stats/:entity", controller.GetStatsByEntity)
+	metaGroup.Get("/schema-check", controller.GetSchemaCheck)
+}
+
+// RegisterGraphQLRoutes serves the GraphQL schema at /graphql behind the
+// given handlers.
+func RegisterGraphQLRoutes(router fiber.Router, c *container.Container, handlers ...fiber.Handler) {
+	controller := graph.NewGraphController(&graph.Services{
+		Matches: matches.NewMatchService(c.Matches),
+
+		Teams:           team.NewTeamService(c.Teams),
+		TeamMatchStats:  teamMatchStat.NewTeamMatchStatService(c.TeamMatchStats),
+		TeamSeasonStats: teamSeasonStat.NewTeamSeasonStatService(c.TeamSeasonStats),
+
+		Players:           player.NewPlayerService(c.Players),
+		PlayerMatchStats:  playerMatchStat.NewPlayerMatchStatService(c.PlayerMatchStats),
+		PlayerSeasonStats: playerSeasonStat.NewPlayerSeasonStatService(c.PlayerSeasonStats),
+	}, c.Config.GraphQLMaxRows)
+
+	handlers = append(handlers[:len(handlers):len(handlers)], controller.Execute)
+	router.Get("/graphql", handlers...)
+	router.Post("/graphql", handlers...)
+}
+
+func RegisterHealthRoutes(router fiber.Router, c *container.Container) {
+	controller := health.NewHealthController(c.DB)
+
+	router.Get("/healthz", controller.GetLiveness)
+	router.Get("/readyz", controller.GetReadiness)
+}
+
+// RegisterDocsRoutes serves the OpenAPI document of every route above and
+// a docs page for it, under a router mounted at /api. Routes added here
+// need an entry in openapi.Spec.
+func RegisterDocsRoutes(router fiber.Router) {
+	controller := docs.NewDocsController("/api/openapi.json", "/api/docs")
+
+	router.Get("/openapi.json", controller.GetSpec)
+	router.Get("/docs", controller.GetUI)
+	router.Get("/docs/:asset", controller.GetAsset)
+}
@@ -0,0 +1,314 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/plinphon/StatsBanger/backend/auth"
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/container"
+	"github.com/plinphon/StatsBanger/backend/database"
+	"github.com/plinphon/StatsBanger/backend/fixture"
+	"github.com/plinphon/StatsBanger/backend/middleware"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// testAPIKey is issued with the write scope by newTestApp.
+const testAPIKey = "sb_testwriter_c2VjcmV0"
+
+func newTestApp(t *testing.T) *fiber.App {
+	t.Helper()
+
+	db := fixture.Open(t)
+	c := container.NewWithDB(config.Default(), db)
+	t.Cleanup(func() { c.Close() })
+	issueKey(t, c, &auth.Key{ID: "testwriter", Name: "tests", Scopes: auth.ScopeWrite}, testAPIKey)
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	SetupRoutes(app, c)
+	return app
+}
+
+// get performs a GET against app and decodes a 200 response into out.
+func get(t *testing.T, app *fiber.App, path string, out any) int {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil), -1)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("GET %s: read body: %v", path, err)
+	}
+	if resp.StatusCode == http.StatusOK && out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			t.Fatalf("GET %s: decode %s: %v", path, body, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func mustGet(t *testing.T, app *fiber.App, path string, out any) {
+	t.Helper()
+	if status := get(t, app, path, out); status != http.StatusOK {
+		t.Fatalf("GET %s: status %d, want 200", path, status)
+	}
+}
+
+func issueKey(t *testing.T, c *container.Container, key *auth.Key, token string) {
+	t.Helper()
+	if err := c.Keys.Create(context.Background(), key, token); err != nil {
+		t.Fatalf("issue key %s: %v", key.ID, err)
+	}
+}
+
+func TestMatchRoutes(t *testing.T) {
+	app := newTestApp(t)
+
+	var match models.Match
+	mustGet(t, app, "/api/match/11368591", &match)
+	if match.Id != fixture.AthleticBetisID || match.HomeTeam.TeamName != "Athletic Bilbao" || match.AwayTeam.TeamName != "Real Betis" {
+		t.Errorf("unexpected match: %+v", match)
+	}
+	if match.HomeScore == nil || *match.HomeScore != 4 {
+		t.Errorf("home score = %v, want 4", match.HomeScore)
+	}
+
+	if status := get(t, app, "/api/match/abc", nil); status != http.StatusBadRequest {
+		t.Errorf("non-numeric match ID: status %d, want 400", status)
+	}
+}
+
+func TestTeamRoutes(t *testing.T) {
+	app := newTestApp(t)
+
+	var team models.Team
+	mustGet(t, app, "/api/team/2825", &team)
+	if team.TeamName != "Athletic Bilbao" || team.HomeStadium != "San Mamés" {
+		t.Errorf("unexpected team: %+v", team)
+	}
+
+	var teams []models.Team
+	mustGet(t, app, "/api/team?name=Real", &teams)
+	if len(teams) != 2 {
+		t.Errorf("search Real: got %d teams, want 2", len(teams))
+	}
+}
+
+func TestPlayerRoutes(t *testing.T) {
+	app := newTestApp(t)
+
+	var player models.Player
+	mustGet(t, app, "/api/player/991011", &player)
+	if player.PlayerName != "Jude Bellingham" || player.Position != "M" {
+		t.Errorf("unexpected player: %+v", player)
+	}
+
+	var players []models.Player
+	mustGet(t, app, "/api/player?name=Isco", &players)
+	if len(players) != 1 || players[0].PlayerId != fixture.IscoID {
+		t.Errorf("search Isco: got %+v", players)
+	}
+}
+
+func TestTeamMatchStatRoutes(t *testing.T) {
+	app := newTestApp(t)
+
+	var stat models.TeamMatchStat
+	mustGet(t, app, "/api/team-match-stat?matchID=11368591&teamID=2825&statFields=ball_possession,expected_goals", &stat)
+	if got := stat.Stats["ball_possession"]; got == nil || *got != 52 {
+		t.Errorf("ball_possession = %v, want 52", got)
+	}
+	if len(stat.Stats) != 2 {
+		t.Errorf("got %d stats, want only the 2 requested", len(stat.Stats))
+	}
+
+	var all models.TeamMatchStat
+	mustGet(t, app, "/api/team-match-stat?matchID=11368591&teamID=2825", &all)
+	if all.Stats["total_shots"] == nil || *all.Stats["total_shots"] != 18 {
+		t.Errorf("total_shots = %v, want 18", all.Stats["total_shots"])
+	}
+
+	var stats []models.TeamMatchStat
+	mustGet(t, app, "/api/team-match-stat/team/2825", &stats)
+	if len(stats) != 3 {
+		t.Errorf("Athletic matches: got %d, want 3", len(stats))
+	}
+
+	if status := get(t, app, "/api/team-match-stat?matchID=x&teamID=2825", nil); status != http.StatusBadRequest {
+		t.Errorf("invalid matchID: status %d, want 400", status)
+	}
+}
+
+func TestTeamSeasonStatRoutes(t *testing.T) {
+	app := newTestApp(t)
+
+	var stats []models.TeamSeasonStat
+	mustGet(t, app, "/api/team-season-stat?uniqueTournamentID=8&seasonID=52376&statFields=goals_scored", &stats)
+	if len(stats) != 3 {
+		t.Fatalf("got %d team season stats, want 3", len(stats))
+	}
+	for _, s := range stats {
+		if s.Team.TeamName == "" || s.Stats["goals_scored"] == nil {
+			t.Errorf("incomplete row: %+v", s)
+		}
+	}
+
+	var filtered []models.TeamSeasonStat
+	mustGet(t, app, "/api/team-season-stat?uniqueTournamentID=8&seasonID=52376&teamID=2816", &filtered)
+	if len(filtered) != 1 || filtered[0].Stats["avg_rating"] == nil {
+		t.Errorf("teamID filter without statFields: got %+v", filtered)
+	}
+
+	var top []models.TopTeamStatResult
+	mustGet(t, app, "/api/team-season-stat/top-teams?statFields=goals_scored&uniqueTournamentID=8&seasonID=52376&limit=1", &top)
+	if len(top) != 1 || top[0].TeamID != fixture.RealMadridID || top[0].StatValue != 87 {
+		t.Errorf("top teams by goals_scored: got %+v", top)
+	}
+
+	if status := get(t, app, "/api/team-season-stat/top-teams?uniqueTournamentID=8&seasonID=52376", nil); status != http.StatusBadRequest {
+		t.Errorf("missing statFields: status %d, want 400", status)
+	}
+}
+
+func TestPlayerMatchStatRoutes(t *testing.T) {
+	app := newTestApp(t)
+
+	var stats []models.PlayerMatchStat
+	mustGet(t, app, "/api/player-match-stat?matchID=11368591&statFields=rating,goals", &stats)
+	if len(stats) != 6 {
+		t.Fatalf("got %d player match stats, want 6", len(stats))
+	}
+	for _, s := range stats {
+		if s.Player.PlayerName == "" || s.Match.HomeTeam.TeamName != "Athletic Bilbao" || s.Stats["rating"] == nil {
+			t.Errorf("incomplete row: %+v", s)
+		}
+	}
+
+	var history []models.PlayerMatchStat
+	mustGet(t, app, "/api/player-match-stat/player/991011", &history)
+	if len(history) != 2 {
+		t.Errorf("Bellingham matches: got %d, want 2", len(history))
+	}
+
+	var one models.PlayerMatchStat
+	mustGet(t, app, "/api/player-match-stat/player/991011/match/11369286", &one)
+	if one.PlayerId != fixture.BellinghamID || one.MatchId != fixture.AthleticRealMadridID {
+		t.Errorf("unexpected row: %+v", one)
+	}
+
+	if status := get(t, app, "/api/player-match-stat?matchID=0", nil); status != http.StatusBadRequest {
+		t.Errorf("matchID=0: status %d, want 400", status)
+	}
+}
+
+func TestPlayerSeasonStatRoutes(t *testing.T) {
+	app := newTestApp(t)
+
+	var stats []models.PlayerSeasonStat
+	mustGet(t, app, "/api/player-season-stat?uniqueTournamentID=8&seasonID=52376&playerID=991011,868812&statFields=goals", &stats)
+	if len(stats) != 2 {
+		t.Fatalf("got %d player season stats, want 2", len(stats))
+	}
+	for _, s := range stats {
+		if s.Player.PlayerName == "" || s.Team.TeamName != "Real Madrid" || s.Stats["goals"] == nil {
+			t.Errorf("incomplete row: %+v", s)
+		}
+	}
+
+	var all []models.PlayerSeasonStat
+	mustGet(t, app, "/api/player-season-stat?uniqueTournamentID=8&seasonID=52376", &all)
+	if len(all) != 9 {
+		t.Errorf("whole season: got %d rows, want 9", len(all))
+	}
+
+	var top []models.TopPlayerStatResult
+	mustGet(t, app, "/api/player-season-stat/top-players?statFields=goals&uniqueTournamentID=8&seasonID=52376&position=F&limit=2", &top)
+	if len(top) != 2 || top[0].PlayerID != fixture.ViniciusID || top[1].PlayerID != fixture.GuruzetaID {
+		t.Errorf("top forwards by goals: got %+v", top)
+	}
+
+	if status := get(t, app, "/api/player-season-stat?uniqueTournamentID=8", nil); status != http.StatusBadRequest {
+		t.Errorf("missing seasonID: status %d, want 400", status)
+	}
+}
+
+func TestMetaRoutes(t *testing.T) {
+	app := newTestApp(t)
+
+	var entities []string
+	mustGet(t, app, "/api/meta/stats", &entities)
+	if len(entities) != 4 {
+		t.Errorf("got entities %v, want 4", entities)
+	}
+
+	var meta struct {
+		Entity string `json:"entity"`
+		Stats  []struct {
+			Field     string   `json:"field"`
+			Label     string   `json:"label"`
+			Unit      string   `json:"unit"`
+			Direction string   `json:"direction"`
+			Max       *float64 `json:"max"`
+		} `json:"stats"`
+	}
+	mustGet(t, app, "/api/meta/stats/team-match", &meta)
+	if meta.Entity != "team-match" || len(meta.Stats) != len(models.TeamMatchStats.Fields()) {
+		t.Fatalf("got %s with %d stats", meta.Entity, len(meta.Stats))
+	}
+	possession := meta.Stats[0]
+	if possession.Field != "ball_possession" || possession.Label != "Ball possession" || possession.Unit != "percentage" || possession.Max == nil || *possession.Max != 100 {
+		t.Errorf("ball_possession: got %+v", possession)
+	}
+
+	if status := get(t, app, "/api/meta/stats/coach-season", nil); status != http.StatusNotFound {
+		t.Errorf("unknown entity: status %d, want 404", status)
+	}
+}
+
+func TestSchemaCheckRoute(t *testing.T) {
+	app := newTestApp(t)
+
+	var report database.SchemaReport
+	mustGet(t, app, "/api/meta/schema-check", &report)
+	if !report.OK || len(report.Tables) != 4 {
+		t.Fatalf("fixture schema: got %+v", report)
+	}
+
+	// Drift the schema: one registered column disappears and an unknown
+	// one shows up.
+	db := fixture.Open(t)
+	for _, stmt := range []string{
+		"ALTER TABLE team_match_stat DROP COLUMN punches",
+		"ALTER TABLE team_match_stat ADD COLUMN pass_streak FLOAT",
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	drifted := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	SetupRoutes(drifted, container.NewWithDB(config.Default(), db))
+
+	mustGet(t, drifted, "/api/meta/schema-check", &report)
+	if report.OK {
+		t.Fatal("drifted schema reported OK")
+	}
+	for _, table := range report.Tables {
+		if table.Table != "team_match_stat" {
+			continue
+		}
+		if len(table.MissingColumns) != 1 || table.MissingColumns[0] != "punches" ||
+			len(table.UnregisteredColumns) != 1 || table.UnregisteredColumns[0] != "pass_streak" {
+			t.Errorf("team_match_stat: got %+v", table)
+		}
+	}
+}
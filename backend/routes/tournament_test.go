@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+func TestTournamentRoutes(t *testing.T) {
+	app := newTestApp(t)
+
+	var tournaments []models.Tournament
+	if status := get(t, app, "/api/tournaments", &tournaments); status != http.StatusOK {
+		t.Fatalf("GET /api/tournaments: status %d", status)
+	}
+	want := models.Tournament{
+		Id: 8, Name: "Laliga", Country: "Spain",
+		StartYear: 2023, EndYear: 2024, Seasons: 1, CurrentSeasonId: 52376, Matches: 4,
+	}
+	if len(tournaments) != 1 || tournaments[0] != want {
+		t.Errorf("GET /api/tournaments: %+v, want [%+v]", tournaments, want)
+	}
+
+	var seasons []models.Season
+	if status := get(t, app, "/api/tournaments/8/seasons", &seasons); status != http.StatusOK {
+		t.Fatalf("GET /api/tournaments/8/seasons: status %d", status)
+	}
+	if len(seasons) != 1 {
+		t.Fatalf("GET /api/tournaments/8/seasons: %d seasons, want 1", len(seasons))
+	}
+	s := seasons[0]
+	if s.Id != 52376 || s.Name != "LaLiga 23/24" || s.Year != "23/24" || s.StartYear != 2023 || s.EndYear != 2024 {
+		t.Errorf("season %+v", s)
+	}
+	if !s.Current || s.Matches != 4 || s.PlayedMatches != 3 || !s.HasPlayerStats || !s.HasTeamStats {
+		t.Errorf("season %+v: want current, 4 matches of which 3 played, and stats", s)
+	}
+	if s.FirstMatch == nil || s.FirstMatch.Unix() != 1691875475 {
+		t.Errorf("season first match %v", s.FirstMatch)
+	}
+
+	for path, want := range map[string]int{
+		"/api/tournaments/1/seasons":   http.StatusNotFound,
+		"/api/tournaments/0/seasons":   http.StatusBadRequest,
+		"/api/tournaments/abc/seasons": http.StatusBadRequest,
+	} {
+		if status := get(t, app, path, nil); status != want {
+			t.Errorf("GET %s: status %d, want %d", path, status, want)
+		}
+	}
+}
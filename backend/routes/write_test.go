@@ -0,0 +1,169 @@
+package routes
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/plinphon/StatsBanger/backend/fixture"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// send performs a write request authorized with key and returns the status
+// and response body.
+func send(t *testing.T, app *fiber.App, method, path, body, key string) (int, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(respBody)
+}
+
+func TestWritesRequireAPIKey(t *testing.T) {
+	app := newTestApp(t)
+
+	match := `{"id": 1, "uniqueTournamentId": 8, "seasonId": 52376, "homeTeamId": 2816, "awayTeamId": 2825}`
+	for _, key := range []string{"", "wrong-key"} {
+		if status, _ := send(t, app, http.MethodPost, "/api/match", match, key); status != http.StatusUnauthorized {
+			t.Errorf("key %q: status %d, want 401", key, status)
+		}
+	}
+}
+
+func TestMatchWrites(t *testing.T) {
+	app := newTestApp(t)
+
+	match := `{"id": 11399999, "uniqueTournamentId": 8, "seasonId": 52376, "matchday": 12,
+		"homeTeamId": 2816, "awayTeamId": 2825, "currentPeriodStartTimestamp": "2023-11-05T20:00:00Z"}`
+
+	if status, body := send(t, app, http.MethodPost, "/api/match", match, testAPIKey); status != http.StatusCreated {
+		t.Fatalf("create: status %d, body %s", status, body)
+	}
+	if status, _ := send(t, app, http.MethodPost, "/api/match", match, testAPIKey); status != http.StatusConflict {
+		t.Errorf("duplicate create: status %d, want 409", status)
+	}
+
+	// The response shows the kick-off that was saved when none is given.
+	noKickOff := `{"id": 11399997, "uniqueTournamentId": 8, "seasonId": 52376, "homeTeamId": 2816, "awayTeamId": 2825}`
+	status, body := send(t, app, http.MethodPost, "/api/match", noKickOff, testAPIKey)
+	if status != http.StatusCreated {
+		t.Fatalf("create without kick-off: status %d, body %s", status, body)
+	}
+	var created, saved models.Match
+	if err := json.Unmarshal([]byte(body), &created); err != nil {
+		t.Fatal(err)
+	}
+	mustGet(t, app, "/api/match/11399997", &saved)
+	if created.CurrentPeriodStartTimestamp.IsZero() || !created.CurrentPeriodStartTimestamp.Equal(saved.CurrentPeriodStartTimestamp) {
+		t.Errorf("created kick-off %v, saved %v", created.CurrentPeriodStartTimestamp, saved.CurrentPeriodStartTimestamp)
+	}
+
+	for _, write := range []struct{ method, path, body string }{
+		{http.MethodPost, "/api/match", `{"id": 11399996, "uniqueTournamentId": 8, "seasonId": 52376, "homeTeamId": 2816, "awayTeamId": 1}`},
+		{http.MethodPut, "/api/match/11399996", `{"uniqueTournamentId": 8, "seasonId": 1, "homeTeamId": 2816, "awayTeamId": 2825}`},
+	} {
+		if status, body := send(t, app, write.method, write.path, write.body, testAPIKey); status != http.StatusBadRequest {
+			t.Errorf("%s %s with an unknown reference: status %d, want 400 (%s)", write.method, write.path, status, body)
+		}
+	}
+
+	sameTeams := `{"id": 11399998, "uniqueTournamentId": 8, "seasonId": 52376, "homeTeamId": 2816, "awayTeamId": 2816}`
+	if status, _ := send(t, app, http.MethodPost, "/api/match", sameTeams, testAPIKey); status != http.StatusBadRequest {
+		t.Errorf("same home and away team: status %d, want 400", status)
+	}
+
+	played := `{"uniqueTournamentId": 8, "seasonId": 52376, "matchday": 12, "homeTeamId": 2816, "awayTeamId": 2825,
+		"homeScore": 1, "awayScore": 1, "currentPeriodStartTimestamp": "2023-11-05T20:00:00Z"}`
+	if status, body := send(t, app, http.MethodPut, "/api/match/11399999", played, testAPIKey); status != http.StatusOK {
+		t.Fatalf("upsert: status %d, body %s", status, body)
+	}
+
+	var got models.Match
+	mustGet(t, app, "/api/match/11399999", &got)
+	if got.HomeScore == nil || *got.HomeScore != 1 || got.HomeTeam.TeamName != "Real Betis" {
+		t.Errorf("after upsert: %+v", got)
+	}
+}
+
+func TestStatWrites(t *testing.T) {
+	app := newTestApp(t)
+
+	// The second row collides with the fixture, so neither row may land.
+	batch := `[
+		{"matchId": 11368620, "playerId": 991011, "teamId": 2829, "match_stats": {"goals": 1, "rating": 7.9}},
+		{"matchId": 11368591, "playerId": 103417, "teamId": 2816, "match_stats": {"goals": 1}}
+	]`
+	if status, _ := send(t, app, http.MethodPost, "/api/player-match-stat", batch, testAPIKey); status != http.StatusConflict {
+		t.Fatalf("batch with duplicate: status %d, want 409", status)
+	}
+	var one models.PlayerMatchStat
+	if status := get(t, app, "/api/player-match-stat/player/991011/match/11368620", &one); status == http.StatusOK && one.PlayerId != 0 {
+		t.Errorf("row from a rolled-back batch was stored: %+v", one)
+	}
+
+	single := `{"matchId": 11368620, "playerId": 991011, "teamId": 2829, "match_stats": {"goals": 1, "rating": 7.9}}`
+	if status, body := send(t, app, http.MethodPost, "/api/player-match-stat", single, testAPIKey); status != http.StatusCreated {
+		t.Fatalf("create player match stat: status %d, body %s", status, body)
+	}
+
+	badField := `{"matchId": 11368620, "playerId": 868812, "teamId": 2829, "match_stats": {"goalz": 1}}`
+	if status, _ := send(t, app, http.MethodPost, "/api/player-match-stat", badField, testAPIKey); status != http.StatusBadRequest {
+		t.Errorf("unknown stat field: status %d, want 400", status)
+	}
+
+	for _, write := range []struct{ method, path, body string }{
+		{http.MethodPost, "/api/player-match-stat", `{"matchId": 11368620, "playerId": 1, "teamId": 2829, "match_stats": {"goals": 1}}`},
+		{http.MethodPut, "/api/team-match-stat", `{"MatchId": 1, "TeamId": 2825, "stats": {"ball_possession": 60}}`},
+		{http.MethodPut, "/api/player-season-stat", `{"playerId": 991011, "teamId": 1, "uniqueTournamentId": 8, "seasonId": 52376, "stats": {"goals": 1}}`},
+		{http.MethodPost, "/api/team-season-stat", `{"teamId": 2816, "uniqueTournamentId": 8, "seasonId": 1, "stats": {"goals_scored": 3}}`},
+	} {
+		if status, body := send(t, app, write.method, write.path, write.body, testAPIKey); status != http.StatusBadRequest {
+			t.Errorf("%s %s with an unknown reference: status %d, want 400 (%s)", write.method, write.path, status, body)
+		}
+	}
+
+	// Upserting one stat leaves the others alone.
+	possession := `{"MatchId": 11368591, "TeamId": 2825, "stats": {"ball_possession": 60}}`
+	if status, body := send(t, app, http.MethodPut, "/api/team-match-stat", possession, testAPIKey); status != http.StatusOK {
+		t.Fatalf("upsert team match stat: status %d, body %s", status, body)
+	}
+	var stat models.TeamMatchStat
+	mustGet(t, app, "/api/team-match-stat?matchID=11368591&teamID=2825&statFields=ball_possession,total_shots", &stat)
+	if *stat.Stats["ball_possession"] != 60 || *stat.Stats["total_shots"] != 18 {
+		t.Errorf("after upsert: ball_possession=%v total_shots=%v", *stat.Stats["ball_possession"], *stat.Stats["total_shots"])
+	}
+
+	season := `{"playerId": 991011, "teamId": 2829, "uniqueTournamentId": 8, "seasonId": 52376, "stats": {"goals": 20}}`
+	if status, _ := send(t, app, http.MethodPost, "/api/player-season-stat", season, testAPIKey); status != http.StatusConflict {
+		t.Errorf("duplicate player season stat: status %d, want 409", status)
+	}
+	if status, body := send(t, app, http.MethodPut, "/api/player-season-stat", season, testAPIKey); status != http.StatusOK {
+		t.Fatalf("upsert player season stat: status %d, body %s", status, body)
+	}
+	var top []models.TopPlayerStatResult
+	mustGet(t, app, "/api/player-season-stat/top-players?statFields=goals&uniqueTournamentID=8&seasonID=52376&limit=1", &top)
+	if len(top) != 1 || top[0].PlayerID != fixture.BellinghamID || top[0].StatValue != 20 {
+		t.Errorf("top scorer after upsert: %+v", top)
+	}
+
+	teamSeason := `{"teamId": 2816, "uniqueTournamentId": 8, "seasonId": 52377, "stats": {"goals_scored": 3, "team_id": 1}}`
+	if status, _ := send(t, app, http.MethodPost, "/api/team-season-stat", teamSeason, testAPIKey); status != http.StatusBadRequest {
+		t.Errorf("identity column inside stats: status %d, want 400", status)
+	}
+}
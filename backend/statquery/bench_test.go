@@ -0,0 +1,136 @@
+package statquery
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/plinphon/StatsBanger/backend/config"
+	"github.com/plinphon/StatsBanger/backend/database"
+	"github.com/plinphon/StatsBanger/backend/fixture"
+)
+
+// EnvBenchDB names a SQLite database to benchmark against, such as a copy
+// of the full La Liga dataset. It is opened read-only. Without it the
+// benchmarks run on the fixture.
+const EnvBenchDB = "STATSBANGER_BENCH_DB"
+
+func openBenchDB(b *testing.B) *gorm.DB {
+	path := os.Getenv(EnvBenchDB)
+	if path == "" {
+		return fixture.Open(b)
+	}
+	cfg := config.Default()
+	cfg.DBPath = path
+	cfg.DBReadOnly = true
+	db, err := database.Open(cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { database.Close(db) })
+	return db
+}
+
+// BenchmarkPlayerSeasonStats loads a whole season with every stat, as
+// /api/player-season-stat does without a field list.
+func BenchmarkPlayerSeasonStats(b *testing.B) {
+	db := openBenchDB(b)
+	filters := []Filter{Eq("unique_tournament_id", fixture.TournamentID), Eq("season_id", fixture.SeasonID)}
+
+	b.Run("joined", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := Find(db, PlayerSeasonStats, Query{Filters: filters}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("preload", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := preloadFind(db, PlayerSeasonStats, []string{"Player", "Team"}, filters); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkPlayerMatchStats loads one match with every stat and the
+// nested match relations.
+func BenchmarkPlayerMatchStats(b *testing.B) {
+	db := openBenchDB(b)
+	filters := []Filter{Eq("match_id", fixture.AthleticBetisID)}
+	preloads := []string{"Match.HomeTeam", "Match.AwayTeam", "Player", "Team"}
+
+	b.Run("joined", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := Find(db, PlayerMatchStats, Query{Filters: filters}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("preload", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := preloadFind(db, PlayerMatchStats, preloads, filters); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// preloadFind is the read path Find replaced: GORM loads the rows with one
+// query per preloaded relation, then a second SELECT of the same rows
+// fetches every stat column in the same order.
+func preloadFind[T any](db *gorm.DB, e Entity[T], preloads []string, filters []Filter) ([]*T, error) {
+	scope, err := e.scope(Query{Filters: filters})
+	if err != nil {
+		return nil, err
+	}
+	tx := db.Model(new(T))
+	for _, preload := range preloads {
+		tx = tx.Preload(preload)
+	}
+	var rows []*T
+	if err := tx.Scopes(scope).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	fields := e.Registry.Fields()
+	keys := e.Registry.Keys
+	var columns []clause.Column
+	for _, name := range append(append([]string(nil), keys...), fields...) {
+		columns = append(columns, clause.Column{Table: e.Registry.Table, Name: name})
+	}
+	result, err := db.Scopes(scope).Clauses(clause.Select{Columns: columns}).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	i := 0
+	key := make([]int, len(keys))
+	values := make([]sql.NullFloat64, len(fields))
+	targets := make([]interface{}, 0, len(keys)+len(fields))
+	for k := range key {
+		targets = append(targets, &key[k])
+	}
+	for v := range values {
+		targets = append(targets, &values[v])
+	}
+	for result.Next() && i < len(rows) {
+		if err := result.Scan(targets...); err != nil {
+			return nil, err
+		}
+		stats := make(map[string]*float64, len(fields))
+		for f, field := range fields {
+			if values[f].Valid {
+				value := values[f].Float64
+				stats[field] = &value
+			}
+		}
+		*e.Stats(rows[i]) = stats
+		i++
+	}
+	return rows, result.Err()
+}
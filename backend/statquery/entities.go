@@ -0,0 +1,31 @@
+package statquery
+
+import "github.com/plinphon/StatsBanger/backend/models"
+
+// PlayerSeasonStats reads player_stat with the player and team.
+var PlayerSeasonStats = Entity[models.PlayerSeasonStat]{
+	Registry: models.PlayerSeasonStats,
+	Joins:    []string{"Player", "Team"},
+	Stats:    func(s *models.PlayerSeasonStat) *map[string]*float64 { return &s.Stats },
+}
+
+// PlayerMatchStats reads player_match_stat with the match, player and team.
+var PlayerMatchStats = Entity[models.PlayerMatchStat]{
+	Registry: models.PlayerMatchStats,
+	Joins:    []string{"Match", "Match.HomeTeam", "Match.AwayTeam", "Player", "Team"},
+	Stats:    func(s *models.PlayerMatchStat) *map[string]*float64 { return &s.Stats },
+}
+
+// TeamSeasonStats reads team_stat with the team.
+var TeamSeasonStats = Entity[models.TeamSeasonStat]{
+	Registry: models.TeamSeasonStats,
+	Joins:    []string{"Team"},
+	Stats:    func(s *models.TeamSeasonStat) *map[string]*float64 { return &s.Stats },
+}
+
+// TeamMatchStats reads team_match_stat with the match and team.
+var TeamMatchStats = Entity[models.TeamMatchStat]{
+	Registry: models.TeamMatchStats,
+	Joins:    []string{"Match", "Match.HomeTeam", "Match.AwayTeam", "Team"},
+	Stats:    func(s *models.TeamMatchStat) *map[string]*float64 { return &s.Stats },
+}
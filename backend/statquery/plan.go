@@ -0,0 +1,226 @@
+package statquery
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// plan is the SELECT list of one Find call: the key columns of the stat
+// table, every column of each joined relation and the requested stats, in
+// the order they are scanned.
+type plan struct {
+	table   string
+	joins   []join
+	columns []planColumn
+	stats   []string
+}
+
+// join is one LEFT JOIN, aliased by its relation path with "__" between
+// the names, such as Match__HomeTeam.
+type join struct {
+	alias string
+	table string
+	on    []clause.Expression
+	// path leads from the stat row to the related struct.
+	path []*schema.Field
+}
+
+// planColumn is a column stored into a model field. Joined columns carry
+// the path to the struct holding field; key columns have none.
+type planColumn struct {
+	column clause.Column
+	field  *schema.Field
+	path   []*schema.Field
+}
+
+func (e Entity[T]) plan(db *gorm.DB, fields []string) (*plan, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, fmt.Errorf("parse %T: %w", new(T), err)
+	}
+	root := stmt.Schema
+	table := e.Registry.Table
+	p := &plan{table: table, stats: fields}
+
+	for _, key := range e.Registry.Keys {
+		field := root.LookUpField(key)
+		if field == nil {
+			return nil, fmt.Errorf("%s has no field for key %s", root.Name, key)
+		}
+		p.columns = append(p.columns, planColumn{column: clause.Column{Table: table, Name: key}, field: field})
+	}
+
+	joined := map[string]join{"": {alias: table, table: table}}
+	schemas := map[string]*schema.Schema{"": root}
+	for _, name := range e.Joins {
+		parentName := ""
+		relName := name
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			parentName, relName = name[:i], name[i+1:]
+		}
+		parent, ok := joined[parentName]
+		if !ok {
+			return nil, fmt.Errorf("join %s: %s must be joined first", name, parentName)
+		}
+		rel, ok := schemas[parentName].Relationships.Relations[relName]
+		if !ok || (rel.Type != schema.BelongsTo && rel.Type != schema.HasOne) {
+			return nil, fmt.Errorf("join %s: not a belongs-to or has-one relation", name)
+		}
+
+		j := join{
+			alias: strings.ReplaceAll(name, ".", "__"),
+			table: rel.FieldSchema.Table,
+			path:  append(append([]*schema.Field(nil), parent.path...), rel.Field),
+		}
+		var first string
+		for _, ref := range rel.References {
+			var own, other *schema.Field
+			switch {
+			case ref.OwnPrimaryKey:
+				own, other = ref.ForeignKey, ref.PrimaryKey
+			case ref.PrimaryValue == "":
+				own, other = ref.PrimaryKey, ref.ForeignKey
+			default:
+				return nil, fmt.Errorf("join %s: polymorphic relations are not supported", name)
+			}
+			j.on = append(j.on, clause.Eq{
+				Column: clause.Column{Table: j.alias, Name: own.DBName},
+				Value:  clause.Column{Table: parent.alias, Name: other.DBName},
+			})
+			if first == "" {
+				first = own.DBName
+			}
+		}
+
+		// The join column goes first: when it is NULL the related row is
+		// missing and pointer relations stay nil.
+		related := rel.FieldSchema
+		ordered := []*schema.Field{related.LookUpField(first)}
+		for _, field := range related.Fields {
+			if field.DBName != "" && field.Readable && field.DBName != first {
+				ordered = append(ordered, field)
+			}
+		}
+		for _, field := range ordered {
+			p.columns = append(p.columns, planColumn{
+				column: clause.Column{Table: j.alias, Name: field.DBName},
+				field:  field,
+				path:   j.path,
+			})
+		}
+
+		p.joins = append(p.joins, j)
+		joined[name] = j
+		schemas[name] = related
+	}
+	return p, nil
+}
+
+func (p *plan) selectClause() clause.Select {
+	columns := make([]clause.Column, 0, len(p.columns)+len(p.stats))
+	for _, c := range p.columns {
+		columns = append(columns, c.column)
+	}
+	for _, stat := range p.stats {
+		columns = append(columns, clause.Column{Table: p.table, Name: stat})
+	}
+	return clause.Select{Columns: columns}
+}
+
+func (p *plan) fromClause() clause.From {
+	from := clause.From{Tables: []clause.Table{{Name: p.table}}}
+	for _, j := range p.joins {
+		from.Joins = append(from.Joins, clause.Join{
+			Type:  clause.LeftJoin,
+			Table: clause.Table{Name: j.table, Alias: j.alias},
+			ON:    clause.Where{Exprs: j.on},
+		})
+	}
+	return from
+}
+
+// scan reads the current row of rows into the struct row and its stats.
+func (p *plan) scan(db *gorm.DB, rows *sql.Rows, row reflect.Value, stats *map[string]*float64) error {
+	ctx := db.Statement.Context
+	values := make([]interface{}, len(p.columns)+len(p.stats))
+	for i, c := range p.columns {
+		values[i] = c.field.NewValuePool.Get()
+	}
+	statValues := make([]sql.NullFloat64, len(p.stats))
+	for i := range statValues {
+		values[len(p.columns)+i] = &statValues[i]
+	}
+	if err := rows.Scan(values...); err != nil {
+		return err
+	}
+
+	for i, c := range p.columns {
+		if dest, ok := target(ctx, row, c.path, !isNull(values[i])); ok {
+			if err := c.field.Set(ctx, dest, values[i]); err != nil {
+				return err
+			}
+		}
+		c.field.NewValuePool.Put(values[i])
+	}
+
+	*stats = make(map[string]*float64, len(p.stats))
+	for i, stat := range p.stats {
+		if statValues[i].Valid {
+			value := statValues[i].Float64
+			(*stats)[stat] = &value
+		}
+	}
+
+	// Preload would have run the AfterFind hooks of the related rows,
+	// which derive fields such as Player.Age.
+	for _, j := range p.joins {
+		if dest, ok := target(ctx, row, j.path, false); ok && dest.CanAddr() {
+			if hook, ok := dest.Addr().Interface().(callbacks.AfterFindInterface); ok {
+				if err := hook.AfterFind(db); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// target follows path from row to the struct a column is stored in. Nil
+// pointer relations are allocated when allocate is set; otherwise target
+// reports false.
+func target(ctx context.Context, row reflect.Value, path []*schema.Field, allocate bool) (reflect.Value, bool) {
+	current := row
+	for _, field := range path {
+		next := field.ReflectValueOf(ctx, current)
+		if next.Kind() == reflect.Ptr {
+			if next.IsNil() {
+				if !allocate {
+					return reflect.Value{}, false
+				}
+				next.Set(reflect.New(next.Type().Elem()))
+			}
+			next = next.Elem()
+		}
+		current = next
+	}
+	return current, true
+}
+
+// isNull reports whether a value scanned through a field's NewValuePool
+// holds NULL. The pools hand out pointers to pointers for plain fields.
+func isNull(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return false
+	}
+	v = v.Elem()
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
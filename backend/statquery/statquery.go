@@ -0,0 +1,223 @@
+// Package statquery reads rows of the stat tables. Every stat table stores a
+// few key columns next to one column per stat; a query loads the keys, the
+// related rows and the requested stat columns in a single joined SELECT.
+package statquery
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+// Entity describes a stat table and the model its rows load into.
+type Entity[T any] struct {
+	Registry *models.StatRegistry
+	// Joins are the relations loaded with every row, such as "Player" or
+	// "Match.HomeTeam". Each must be a belongs-to or has-one relation;
+	// nested paths need their parent listed first.
+	Joins []string
+	// Stats returns the map the stat columns of row are stored in.
+	Stats func(row *T) *map[string]*float64
+}
+
+// Op is a comparison used by a Filter.
+type Op string
+
+const (
+	OpEq  Op = "="
+	OpIn  Op = "in"
+	OpGt  Op = ">"
+	OpGte Op = ">="
+	OpLt  Op = "<"
+	OpLte Op = "<="
+)
+
+// Filter restricts a query to rows whose Field compares to Value. Field is
+// a key column or a stat of the entity.
+type Filter struct {
+	Field string
+	Op    Op
+	Value interface{}
+}
+
+func Eq(field string, value interface{}) Filter  { return Filter{field, OpEq, value} }
+func In(field string, values interface{}) Filter { return Filter{field, OpIn, values} }
+func Gt(field string, value interface{}) Filter  { return Filter{field, OpGt, value} }
+func Gte(field string, value interface{}) Filter { return Filter{field, OpGte, value} }
+func Lt(field string, value interface{}) Filter  { return Filter{field, OpLt, value} }
+func Lte(field string, value interface{}) Filter { return Filter{field, OpLte, value} }
+
+// Order sorts rows by Field, a key column or a stat. Rows without a value
+// sort last in both directions.
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+// Query selects rows of an entity.
+type Query struct {
+	// Fields are the stats loaded into each row. Empty strings are
+	// ignored; an empty list loads every stat.
+	Fields  []string
+	Filters []Filter
+	Order   []Order
+	// Limit caps the number of rows when positive; Offset skips rows.
+	Limit  int
+	Offset int
+}
+
+// Find runs q against e. Rows come back in q.Order, ties broken by the key
+// columns.
+func Find[T any](db *gorm.DB, e Entity[T], q Query) ([]*T, error) {
+	fields, err := e.fields(q.Fields)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := e.scope(q)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := e.plan(db, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := db.Table(e.Registry.Table).
+		Clauses(p.selectClause(), p.fromClause()).
+		Scopes(scope).
+		Rows()
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", e.Registry.Table, err)
+	}
+	defer result.Close()
+
+	var rows []*T
+	for result.Next() {
+		row := new(T)
+		if err := p.scan(db, result, reflect.ValueOf(row).Elem(), e.Stats(row)); err != nil {
+			return nil, fmt.Errorf("scan %s: %w", e.Registry.Table, err)
+		}
+		rows = append(rows, row)
+	}
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", e.Registry.Table, err)
+	}
+	return rows, nil
+}
+
+// First returns the first row matching q, or gorm.ErrRecordNotFound.
+func First[T any](db *gorm.DB, e Entity[T], q Query) (*T, error) {
+	q.Limit, q.Offset = 1, 0
+	rows, err := Find(db, e, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return rows[0], nil
+}
+
+// fields validates the requested stats.
+func (e Entity[T]) fields(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return e.Registry.Fields(), nil
+	}
+	fields := make([]string, 0, len(requested))
+	for _, field := range requested {
+		if field == "" {
+			continue
+		}
+		if !e.Registry.Has(field) {
+			return nil, fmt.Errorf("%w: %s", models.ErrInvalidStatField, field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// column checks that field can be filtered or ordered on.
+func (e Entity[T]) column(field string) (clause.Column, error) {
+	if !e.Registry.Has(field) && !isKey(e.Registry, field) {
+		return clause.Column{}, fmt.Errorf("%w: %s", models.ErrInvalidStatField, field)
+	}
+	return clause.Column{Table: e.Registry.Table, Name: field}, nil
+}
+
+// scope returns the filters, order and paging of q as a scope on the table
+// of e, after checking every field they name.
+func (e Entity[T]) scope(q Query) (func(*gorm.DB) *gorm.DB, error) {
+	var conditions []clause.Expression
+	for _, f := range q.Filters {
+		column, err := e.column(f.Field)
+		if err != nil {
+			return nil, err
+		}
+		switch f.Op {
+		case OpEq:
+			conditions = append(conditions, clause.Eq{Column: column, Value: f.Value})
+		case OpIn:
+			conditions = append(conditions, clause.Expr{SQL: "? IN ?", Vars: []interface{}{column, f.Value}})
+		case OpGt:
+			conditions = append(conditions, clause.Gt{Column: column, Value: f.Value})
+		case OpGte:
+			conditions = append(conditions, clause.Gte{Column: column, Value: f.Value})
+		case OpLt:
+			conditions = append(conditions, clause.Lt{Column: column, Value: f.Value})
+		case OpLte:
+			conditions = append(conditions, clause.Lte{Column: column, Value: f.Value})
+		default:
+			return nil, fmt.Errorf("unknown filter operator %q", f.Op)
+		}
+	}
+
+	var order []clause.Expression
+	for _, o := range q.Order {
+		column, err := e.column(o.Field)
+		if err != nil {
+			return nil, err
+		}
+		direction := "ASC"
+		if o.Desc {
+			direction = "DESC"
+		}
+		// NULLS LAST keeps rows without the stat at the bottom on every
+		// dialect; PostgreSQL would otherwise list them first when
+		// sorting descending.
+		order = append(order, clause.Expr{SQL: "? " + direction + " NULLS LAST", Vars: []interface{}{column}})
+	}
+	for _, key := range e.Registry.Keys {
+		order = append(order, clause.Expr{SQL: "?", Vars: []interface{}{clause.Column{Table: e.Registry.Table, Name: key}}})
+	}
+
+	return func(tx *gorm.DB) *gorm.DB {
+		tx = tx.Table(e.Registry.Table)
+		if len(conditions) > 0 {
+			tx = tx.Clauses(clause.Where{Exprs: conditions})
+		}
+		// A single expression: GORM drops earlier OrderBy expressions
+		// when more are merged in.
+		tx = tx.Order(clause.OrderBy{Expression: clause.CommaExpression{Exprs: order}})
+		if q.Limit > 0 {
+			tx = tx.Limit(q.Limit)
+		}
+		if q.Offset > 0 {
+			tx = tx.Offset(q.Offset)
+		}
+		return tx
+	}, nil
+}
+
+func isKey(registry *models.StatRegistry, column string) bool {
+	for _, key := range registry.Keys {
+		if key == column {
+			return true
+		}
+	}
+	return false
+}
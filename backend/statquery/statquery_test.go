@@ -0,0 +1,114 @@
+package statquery
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/plinphon/StatsBanger/backend/fixture"
+	"github.com/plinphon/StatsBanger/backend/models"
+)
+
+func TestFindFiltersOrdersAndPages(t *testing.T) {
+	db := fixture.Open(t)
+
+	query := Query{
+		Fields: []string{"goals", "saves"},
+		Filters: []Filter{
+			Eq("season_id", fixture.SeasonID),
+			In("team_id", []int{fixture.AthleticID, fixture.BetisID}),
+		},
+		Order: []Order{{Field: "goals", Desc: true}},
+	}
+	stats, err := Find(db, PlayerSeasonStats, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{fixture.GuruzetaID, fixture.WillianID, fixture.IscoID, fixture.WilliamsID, fixture.PezzellaID, fixture.SimonID}
+	if len(stats) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(stats), len(want))
+	}
+	for i, s := range stats {
+		if s.PlayerId != want[i] {
+			t.Errorf("row %d: got player %d, want %d", i, s.PlayerId, want[i])
+		}
+		if s.Player.PlayerName == "" || s.Player.Age == 0 || s.Team.TeamName == "" {
+			t.Errorf("row %d: relations not loaded: %+v", i, s)
+		}
+		if _, ok := s.Stats["rating"]; ok {
+			t.Errorf("row %d: unrequested stat loaded", i)
+		}
+	}
+	if goals := stats[0].Stats["goals"]; goals == nil || *goals != 14 {
+		t.Errorf("Guruzeta goals: got %v", goals)
+	}
+	if saves := stats[5].Stats["saves"]; saves == nil || *saves != 84 {
+		t.Errorf("Simón saves: got %v", saves)
+	}
+	if _, ok := stats[0].Stats["saves"]; ok {
+		t.Error("NULL stat should be absent from the map")
+	}
+
+	query.Filters = append(query.Filters, Gte("goals", 6))
+	query.Limit, query.Offset = 2, 1
+	paged, err := Find(db, PlayerSeasonStats, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paged) != 2 || paged[0].PlayerId != fixture.WillianID || paged[1].PlayerId != fixture.IscoID {
+		t.Errorf("paged: got %+v", paged)
+	}
+}
+
+func TestFindNullsLast(t *testing.T) {
+	db := fixture.Open(t)
+
+	for _, desc := range []bool{true, false} {
+		stats, err := Find(db, PlayerMatchStats, Query{
+			Fields:  []string{"saves"},
+			Filters: []Filter{Eq("match_id", fixture.AthleticRealMadridID)},
+			Order:   []Order{{Field: "saves", Desc: desc}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stats) != 6 || stats[0].PlayerId != fixture.SimonID {
+			t.Errorf("desc=%t: keeper should sort first, got %+v", desc, stats)
+		}
+	}
+}
+
+func TestFirst(t *testing.T) {
+	db := fixture.Open(t)
+
+	stat, err := First(db, TeamMatchStats, Query{
+		Filters: []Filter{Eq("match_id", fixture.AthleticBetisID), Eq("team_id", fixture.AthleticID)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Match == nil || stat.Match.HomeTeam.TeamName != "Athletic Bilbao" || stat.Match.AwayTeam.TeamName != "Real Betis" ||
+		stat.Match.CurrentPeriodStartTimestamp.Unix() != 1693171809 || len(stat.Stats) == 0 {
+		t.Errorf("incomplete row: %+v", stat)
+	}
+
+	_, err = First(db, TeamMatchStats, Query{Filters: []Filter{Eq("match_id", fixture.RealMadridBetisID)}})
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("unplayed match: got %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestFindRejectsUnknownFields(t *testing.T) {
+	db := fixture.Open(t)
+
+	for name, q := range map[string]Query{
+		"field":  {Fields: []string{"goals; DROP TABLE player_stat"}},
+		"filter": {Filters: []Filter{Eq("player_name", "Isco")}},
+		"order":  {Order: []Order{{Field: "position"}}},
+	} {
+		if _, err := Find(db, PlayerSeasonStats, q); !errors.Is(err, models.ErrInvalidStatField) {
+			t.Errorf("%s: got %v, want ErrInvalidStatField", name, err)
+		}
+	}
+}